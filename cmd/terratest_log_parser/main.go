@@ -6,10 +6,35 @@
 //   |-> TEST_NAME.log
 //   |-> summary.log
 //   |-> report.xml
+//   |-> failures.md
+//   |-> failures.json
+//   |-> timeouts.json
 // where:
 // - `TEST_NAME.log` is a log for each test run that only includes the relevant logs for that test.
 // - `summary.log` is a summary of all the tests in the suite, including PASS/FAIL information.
 // - `report.xml` is the test summary in junit XML format to be consumed by a CI engine.
+// - `failures.md` and `failures.json` hold, for each failed test, the excerpts of its log that show what went wrong:
+//   failed testify assertions, the last terraform error, panics with their goroutine stack, or else the last lines
+//   before the `--- FAIL` line. They are only written if a test failed. The excerpts are also the failure message of
+//   the test in `report.xml`.
+// - `timeouts.json` lists, for each test binary killed by the timeout of go test (`panic: test timed out after`), the
+//   tests that were still in progress, along with the goroutine stack of each. go test reports no result for these
+//   tests, so they are marked as failed in the reports. It is only written if a timeout was hit.
+//
+// With the `--format` flag, the test summary can be written in other formats as well:
+// - `junit` (the default) writes `report.xml`.
+// - `html` writes `report.html`, a page with the collapsible log of each test, highlighting the failed tests.
+// - `markdown` writes `report.md`, a summary to post as a PR comment or GitHub Actions job summary.
+// - `ctrf` writes `report.ctrf.json`, the test summary in the Common Test Report Format (https://ctrf.io) for dashboards.
+//
+// The input may either be the plain text output of `go test -v`, or the events of `go test -json`, which is detected
+// automatically. Prefer the latter when running tests in parallel: every event carries the name of the test that
+// emitted it, so the output of interleaved tests is attributed exactly instead of heuristically.
+//
+// The logs of the tests are written as they are parsed, so piping a running `go test` into this command shows the log
+// of each test while it runs. With the `--follow` flag, the log file given with `--testlog` is followed like `tail -f`
+// as it grows, until the command is interrupted (ctrl+c) or, with `--follow-idle-timeout`, until nothing is appended
+// for that long. The reports are written at the end, including when interrupted.
 //
 // Certain tradeoffs were made in the decision to implement this functionality as a separate parsing command, as opposed
 // to being built into the logger module as part of `Logf`. Specifically, this implementation avoids the difficulties of
@@ -35,9 +60,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 
 	"github.com/gruntwork-io/go-commons/entrypoint"
 	"github.com/gruntwork-io/go-commons/errors"
@@ -49,15 +79,22 @@ import (
 
 var logger = logging.GetLogger("terratest_log_parser")
 
-const CUSTOM_USAGE_TEXT = `Usage: terratest_log_parser [--help] [--log-level=info] [--testlog=LOG_INPUT] [--outputdir=OUTPUT_DIR]
+const CUSTOM_USAGE_TEXT = `Usage: terratest_log_parser [--help] [--log-level=info] [--testlog=LOG_INPUT] [--outputdir=OUTPUT_DIR] [--format=FORMAT] [--follow]
 
 A tool for parsing parallel terratest output to produce a test summary and to break out the interleaved logs by test for better debuggability.
 
 Options:
    --log-level LEVEL  Set the log level to LEVEL. Must be one of: [panic fatal error warning info debug]
                       (default: "info")
-   --testlog value    Path to file containing test log. If unset will use stdin.
+   --testlog value    Path to file containing test log, either from 'go test -v' or 'go test -json'. If unset will use stdin.
    --outputdir value  Path to directory to output test output to. If unset will use the current directory.
+   --format value     Comma separated list of report formats to write. Must be any of: [ctrf html junit markdown]
+                      (default: "junit")
+   --follow           Follow the test log as it grows, like 'tail -f', until interrupted. The reports are written when
+                      interrupted. When reading from stdin, read until stdin is closed or interrupted.
+   --follow-idle-timeout value
+                      With --follow, stop once nothing has been appended to the test log for this long (e.g., 10m). If
+                      unset, only stop when interrupted.
    --help, -h         show help
 `
 
@@ -71,6 +108,18 @@ func run(cliContext *cli.Context) error {
 	}
 	logger.SetLevel(level)
 
+	formats := []string{}
+	for _, format := range strings.Split(cliContext.String("format"), ",") {
+		format = strings.TrimSpace(format)
+		if format == "" {
+			continue
+		}
+		if _, err := parser.GetReportWriter(format); err != nil {
+			return errors.WithStackTrace(err)
+		}
+		formats = append(formats, format)
+	}
+
 	var file *os.File
 	if filename != "" {
 		logger.Infof("reading from file")
@@ -89,10 +138,35 @@ func run(cliContext *cli.Context) error {
 		logger.Fatalf("Error extracting absolute path of output directory: %s", err)
 	}
 
-	parser.SpawnParsers(logger, file, outputDir)
+	var reader io.Reader = file
+	if cliContext.Bool("follow") {
+		ctx := interruptContext()
+		if filename != "" {
+			logger.Infof("following file until interrupted")
+			reader = parser.NewFollowReader(ctx, file, parser.DefaultFollowPollInterval, cliContext.Duration("follow-idle-timeout"))
+		} else {
+			// The end of stdin is final, so there is nothing to follow, but the reports are still written when interrupted
+			logger.Infof("reading stdin until it is closed or interrupted")
+			reader = parser.NewContextReader(ctx, file)
+		}
+	}
+
+	parser.SpawnParsers(logger, reader, outputDir, formats...)
 	return nil
 }
 
+// interruptContext returns a context that is done once the command is interrupted (ctrl+c) or terminated, so that the
+// reports are still written. Interrupting the command a second time kills it.
+func interruptContext() context.Context {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		logger.Infof("interrupted, stopping")
+		stop()
+	}()
+	return ctx
+}
+
 func main() {
 	app := entrypoint.NewApp()
 	cli.AppHelpTemplate = CUSTOM_USAGE_TEXT
@@ -112,7 +186,7 @@ func main() {
 	logInputFlag := cli.StringFlag{
 		Name:  "testlog, l",
 		Value: "",
-		Usage: "Path to file containing test log. If unset will use stdin.",
+		Usage: "Path to file containing test log, either from 'go test -v' or 'go test -json'. If unset will use stdin.",
 	}
 	outputDirFlag := cli.StringFlag{
 		Name:  "outputdir, o",
@@ -124,10 +198,26 @@ func main() {
 		Value: logrus.InfoLevel.String(),
 		Usage: fmt.Sprintf("Set the log level to `LEVEL`. Must be one of: %v", logrus.AllLevels),
 	}
+	formatFlag := cli.StringFlag{
+		Name:  "format, f",
+		Value: strings.Join(parser.DefaultFormats, ","),
+		Usage: fmt.Sprintf("Comma separated list of report formats to write. Must be any of: %v", parser.ReportFormats()),
+	}
+	followFlag := cli.BoolFlag{
+		Name:  "follow",
+		Usage: "Follow the test log as it grows, like 'tail -f', until interrupted. The reports are written when interrupted. When reading from stdin, read until stdin is closed or interrupted.",
+	}
+	followIdleTimeoutFlag := cli.DurationFlag{
+		Name:  "follow-idle-timeout",
+		Usage: "With --follow, stop once nothing has been appended to the test log for this long (e.g., 10m). If unset, only stop when interrupted.",
+	}
 	app.Flags = []cli.Flag{
 		logLevelFlag,
 		logInputFlag,
 		outputDirFlag,
+		formatFlag,
+		followFlag,
+		followIdleTimeoutFlag,
 	}
 
 	entrypoint.RunApp(app)
@@ -3,9 +3,8 @@ package formatting
 
 import (
 	"fmt"
-	"reflect"
-	"strconv"
-	"strings"
+
+	"github.com/zclconf/go-cty/cty"
 )
 
 // FormatBackendConfigAsArgs formats backend configuration as Terraform CLI args.
@@ -45,97 +44,22 @@ func formatTerraformArgs(vars map[string]interface{}, prefix string, useSpaceAsS
 	return args
 }
 
-// toHclString converts Go values to HCL-formatted strings for Terraform CLI arguments.
-// Handles primitives, slices, and maps. Example: []int{1,2,3} -> "[1, 2, 3]"
-func toHclString(value interface{}, isNested bool) string {
-	if slice, isSlice := tryToConvertToGenericSlice(value); isSlice {
-		return sliceToHclString(slice)
-	} else if m, isMap := tryToConvertToGenericMap(value); isMap {
-		return mapToHclString(m)
-	} else {
-		return primitiveToHclString(value, isNested)
-	}
-}
-
-// tryToConvertToGenericSlice converts any slice type to []interface{} using reflection.
-func tryToConvertToGenericSlice(value interface{}) ([]interface{}, bool) {
-	reflectValue := reflect.ValueOf(value)
-	if reflectValue.Kind() != reflect.Slice {
-		return []interface{}{}, false
-	}
-
-	genericSlice := make([]interface{}, reflectValue.Len())
-
-	for i := 0; i < reflectValue.Len(); i++ {
-		genericSlice[i] = reflectValue.Index(i).Interface()
-	}
-
-	return genericSlice, true
-}
-
-// tryToConvertToGenericMap converts any map[string]T to map[string]interface{} using reflection.
-func tryToConvertToGenericMap(value interface{}) (map[string]interface{}, bool) {
-	reflectValue := reflect.ValueOf(value)
-	if reflectValue.Kind() != reflect.Map {
-		return map[string]interface{}{}, false
-	}
-
-	reflectType := reflect.TypeOf(value)
-	if reflectType.Key().Kind() != reflect.String {
-		return map[string]interface{}{}, false
-	}
-
-	genericMap := make(map[string]interface{}, reflectValue.Len())
-
-	mapKeys := reflectValue.MapKeys()
-	for _, key := range mapKeys {
-		genericMap[key.String()] = reflectValue.MapIndex(key).Interface()
-	}
-
-	return genericMap, true
-}
-
-func sliceToHclString(slice []interface{}) string {
-	hclValues := []string{}
-
-	for _, value := range slice {
-		hclValue := toHclString(value, true)
-		hclValues = append(hclValues, hclValue)
-	}
-
-	return fmt.Sprintf("[%s]", strings.Join(hclValues, ", "))
+// FormatArgValue formats a Go value (see ToCtyValue) as the value of a -var or -backend-config argument. Strings are
+// returned as is, as Terraform takes the values of string variables literally, and all other values as an HCL
+// expression (see ToHclString). Values without an HCL representation are formatted with fmt.Sprintf.
+func FormatArgValue(value interface{}) string {
+	return toHclString(value, false)
 }
 
-func mapToHclString(m map[string]interface{}) string {
-	keyValuePairs := []string{}
-
-	for key, value := range m {
-		keyValuePair := fmt.Sprintf(`"%s" = %s`, key, toHclString(value, true))
-		keyValuePairs = append(keyValuePairs, keyValuePair)
-	}
-
-	return fmt.Sprintf("{%s}", strings.Join(keyValuePairs, ", "))
-}
-
-func primitiveToHclString(value interface{}, isNested bool) string {
-	if value == nil {
-		return "null"
+// toHclString converts Go values to HCL-formatted strings for Terraform CLI arguments.
+// Strings are quoted only if isNested is true. Example: []int{1,2,3} -> "[1, 2, 3]"
+func toHclString(value interface{}, isNested bool) string {
+	ctyValue, err := ToCtyValue(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
 	}
-
-	switch v := value.(type) {
-
-	case bool:
-		return strconv.FormatBool(v)
-
-	case string:
-		// If string is nested in a larger data structure (e.g. list of string, map of string), ensure value is quoted
-		if isNested {
-			return fmt.Sprintf("\"%v\"", v)
-		}
-
-		return fmt.Sprintf("%v", v)
-
-	default:
-		return fmt.Sprintf("%v", v)
+	if !isNested && !ctyValue.IsNull() && ctyValue.Type() == cty.String {
+		return ctyValue.AsString()
 	}
+	return FormatHclValue(ctyValue)
 }
@@ -0,0 +1,471 @@
+package formatting
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// UnsupportedValueError is returned when a Go value has no HCL representation, such as a channel or a function.
+type UnsupportedValueError struct {
+	Path string
+	Type reflect.Type
+}
+
+func (err UnsupportedValueError) Error() string {
+	if err.Path == "" {
+		return fmt.Sprintf("cannot convert value of type %s to HCL", err.Type)
+	}
+	return fmt.Sprintf("cannot convert value of type %s at %s to HCL", err.Type, err.Path)
+}
+
+var (
+	ctyValueType        = reflect.TypeOf(cty.Value{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	jsonNumberType      = reflect.TypeOf(json.Number(""))
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	hclFieldTagPriority = []string{"hcl", "cty", "json"}
+)
+
+// ToCtyValue converts a Go value to the cty value that Terraform would see for it:
+//   - nil, nil pointers and nil interfaces become null; pointers are dereferenced.
+//   - Numbers, strings and bools become the respective primitive. A time.Duration becomes its string (e.g. "1h30m0s"),
+//     and types implementing encoding.TextMarshaler (e.g. time.Time or net.IP) become their text.
+//   - Slices and arrays become tuples, and maps with string keys become objects, so that their elements may be of
+//     different types, as with []interface{} and map[string]interface{}.
+//   - Structs become objects. The name of each attribute is taken from the `hcl`, `cty` or `json` tag of the field, in
+//     that order, or else is the name of the field. Fields tagged `-` and unexported fields are skipped. Fields tagged
+//     `optional` are left out when they are a nil pointer, interface or map, so that Terraform applies the default of
+//     optional object attributes, while an explicit `false`, `0` or `""` is kept. Fields tagged `omitempty` are left
+//     out when they are empty, as with encoding/json. Embedded structs without a tag are flattened, as with
+//     encoding/json.
+//   - cty values are used as is.
+func ToCtyValue(value interface{}) (cty.Value, error) {
+	if value == nil {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
+	return toCtyValue(reflect.ValueOf(value), "")
+}
+
+func toCtyValue(value reflect.Value, path string) (cty.Value, error) {
+	if !value.IsValid() {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
+
+	switch value.Type() {
+	case ctyValueType:
+		return value.Interface().(cty.Value), nil
+	case durationType:
+		return cty.StringVal(time.Duration(value.Int()).String()), nil
+	case jsonNumberType:
+		number, err := cty.ParseNumberVal(value.String())
+		if err != nil {
+			return cty.NilVal, UnsupportedValueError{Path: path, Type: value.Type()}
+		}
+		return number, nil
+	}
+
+	if value.Kind() != reflect.Pointer && value.Kind() != reflect.Interface && value.Type().Implements(textMarshalerType) {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return cty.StringVal(string(text)), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return cty.NullVal(cty.DynamicPseudoType), nil
+		}
+		return toCtyValue(value.Elem(), path)
+
+	case reflect.Bool:
+		return cty.BoolVal(value.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cty.NumberIntVal(value.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cty.NumberUIntVal(value.Uint()), nil
+
+	case reflect.Float32, reflect.Float64:
+		float := value.Float()
+		if math.IsNaN(float) || math.IsInf(float, 0) {
+			return cty.NilVal, UnsupportedValueError{Path: path, Type: value.Type()}
+		}
+		return cty.NumberFloatVal(float), nil
+
+	case reflect.String:
+		return cty.StringVal(value.String()), nil
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return cty.NullVal(cty.DynamicPseudoType), nil
+		}
+		elements := make([]cty.Value, value.Len())
+		for i := range elements {
+			element, err := toCtyValue(value.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			elements[i] = element
+		}
+		return cty.TupleVal(elements), nil
+
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return cty.NilVal, UnsupportedValueError{Path: path, Type: value.Type()}
+		}
+		if value.IsNil() {
+			return cty.NullVal(cty.DynamicPseudoType), nil
+		}
+		attributes := map[string]cty.Value{}
+		iter := value.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			attribute, err := toCtyValue(iter.Value(), joinPath(path, key))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			attributes[key] = attribute
+		}
+		return cty.ObjectVal(attributes), nil
+
+	case reflect.Struct:
+		attributes := map[string]cty.Value{}
+		if err := addStructAttributes(attributes, value, path); err != nil {
+			return cty.NilVal, err
+		}
+		return cty.ObjectVal(attributes), nil
+
+	default:
+		return cty.NilVal, UnsupportedValueError{Path: path, Type: value.Type()}
+	}
+}
+
+// addStructAttributes adds the fields of the given struct to the given attributes. See ToCtyValue for how fields are
+// named and which are left out.
+func addStructAttributes(attributes map[string]cty.Value, value reflect.Value, path string) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, options, hasTag := structFieldTag(field)
+		if name == "-" && len(options) == 0 {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		if field.Anonymous && !hasTag {
+			embedded := fieldValue
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addStructAttributes(attributes, embedded, path); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if options["omitempty"] && fieldValue.IsZero() {
+			continue
+		}
+		if options["optional"] && isNil(fieldValue) {
+			continue
+		}
+
+		attribute, err := toCtyValue(fieldValue, joinPath(path, name))
+		if err != nil {
+			return err
+		}
+		attributes[name] = attribute
+	}
+	return nil
+}
+
+// isNil returns true if the given value is a nil pointer, interface or map.
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map:
+		return value.IsNil()
+	}
+	return false
+}
+
+// structFieldTag returns the name and options from the first of the `hcl`, `cty` and `json` tags the field has.
+func structFieldTag(field reflect.StructField) (string, map[string]bool, bool) {
+	for _, key := range hclFieldTagPriority {
+		tag, hasTag := field.Tag.Lookup(key)
+		if !hasTag {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		options := map[string]bool{}
+		for _, option := range parts[1:] {
+			options[strings.TrimSpace(option)] = true
+		}
+		return parts[0], options, true
+	}
+	return "", nil, false
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// ToHclString converts a Go value (see ToCtyValue) to an HCL expression on a single line, such as `[1, 2, 3]` or
+// `{"key" = "value"}`, as used for the values of -var and -backend-config arguments.
+func ToHclString(value interface{}) (string, error) {
+	ctyValue, err := ToCtyValue(value)
+	if err != nil {
+		return "", err
+	}
+	return FormatHclValue(ctyValue), nil
+}
+
+// FormatHclValue formats the given cty value as an HCL expression on a single line. The keys of objects and maps are
+// sorted and always quoted, and strings are quoted with all special characters escaped.
+func FormatHclValue(value cty.Value) string {
+	var out strings.Builder
+	writeHclValue(&out, value, false, "")
+	return out.String()
+}
+
+// FormatHclFile formats the given variables as the contents of a .tfvars file: one attribute per variable, sorted by
+// name. Unlike FormatHclValue, objects are spread over multiple lines and multi-line strings are written as heredocs.
+func FormatHclFile(vars map[string]interface{}) ([]byte, error) {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out strings.Builder
+	for _, name := range names {
+		value, err := ToCtyValue(vars[name])
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString(hclKey(name, false))
+		out.WriteString(" = ")
+		writeHclValue(&out, value, true, "")
+		out.WriteString("\n")
+	}
+	return hclwrite.Format([]byte(out.String())), nil
+}
+
+// writeHclValue writes the given value as an HCL expression. If multiline is true, objects are written with one
+// attribute per line, indented by the given indent, and multi-line strings as heredocs.
+func writeHclValue(out *strings.Builder, value cty.Value, multiline bool, indent string) {
+	switch {
+	case value.IsNull() || !value.IsKnown():
+		out.WriteString("null")
+
+	case value.Type() == cty.String:
+		if multiline && canBeHeredoc(value.AsString()) {
+			writeHeredoc(out, value.AsString())
+		} else {
+			out.WriteString(quoteHclString(value.AsString()))
+		}
+
+	case value.Type() == cty.Number:
+		out.WriteString(formatNumber(value.AsBigFloat()))
+
+	case value.Type() == cty.Bool:
+		fmt.Fprintf(out, "%t", value.True())
+
+	case value.Type().IsListType() || value.Type().IsSetType() || value.Type().IsTupleType():
+		out.WriteString("[")
+		first := true
+		for iter := value.ElementIterator(); iter.Next(); {
+			_, element := iter.Element()
+			if !first {
+				out.WriteString(", ")
+			}
+			first = false
+			writeHclValue(out, element, multiline, indent)
+		}
+		out.WriteString("]")
+
+	case value.Type().IsMapType() || value.Type().IsObjectType():
+		// The element iterator returns the keys of maps and objects sorted
+		if value.LengthInt() == 0 {
+			out.WriteString("{}")
+			return
+		}
+		out.WriteString("{")
+		first := true
+		for iter := value.ElementIterator(); iter.Next(); {
+			key, element := iter.Element()
+			switch {
+			case multiline:
+				out.WriteString("\n" + indent + "  ")
+			case !first:
+				out.WriteString(", ")
+			}
+			first = false
+			out.WriteString(hclKey(key.AsString(), !multiline))
+			out.WriteString(" = ")
+			writeHclValue(out, element, multiline, indent+"  ")
+		}
+		if multiline {
+			out.WriteString("\n" + indent)
+		}
+		out.WriteString("}")
+
+	default:
+		// Capsule types have no HCL representation
+		out.WriteString(quoteHclString(value.GoString()))
+	}
+}
+
+// hclKey returns the given key of an object as HCL, which is quoted if alwaysQuote is true or if it is not a valid
+// identifier.
+func hclKey(key string, alwaysQuote bool) string {
+	if !alwaysQuote && hclsyntax.ValidIdentifier(key) {
+		return key
+	}
+	return quoteHclString(key)
+}
+
+// quoteHclString quotes the given string as an HCL string literal, escaping quotes, backslashes, control characters
+// and template sequences.
+func quoteHclString(s string) string {
+	var out strings.Builder
+	out.WriteString(`"`)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			out.WriteString(`\"`)
+		case '\\':
+			out.WriteString(`\\`)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\r':
+			out.WriteString(`\r`)
+		case '\t':
+			out.WriteString(`\t`)
+		case '$', '%':
+			out.WriteByte(c)
+			// `${` and `%{` start template sequences, which are escaped by doubling the first character
+			if i+1 < len(s) && s[i+1] == '{' {
+				out.WriteByte(c)
+			}
+		default:
+			if c < 0x20 {
+				fmt.Fprintf(&out, `\u%04x`, c)
+			} else {
+				out.WriteByte(c)
+			}
+		}
+	}
+	out.WriteString(`"`)
+	return out.String()
+}
+
+// canBeHeredoc returns true if the given string can be written as a heredoc, which always ends with a newline.
+func canBeHeredoc(s string) bool {
+	return strings.HasSuffix(s, "\n") && strings.Count(s, "\n") > 1 && !strings.ContainsAny(s, "\r")
+}
+
+// writeHeredoc writes the given string as a heredoc, with a delimiter that does not occur as a line of the string.
+func writeHeredoc(out *strings.Builder, s string) {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	delimiter := "EOT"
+	for i := 0; containsLine(lines, delimiter); i++ {
+		delimiter = fmt.Sprintf("EOT%d", i)
+	}
+
+	out.WriteString("<<" + delimiter + "\n")
+	for _, line := range lines {
+		line = strings.ReplaceAll(line, "${", "$${")
+		line = strings.ReplaceAll(line, "%{", "%%{")
+		out.WriteString(line + "\n")
+	}
+	out.WriteString(delimiter)
+}
+
+func containsLine(lines []string, s string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// formatNumber formats the given number without an exponent and with as many digits as needed, e.g. 42 or 0.1.
+func formatNumber(number *big.Float) string {
+	if number.IsInt() {
+		integer, _ := number.Int(nil)
+		return integer.String()
+	}
+	return number.Text('f', -1)
+}
+
+// FormatVarsJSON formats the given variables as the contents of a .tfvars.json file. Passing variables to Terraform in
+// such a file, rather than as -var arguments, avoids the limits of the OS on the length of the command line.
+func FormatVarsJSON(vars map[string]interface{}) ([]byte, error) {
+	object := map[string]interface{}{}
+	for name, value := range vars {
+		ctyValue, err := ToCtyValue(value)
+		if err != nil {
+			return nil, err
+		}
+		object[name] = ctyToJSON(ctyValue)
+	}
+	return json.MarshalIndent(object, "", "  ")
+}
+
+// ctyToJSON converts the given cty value to a value that encoding/json marshals the same way Terraform would.
+func ctyToJSON(value cty.Value) interface{} {
+	switch {
+	case value.IsNull() || !value.IsKnown():
+		return nil
+	case value.Type() == cty.String:
+		return value.AsString()
+	case value.Type() == cty.Number:
+		return json.Number(formatNumber(value.AsBigFloat()))
+	case value.Type() == cty.Bool:
+		return value.True()
+	case value.Type().IsListType() || value.Type().IsSetType() || value.Type().IsTupleType():
+		elements := []interface{}{}
+		for iter := value.ElementIterator(); iter.Next(); {
+			_, element := iter.Element()
+			elements = append(elements, ctyToJSON(element))
+		}
+		return elements
+	case value.Type().IsMapType() || value.Type().IsObjectType():
+		attributes := map[string]interface{}{}
+		for iter := value.ElementIterator(); iter.Next(); {
+			key, element := iter.Element()
+			attributes[key.AsString()] = ctyToJSON(element)
+		}
+		return attributes
+	default:
+		return value.GoString()
+	}
+}
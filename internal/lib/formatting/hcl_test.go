@@ -0,0 +1,169 @@
+package formatting
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type testTags struct {
+	Key       string  `hcl:"key"`
+	Value     string  `hcl:"value,optional"`
+	Propagate *bool   `hcl:"propagate,optional"`
+	Comment   *string `hcl:"comment,optional"`
+}
+
+type testBase struct {
+	Region string `json:"region"`
+}
+
+type testInstance struct {
+	testBase
+	Name     string            `hcl:"name"`
+	Count    *int              `hcl:"count"`
+	Size     *string           `hcl:"size,optional"`
+	Timeout  time.Duration     `json:"timeout"`
+	Tags     []testTags        `hcl:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Ignored  string            `json:"-"`
+	Untagged bool
+	internal string
+}
+
+func TestToHclStringComplexTypes(t *testing.T) {
+	t.Parallel()
+
+	size := "large"
+	disabled := false
+	tests := []struct {
+		name   string
+		input  interface{}
+		expect string
+	}{
+		{"nil", nil, "null"},
+		{"nil pointer", (*string)(nil), "null"},
+		{"pointer", &size, `"large"`},
+		{"float", 1.5, "1.5"},
+		{"small float", 0.1, "0.1"},
+		{"big uint", uint64(18446744073709551615), "18446744073709551615"},
+		{"duration", 90 * time.Minute, `"1h30m0s"`},
+		{"time", time.Date(2023, 9, 21, 10, 0, 0, 0, time.UTC), `"2023-09-21T10:00:00Z"`},
+		{"json number", json.Number("12.50"), "12.5"},
+		{"quotes and newlines", "say \"hi\"\nC:\\dir", `"say \"hi\"\nC:\\dir"`},
+		{"template sequences", "${var.foo} %{if} $5", `"$${var.foo} %%{if} $5"`},
+		{"typed map of slices", map[string][]int{"b": {2}, "a": {1}}, `{"a" = [1], "b" = [2]}`},
+		{"empty map", map[string]string{}, "{}"},
+		{"nil map", map[string]string(nil), "null"},
+		{"array", [2]bool{true, false}, "[true, false]"},
+		{"mixed list", []interface{}{"a", 1, nil}, `["a", 1, null]`},
+		{"cty value", cty.ListVal([]cty.Value{cty.StringVal("x")}), `["x"]`},
+		{
+			"struct",
+			testInstance{
+				testBase: testBase{Region: "us-east-1"},
+				Name:     "web",
+				Timeout:  time.Minute,
+				Tags:     []testTags{{Key: "env"}, {Key: "team", Value: "infra", Propagate: &disabled}},
+				Ignored:  "ignored",
+				internal: "internal",
+			},
+			`{"Untagged" = false, "count" = null, "name" = "web", "region" = "us-east-1", "tags" = [{"key" = "env", "value" = ""}, {"key" = "team", "propagate" = false, "value" = "infra"}], "timeout" = "1m0s"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := ToHclString(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, result)
+		})
+	}
+}
+
+func TestToHclStringUnsupportedValue(t *testing.T) {
+	t.Parallel()
+
+	_, err := ToHclString(map[string]interface{}{"callback": func() {}})
+	assert.Equal(t, "cannot convert value of type func() at callback to HCL", err.Error())
+
+	// Arguments fall back to fmt.Sprintf for values without an HCL representation
+	assert.Equal(t, "map[1:one]", FormatArgValue(map[int]string{1: "one"}))
+}
+
+func TestFormatHclFile(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]interface{}{
+		"name":      "web",
+		"user_data": "#!/bin/bash\necho \"${HOSTNAME}\"\n",
+		"settings": map[string]interface{}{
+			"enabled":    true,
+			"max-size":   3,
+			"with space": "x",
+			"script":     "line 1\nline 2\n",
+			"nothing":    nil,
+		},
+		"zones": []string{"a", "b"},
+	}
+
+	contents, err := FormatHclFile(vars)
+	require.NoError(t, err)
+	assert.Equal(t, `name = "web"
+settings = {
+  enabled      = true
+  max-size     = 3
+  nothing      = null
+  script       = <<EOT
+line 1
+line 2
+EOT
+  "with space" = "x"
+}
+user_data = <<EOT
+#!/bin/bash
+echo "$${HOSTNAME}"
+EOT
+zones     = ["a", "b"]
+`, string(contents))
+
+	// The file must parse back to the same values
+	file, diags := hclparse.NewParser().ParseHCL(contents, "test.tfvars")
+	require.False(t, diags.HasErrors(), diags.Error())
+	attributes, diags := file.Body.JustAttributes()
+	require.False(t, diags.HasErrors(), diags.Error())
+	for name, value := range vars {
+		expected, err := ToCtyValue(value)
+		require.NoError(t, err)
+		actual, diags := attributes[name].Expr.Value(&hcl.EvalContext{})
+		require.False(t, diags.HasErrors(), diags.Error())
+		assert.True(t, expected.Equals(actual).True(), "%s: expected %#v, got %#v", name, expected, actual)
+	}
+}
+
+func TestFormatVarsJSON(t *testing.T) {
+	t.Parallel()
+
+	contents, err := FormatVarsJSON(map[string]interface{}{
+		"name":    "web",
+		"count":   uint64(18446744073709551615),
+		"timeout": time.Minute,
+		"tags":    []testTags{{Key: "env"}},
+		"nothing": nil,
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "web",
+		"count": 18446744073709551615,
+		"timeout": "1m0s",
+		"tags": [{"key": "env", "value": ""}],
+		"nothing": null
+	}`, string(contents))
+}
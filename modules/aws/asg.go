@@ -105,8 +105,27 @@ func WaitForCapacityE(
 	maxRetries int,
 	sleepBetweenRetries time.Duration,
 ) error {
-	msg, err := retry.DoWithRetryE(
+	return WaitForCapacityContextE(t, context.Background(), asgName, region, maxRetries, sleepBetweenRetries)
+}
+
+// WaitForCapacityContext is like WaitForCapacity, but stops waiting as soon as the given context is done.
+// This will fail the test if there is an error or if the check times out.
+func WaitForCapacityContext(t testing.TestingT, ctx context.Context, asgName string, region string, maxRetries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitForCapacityContextE(t, ctx, asgName, region, maxRetries, sleepBetweenRetries))
+}
+
+// WaitForCapacityContextE is like WaitForCapacityE, but stops waiting as soon as the given context is done.
+func WaitForCapacityContextE(
+	t testing.TestingT,
+	ctx context.Context,
+	asgName string,
+	region string,
+	maxRetries int,
+	sleepBetweenRetries time.Duration,
+) error {
+	msg, err := retry.DoWithRetryContextE(
 		t,
+		ctx,
 		fmt.Sprintf("Waiting for ASG %s to reach desired capacity.", asgName),
 		maxRetries,
 		sleepBetweenRetries,
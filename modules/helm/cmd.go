@@ -4,6 +4,7 @@ import (
 	"slices"
 
 	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/shell"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
@@ -79,6 +80,7 @@ func prepareHelmCommand(t testing.TestingT, options *Options, cmd string, additi
 		args = append(args, getNamespaceArgs(options)...)
 	}
 	args = append(args, additionalArgs...)
+	registerSensitiveValues(options)
 
 	helmCmd := shell.Command{
 		Command:    "helm",
@@ -89,3 +91,15 @@ func prepareHelmCommand(t testing.TestingT, options *Options, cmd string, additi
 	}
 	return helmCmd
 }
+
+// registerSensitiveValues registers the values named in options.SensitiveValues as secrets, so that they are redacted
+// from the logs (see logger.RegisterSecret).
+func registerSensitiveValues(options *Options) {
+	for _, key := range options.SensitiveValues {
+		for _, values := range []map[string]string{options.SetValues, options.SetStrValues, options.SetJsonValues, options.EnvVars} {
+			if value, hasValue := values[key]; hasValue {
+				logger.RegisterSecret(value)
+			}
+		}
+	}
+}
@@ -11,6 +11,7 @@ type Options struct {
 	SetStrValues      map[string]string   // Values that should be set via the command line explicitly as `string` types.
 	SetJsonValues     map[string]string   // Values that should be set via the command line in JSON format.
 	SetFiles          map[string]string   // Values that should be set from a file. These should be file paths. Use to avoid logging secrets.
+	SensitiveValues   []string            // Keys of SetValues, SetStrValues, SetJsonValues and EnvVars whose values are secrets. Their values are redacted from all log output.
 	KubectlOptions    *k8s.KubectlOptions // KubectlOptions to control how to authenticate to kubernetes cluster. `nil` => use defaults.
 	HomePath          string              // The path to the helm home to use when calling out to helm. Empty string means use default ($HOME/.helm).
 	EnvVars           map[string]string   // Environment variables to set when running helm
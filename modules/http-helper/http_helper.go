@@ -3,6 +3,7 @@ package http_helper
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
@@ -19,6 +20,8 @@ type HttpGetOptions struct {
 	Url       string
 	TlsConfig *tls.Config
 	Timeout   int
+	// Context, if set, is attached to every request and stops the *WithRetry functions from retrying once it is done.
+	Context context.Context
 }
 
 type HttpDoOptions struct {
@@ -28,6 +31,16 @@ type HttpDoOptions struct {
 	Headers   map[string]string
 	TlsConfig *tls.Config
 	Timeout   int
+	// Context, if set, is attached to every request and stops the *WithRetry functions from retrying once it is done.
+	Context context.Context
+}
+
+// contextOrBackground returns the given context, or context.Background() if it is nil.
+func contextOrBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
 }
 
 // HttpGet performs an HTTP GET, with an optional pointer to a custom TLS configuration, on the given URL and
@@ -68,7 +81,12 @@ func HttpGetWithOptionsE(t testing.TestingT, options HttpGetOptions) (int, strin
 		Transport: tr,
 	}
 
-	resp, err := client.Get(options.Url)
+	req, err := http.NewRequestWithContext(contextOrBackground(options.Context), http.MethodGet, options.Url, nil)
+	if err != nil {
+		return -1, "", err
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return -1, "", err
 	}
@@ -173,7 +191,7 @@ func HttpGetWithRetryE(t testing.TestingT, url string, tlsConfig *tls.Config, ex
 // HttpGetWithRetryWithOptionsE repeatedly performs an HTTP GET on the given URL until the given status code and body are returned or until max
 // retries has been exceeded.
 func HttpGetWithRetryWithOptionsE(t testing.TestingT, options HttpGetOptions, expectedStatus int, expectedBody string, retries int, sleepBetweenRetries time.Duration) error {
-	_, err := retry.DoWithRetryE(t, fmt.Sprintf("HTTP GET to URL %s", options.Url), retries, sleepBetweenRetries, func() (string, error) {
+	_, err := retry.DoWithRetryContextE(t, contextOrBackground(options.Context), fmt.Sprintf("HTTP GET to URL %s", options.Url), retries, sleepBetweenRetries, func() (string, error) {
 		return "", HttpGetWithValidationWithOptionsE(t, options, expectedStatus, expectedBody)
 	})
 
@@ -206,7 +224,7 @@ func HttpGetWithRetryWithCustomValidationE(t testing.TestingT, url string, tlsCo
 // HttpGetWithRetryWithCustomValidationWithOptionsE repeatedly performs an HTTP GET on the given URL until the given validation function returns true or max retries
 // has been exceeded.
 func HttpGetWithRetryWithCustomValidationWithOptionsE(t testing.TestingT, options HttpGetOptions, retries int, sleepBetweenRetries time.Duration, validateResponse func(int, string) bool) error {
-	_, err := retry.DoWithRetryE(t, fmt.Sprintf("HTTP GET to URL %s", options.Url), retries, sleepBetweenRetries, func() (string, error) {
+	_, err := retry.DoWithRetryContextE(t, contextOrBackground(options.Context), fmt.Sprintf("HTTP GET to URL %s", options.Url), retries, sleepBetweenRetries, func() (string, error) {
 		return "", HttpGetWithCustomValidationWithOptionsE(t, options, validateResponse)
 	})
 
@@ -271,7 +289,7 @@ func HTTPDoWithOptionsE(
 		Transport: tr,
 	}
 
-	req := newRequest(options.Method, options.Url, options.Body, options.Headers)
+	req := newRequest(contextOrBackground(options.Context), options.Method, options.Url, options.Body, options.Headers)
 	resp, err := client.Do(req)
 	if err != nil {
 		return -1, "", err
@@ -358,8 +376,8 @@ func HTTPDoWithRetryWithOptionsE(
 
 	options.Body = nil
 
-	out, err := retry.DoWithRetryE(
-		t, fmt.Sprintf("HTTP %s to URL %s", options.Method, options.Url), retries,
+	out, err := retry.DoWithRetryContextE(
+		t, contextOrBackground(options.Context), fmt.Sprintf("HTTP %s to URL %s", options.Method, options.Url), retries,
 		sleepBetweenRetries, func() (string, error) {
 			options.Body = bytes.NewReader(data)
 			statusCode, out, err := HTTPDoWithOptionsE(t, options)
@@ -430,7 +448,7 @@ func HTTPDoWithValidationRetryWithOptionsE(
 	t testing.TestingT, options HttpDoOptions, expectedStatus int,
 	expectedBody string, retries int, sleepBetweenRetries time.Duration,
 ) error {
-	_, err := retry.DoWithRetryE(t, fmt.Sprintf("HTTP %s to URL %s", options.Method, options.Url), retries,
+	_, err := retry.DoWithRetryContextE(t, contextOrBackground(options.Context), fmt.Sprintf("HTTP %s to URL %s", options.Method, options.Url), retries,
 		sleepBetweenRetries, func() (string, error) {
 			return "", HTTPDoWithValidationWithOptionsE(t, options, expectedStatus, expectedBody)
 		})
@@ -536,8 +554,8 @@ func HTTPDoWithCustomValidationWithOptionsE(t testing.TestingT, options HttpDoOp
 	return nil
 }
 
-func newRequest(method string, url string, body io.Reader, headers map[string]string) *http.Request {
-	req, err := http.NewRequest(method, url, body)
+func newRequest(ctx context.Context, method string, url string, body io.Reader, headers map[string]string) *http.Request {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil
 	}
@@ -2,6 +2,7 @@ package http_helper
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -140,6 +141,22 @@ func TestErrorWithRetry(t *testing.T) {
 	}
 }
 
+func TestContextStopsRetry(t *testing.T) {
+	t.Parallel()
+	ts := getTestServerForFunction(wrongStatusHandler)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	options := HttpGetOptions{Url: ts.URL, Timeout: 10, Context: ctx}
+	start := time.Now()
+	err := HttpGetWithRetryWithOptionsE(t, options, 200, "", 100, time.Minute)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 30*time.Second)
+}
+
 func TestEmptyRequestBodyWithRetryWithOptions(t *testing.T) {
 	t.Parallel()
 	ts := getTestServerForFunction(bodyCopyHandler)
@@ -0,0 +1,419 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// DefaultFieldManager is the field manager that ServerSideApply uses when ApplyOptions does not set one.
+const DefaultFieldManager = "terratest"
+
+// ApplyOptions are the options of server-side apply.
+type ApplyOptions struct {
+	// FieldManager is the name the API server records as the manager of the applied fields. Defaults to
+	// DefaultFieldManager.
+	FieldManager string
+
+	// Force takes over the fields that other managers manage, instead of failing with a conflict.
+	Force bool
+
+	// DryRun has the API server validate, default and admit the objects, including calling admission webhooks, without
+	// persisting them, like kubectl apply --dry-run=server.
+	DryRun bool
+}
+
+// ObjectDiff is the change that server-side apply makes, or would make, to a Kubernetes object.
+type ObjectDiff struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Live is the object in the cluster before the apply, or nil if the apply creates it.
+	Live *unstructured.Unstructured
+
+	// Applied is the object the apply results in.
+	Applied *unstructured.Unstructured
+
+	// Diff is a unified diff from the live to the applied object, in YAML, without the fields that the API server
+	// updates on every write, such as metadata.resourceVersion and metadata.managedFields. It is empty if the apply does
+	// not change the object.
+	Diff string
+}
+
+// Changed returns true if the apply creates or changes the object.
+func (diff ObjectDiff) Changed() bool {
+	return diff.Diff != ""
+}
+
+// ServerSideApply will take in a file path and apply the objects in it to the cluster targeted by KubectlOptions with
+// server-side apply, using the native client rather than kubectl. A nil applyOptions uses the defaults. This returns the
+// applied objects, as the API server persisted them or, with DryRun, would persist them. If there are any errors, fail
+// the test immediately.
+func ServerSideApply(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configPath string) []*unstructured.Unstructured {
+	objects, err := ServerSideApplyE(t, options, applyOptions, configPath)
+	require.NoError(t, err)
+	return objects
+}
+
+// ServerSideApplyE will take in a file path and apply the objects in it to the cluster targeted by KubectlOptions with
+// server-side apply. If the API server rejects an object, this returns an ObjectRejected error with the reason and
+// message of the rejection.
+func ServerSideApplyE(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configPath string) ([]*unstructured.Unstructured, error) {
+	objects, err := ParseManifestFileE(t, configPath)
+	if err != nil {
+		return nil, err
+	}
+	return ServerSideApplyObjectsE(t, options, applyOptions, objects...)
+}
+
+// ServerSideApplyFromString will take in a kubernetes resource config as a string and apply it on the cluster specified
+// by the provided kubectl options with server-side apply. If there are any errors, fail the test immediately.
+func ServerSideApplyFromString(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configData string) []*unstructured.Unstructured {
+	objects, err := ServerSideApplyFromStringE(t, options, applyOptions, configData)
+	require.NoError(t, err)
+	return objects
+}
+
+// ServerSideApplyFromStringE will take in a kubernetes resource config as a string and apply it on the cluster
+// specified by the provided kubectl options with server-side apply.
+func ServerSideApplyFromStringE(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configData string) ([]*unstructured.Unstructured, error) {
+	objects, err := ParseManifestE(t, configData)
+	if err != nil {
+		return nil, err
+	}
+	return ServerSideApplyObjectsE(t, options, applyOptions, objects...)
+}
+
+// ServerSideApplyFromKustomize will take in a kustomization directory path, build it with kubectl kustomize and apply
+// the result to the cluster targeted by KubectlOptions with server-side apply. If there are any errors, fail the test
+// immediately.
+func ServerSideApplyFromKustomize(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configPath string) []*unstructured.Unstructured {
+	objects, err := ServerSideApplyFromKustomizeE(t, options, applyOptions, configPath)
+	require.NoError(t, err)
+	return objects
+}
+
+// ServerSideApplyFromKustomizeE will take in a kustomization directory path, build it with kubectl kustomize and apply
+// the result to the cluster targeted by KubectlOptions with server-side apply.
+func ServerSideApplyFromKustomizeE(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configPath string) ([]*unstructured.Unstructured, error) {
+	objects, err := buildKustomizationE(t, options, configPath)
+	if err != nil {
+		return nil, err
+	}
+	return ServerSideApplyObjectsE(t, options, applyOptions, objects...)
+}
+
+// ServerSideApplyObjects applies the given objects to the cluster targeted by KubectlOptions with server-side apply, in
+// order. Objects without a namespace are applied in the namespace of the options, if they are namespaced. If there are
+// any errors, fail the test immediately.
+func ServerSideApplyObjects(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, objects ...*unstructured.Unstructured) []*unstructured.Unstructured {
+	applied, err := ServerSideApplyObjectsE(t, options, applyOptions, objects...)
+	require.NoError(t, err)
+	return applied
+}
+
+// ServerSideApplyObjectsE applies the given objects to the cluster targeted by KubectlOptions with server-side apply,
+// in order, stopping at the first object that fails. Note that with DryRun, objects in a namespace that the same
+// objects create are rejected, as the namespace is not persisted.
+func ServerSideApplyObjectsE(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, objects ...*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	applier, err := newServerSideApplierE(t, options, applyOptions)
+	if err != nil {
+		return nil, err
+	}
+	applied := []*unstructured.Unstructured{}
+	for _, object := range objects {
+		result, err := applier.apply(object)
+		if err != nil {
+			return applied, err
+		}
+		applied = append(applied, result)
+	}
+	return applied, nil
+}
+
+// ServerSideDiff will take in a file path and return what applying the objects in it to the cluster targeted by
+// KubectlOptions with server-side apply would change, like kubectl diff --server-side. The objects are applied with
+// DryRun, so nothing changes in the cluster. If there are any errors, fail the test immediately.
+func ServerSideDiff(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configPath string) []ObjectDiff {
+	diffs, err := ServerSideDiffE(t, options, applyOptions, configPath)
+	require.NoError(t, err)
+	return diffs
+}
+
+// ServerSideDiffE will take in a file path and return what applying the objects in it to the cluster targeted by
+// KubectlOptions with server-side apply would change.
+func ServerSideDiffE(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configPath string) ([]ObjectDiff, error) {
+	objects, err := ParseManifestFileE(t, configPath)
+	if err != nil {
+		return nil, err
+	}
+	return ServerSideDiffObjectsE(t, options, applyOptions, objects...)
+}
+
+// ServerSideDiffFromString will take in a kubernetes resource config as a string and return what applying it on the
+// cluster specified by the provided kubectl options with server-side apply would change. If there are any errors, fail
+// the test immediately.
+func ServerSideDiffFromString(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configData string) []ObjectDiff {
+	diffs, err := ServerSideDiffFromStringE(t, options, applyOptions, configData)
+	require.NoError(t, err)
+	return diffs
+}
+
+// ServerSideDiffFromStringE will take in a kubernetes resource config as a string and return what applying it on the
+// cluster specified by the provided kubectl options with server-side apply would change.
+func ServerSideDiffFromStringE(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configData string) ([]ObjectDiff, error) {
+	objects, err := ParseManifestE(t, configData)
+	if err != nil {
+		return nil, err
+	}
+	return ServerSideDiffObjectsE(t, options, applyOptions, objects...)
+}
+
+// ServerSideDiffFromKustomize will take in a kustomization directory path and return what applying it to the cluster
+// targeted by KubectlOptions with server-side apply would change. If there are any errors, fail the test immediately.
+func ServerSideDiffFromKustomize(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configPath string) []ObjectDiff {
+	diffs, err := ServerSideDiffFromKustomizeE(t, options, applyOptions, configPath)
+	require.NoError(t, err)
+	return diffs
+}
+
+// ServerSideDiffFromKustomizeE will take in a kustomization directory path and return what applying it to the cluster
+// targeted by KubectlOptions with server-side apply would change.
+func ServerSideDiffFromKustomizeE(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, configPath string) ([]ObjectDiff, error) {
+	objects, err := buildKustomizationE(t, options, configPath)
+	if err != nil {
+		return nil, err
+	}
+	return ServerSideDiffObjectsE(t, options, applyOptions, objects...)
+}
+
+// ServerSideDiffObjects returns what applying the given objects to the cluster targeted by KubectlOptions with
+// server-side apply would change. If there are any errors, fail the test immediately.
+func ServerSideDiffObjects(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, objects ...*unstructured.Unstructured) []ObjectDiff {
+	diffs, err := ServerSideDiffObjectsE(t, options, applyOptions, objects...)
+	require.NoError(t, err)
+	return diffs
+}
+
+// ServerSideDiffObjectsE returns what applying the given objects to the cluster targeted by KubectlOptions with
+// server-side apply would change, stopping at the first object that fails.
+func ServerSideDiffObjectsE(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions, objects ...*unstructured.Unstructured) ([]ObjectDiff, error) {
+	dryRunOptions := ApplyOptions{DryRun: true}
+	if applyOptions != nil {
+		dryRunOptions = *applyOptions
+		dryRunOptions.DryRun = true
+	}
+	applier, err := newServerSideApplierE(t, options, &dryRunOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := []ObjectDiff{}
+	for _, object := range objects {
+		live, err := applier.get(object)
+		if err != nil {
+			return diffs, err
+		}
+		applied, err := applier.apply(object)
+		if err != nil {
+			return diffs, err
+		}
+		diff, err := diffObjects(live, applied)
+		if err != nil {
+			return diffs, err
+		}
+		diffs = append(diffs, ObjectDiff{
+			Kind:      applied.GetKind(),
+			Namespace: applied.GetNamespace(),
+			Name:      applied.GetName(),
+			Live:      live,
+			Applied:   applied,
+			Diff:      diff,
+		})
+	}
+	return diffs, nil
+}
+
+// serverSideApplier applies objects of any kind with server-side apply.
+type serverSideApplier struct {
+	t            testing.TestingT
+	options      *KubectlOptions
+	applyOptions metav1.ApplyOptions
+	client       dynamic.Interface
+	mapper       meta.RESTMapper
+}
+
+func newServerSideApplierE(t testing.TestingT, options *KubectlOptions, applyOptions *ApplyOptions) (*serverSideApplier, error) {
+	client, err := GetDynamicClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := newRESTMapperE(t, options)
+	if err != nil {
+		return nil, err
+	}
+
+	metaApplyOptions := metav1.ApplyOptions{FieldManager: DefaultFieldManager}
+	if applyOptions != nil {
+		if applyOptions.FieldManager != "" {
+			metaApplyOptions.FieldManager = applyOptions.FieldManager
+		}
+		metaApplyOptions.Force = applyOptions.Force
+		if applyOptions.DryRun {
+			metaApplyOptions.DryRun = []string{metav1.DryRunAll}
+		}
+	}
+	return &serverSideApplier{t: t, options: options, applyOptions: metaApplyOptions, client: client, mapper: mapper}, nil
+}
+
+// apply applies the given object and returns the result, along with the object as it was before, or nil if it did not
+// exist.
+func (applier *serverSideApplier) apply(object *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resourceClient, object, err := applier.resourceClientFor(object)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := resourceClient.Apply(context.Background(), object.GetName(), object, applier.applyOptions)
+	if err != nil {
+		return nil, newObjectRejectedError(object, err)
+	}
+	dryRunMsg := ""
+	if len(applier.applyOptions.DryRun) > 0 {
+		dryRunMsg = " (server dry run)"
+	}
+	applier.options.Logger.Logf(applier.t, "Applied %s %s%s", applied.GetKind(), objectRef(applied.GetNamespace(), applied.GetName()), dryRunMsg)
+	return applied, nil
+}
+
+// get returns the given object as it currently is in the cluster, or nil if it does not exist.
+func (applier *serverSideApplier) get(object *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resourceClient, object, err := applier.resourceClientFor(object)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := resourceClient.Get(context.Background(), object.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return live, err
+}
+
+// resourceClientFor returns the dynamic client for the resource of the given object, along with the object, in the
+// namespace of the options if it is namespaced and does not have a namespace.
+func (applier *serverSideApplier) resourceClientFor(object *unstructured.Unstructured) (dynamic.ResourceInterface, *unstructured.Unstructured, error) {
+	gvk := object.GroupVersionKind()
+	mapping, err := getRESTMapping(applier.mapper, gvk)
+	if meta.IsNoMatchError(err) {
+		// The kind may be defined by a CustomResourceDefinition that was applied after the mapper was created
+		applier.mapper, err = newRESTMapperE(applier.t, applier.options)
+		if err != nil {
+			return nil, nil, err
+		}
+		mapping, err = getRESTMapping(applier.mapper, gvk)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return applier.client.Resource(mapping.Resource), object, nil
+	}
+	if object.GetNamespace() == "" {
+		namespace := applier.options.Namespace
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+		object = object.DeepCopy()
+		object.SetNamespace(namespace)
+	}
+	return applier.client.Resource(mapping.Resource).Namespace(object.GetNamespace()), object, nil
+}
+
+// buildKustomizationE builds the kustomization in the given directory with kubectl kustomize and returns the resulting
+// objects.
+func buildKustomizationE(t testing.TestingT, options *KubectlOptions, configPath string) ([]*unstructured.Unstructured, error) {
+	output, err := shell.RunCommandAndGetStdOutE(t, newKubectlCommand(options, "kustomize", configPath))
+	if err != nil {
+		return nil, err
+	}
+	return ParseManifestE(t, output)
+}
+
+// diffObjects returns a unified diff from the live to the applied object, in YAML, or an empty string if the apply does
+// not change the object.
+func diffObjects(live *unstructured.Unstructured, applied *unstructured.Unstructured) (string, error) {
+	liveYAML := ""
+	if live != nil {
+		out, err := yaml.Marshal(withoutVolatileFields(live).Object)
+		if err != nil {
+			return "", err
+		}
+		liveYAML = string(out)
+	}
+	appliedYAML, err := yaml.Marshal(withoutVolatileFields(applied).Object)
+	if err != nil {
+		return "", err
+	}
+	if liveYAML == string(appliedYAML) {
+		return "", nil
+	}
+
+	ref := applied.GetKind() + "/" + objectRef(applied.GetNamespace(), applied.GetName())
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(liveYAML),
+		B:        difflib.SplitLines(string(appliedYAML)),
+		FromFile: "live/" + ref,
+		ToFile:   "applied/" + ref,
+		Context:  3,
+	})
+}
+
+// withoutVolatileFields returns a copy of the given object without the fields that the API server updates on every
+// write, which are noise in a diff.
+func withoutVolatileFields(object *unstructured.Unstructured) *unstructured.Unstructured {
+	object = object.DeepCopy()
+	object.SetManagedFields(nil)
+	object.SetResourceVersion("")
+	object.SetGeneration(0)
+	object.SetUID("")
+	unstructured.RemoveNestedField(object.Object, "metadata", "creationTimestamp")
+	return object
+}
+
+// objectRef returns namespace/name for namespaced objects and name for cluster scoped objects.
+func objectRef(namespace string, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// newObjectRejectedError returns an ObjectRejected error for the given error of the API server, or the error itself if
+// it does not come from the API server, e.g. because the connection failed.
+func newObjectRejectedError(object *unstructured.Unstructured, err error) error {
+	var apiStatus apierrors.APIStatus
+	if !errors.As(err, &apiStatus) {
+		return err
+	}
+	return ObjectRejected{
+		Kind:       object.GetKind(),
+		Namespace:  object.GetNamespace(),
+		Name:       object.GetName(),
+		Status:     apiStatus.Status(),
+		Underlying: err,
+	}
+}
@@ -0,0 +1,166 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestServerSideApplyFromString(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleServerSideApplyYAMLTemplate, uniqueID, "bar")
+	applied := ServerSideApplyFromString(t, options, &ApplyOptions{FieldManager: "terratest-test"}, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+	require.Equal(t, 2, len(applied))
+
+	configMap := GetConfigMap(t, options, "server-side-apply")
+	assert.Equal(t, "bar", configMap.Data["foo"])
+	managers := []string{}
+	for _, managedFields := range configMap.ManagedFields {
+		managers = append(managers, managedFields.Manager)
+	}
+	assert.Contains(t, managers, "terratest-test")
+}
+
+func TestServerSideApplyDryRunDoesNotPersist(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	CreateNamespace(t, options, uniqueID)
+	defer DeleteNamespace(t, options, uniqueID)
+
+	configData := fmt.Sprintf(ExampleServerSideApplyConfigMapYAML, "bar")
+	applied := ServerSideApplyFromString(t, options, &ApplyOptions{DryRun: true}, configData)
+	require.Equal(t, 1, len(applied))
+	assert.Equal(t, uniqueID, applied[0].GetNamespace())
+
+	_, err := GetConfigMapE(t, options, "server-side-apply")
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestServerSideApplyDryRunReturnsObjectRejected(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	CreateNamespace(t, options, uniqueID)
+	defer DeleteNamespace(t, options, uniqueID)
+
+	_, err := ServerSideApplyFromStringE(t, options, &ApplyOptions{DryRun: true}, ExampleInvalidDeploymentYAML)
+	require.Error(t, err)
+
+	var rejected ObjectRejected
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, "Deployment", rejected.Kind)
+	assert.Equal(t, uniqueID, rejected.Namespace)
+	assert.Equal(t, metav1.StatusReasonInvalid, rejected.Status.Reason)
+	assert.Contains(t, rejected.Status.Message, "spec.replicas")
+	assert.True(t, apierrors.IsInvalid(err))
+}
+
+func TestServerSideDiffFromString(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleServerSideApplyYAMLTemplate, uniqueID, "bar")
+	ServerSideApplyFromString(t, options, nil, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	diffs := ServerSideDiffFromString(t, options, nil, fmt.Sprintf(ExampleServerSideApplyConfigMapYAML, "baz"))
+	require.Equal(t, 1, len(diffs))
+	assert.True(t, diffs[0].Changed())
+	assert.Contains(t, diffs[0].Diff, "-  foo: bar")
+	assert.Contains(t, diffs[0].Diff, "+  foo: baz")
+
+	// The diff is a dry run, so the config map did not change
+	configMap := GetConfigMap(t, options, "server-side-apply")
+	assert.Equal(t, "bar", configMap.Data["foo"])
+
+	diffs = ServerSideDiffFromString(t, options, nil, fmt.Sprintf(ExampleServerSideApplyConfigMapYAML, "bar"))
+	require.Equal(t, 1, len(diffs))
+	assert.False(t, diffs[0].Changed())
+}
+
+func TestDiffObjectsIgnoresVolatileFields(t *testing.T) {
+	t.Parallel()
+
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "server-side-apply",
+			"namespace":       "default",
+			"resourceVersion": "1",
+		},
+		"data": map[string]interface{}{"foo": "bar"},
+	}}
+	applied := live.DeepCopy()
+	applied.SetResourceVersion("2")
+
+	diff, err := diffObjects(live, applied)
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+
+	diff, err = diffObjects(nil, applied)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+++ applied/ConfigMap/default/server-side-apply")
+	assert.Contains(t, diff, "+  foo: bar")
+}
+
+const ExampleServerSideApplyConfigMapYAML = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: server-side-apply
+data:
+  foo: %s
+`
+
+const ExampleServerSideApplyYAMLTemplate = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+` + ExampleServerSideApplyConfigMapYAML
+
+const ExampleInvalidDeploymentYAML = `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: invalid-deployment
+spec:
+  replicas: -1
+  selector:
+    matchLabels:
+      app: nginx
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15.7
+`
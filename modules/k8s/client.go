@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
@@ -24,6 +26,43 @@ func GetKubernetesClientE(t testing.TestingT) (*kubernetes.Clientset, error) {
 
 // GetKubernetesClientFromOptionsE returns a Kubernetes API client given a configured KubectlOptions object.
 func GetKubernetesClientFromOptionsE(t testing.TestingT, options *KubectlOptions) (*kubernetes.Clientset, error) {
+	config, err := GetRestConfigFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset, nil
+}
+
+// GetDynamicClientFromOptionsE returns a Kubernetes API client for resources of any kind, including custom resources,
+// given a configured KubectlOptions object.
+func GetDynamicClientFromOptionsE(t testing.TestingT, options *KubectlOptions) (dynamic.Interface, error) {
+	config, err := GetRestConfigFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(config)
+}
+
+// GetDiscoveryClientFromOptionsE returns a Kubernetes API client for discovering the resource kinds the API server
+// supports, given a configured KubectlOptions object.
+func GetDiscoveryClientFromOptionsE(t testing.TestingT, options *KubectlOptions) (*discovery.DiscoveryClient, error) {
+	config, err := GetRestConfigFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return discovery.NewDiscoveryClientForConfig(config)
+}
+
+// GetRestConfigFromOptionsE returns the configuration of Kubernetes API clients given a configured KubectlOptions
+// object: the in-cluster config if InClusterAuth is set, the RestConfig if there is one, and the config of the context
+// in the kubeconfig file otherwise.
+func GetRestConfigFromOptionsE(t testing.TestingT, options *KubectlOptions) (*rest.Config, error) {
 	var err error
 	var config *rest.Config
 
@@ -54,10 +93,5 @@ func GetKubernetesClientFromOptionsE(t testing.TestingT, options *KubectlOptions
 		}
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return nil, err
-	}
-
-	return clientset, nil
+	return config, nil
 }
@@ -7,8 +7,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
@@ -34,12 +34,31 @@ func GetConfigMapE(t testing.TestingT, options *KubectlOptions, configMapName st
 // WaitUntilConfigMapAvailable waits until the configmap is present on the cluster in cases where it is not immediately
 // available (for example, when using ClusterIssuer to request a certificate).
 func WaitUntilConfigMapAvailable(t testing.TestingT, options *KubectlOptions, configMapName string, retries int, sleepBetweenRetries time.Duration) {
+	WaitUntilConfigMapAvailableContext(t, context.Background(), options, configMapName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilConfigMapAvailableE waits until the configmap is present on the cluster, retrying the check for the
+// specified amount of times, sleeping for the provided duration between each try.
+func WaitUntilConfigMapAvailableE(t testing.TestingT, options *KubectlOptions, configMapName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilConfigMapAvailableContextE(t, context.Background(), options, configMapName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilConfigMapAvailableContext is like WaitUntilConfigMapAvailable, but stops waiting as soon as the given context is done.
+// This will fail the test if the context is done or the retries are exhausted.
+func WaitUntilConfigMapAvailableContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, configMapName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilConfigMapAvailableContextE(t, ctx, options, configMapName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilConfigMapAvailableContextE is like WaitUntilConfigMapAvailableE, but stops waiting as soon as the given context is done.
+func WaitUntilConfigMapAvailableContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, configMapName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for configmap %s to be provisioned.", configMapName)
-	message := retry.DoWithRetry(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchConfigMaps(t, options, nameSelector(configMapName)),
 		func() (string, error) {
 			_, err := GetConfigMapE(t, options, configMapName)
 			if err != nil {
@@ -49,5 +68,21 @@ func WaitUntilConfigMapAvailable(t testing.TestingT, options *KubectlOptions, co
 			return "configmap is now available", nil
 		},
 	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for ConfigMap to be provisioned: %s", err)
+		return err
+	}
 	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// watchConfigMaps returns a function that watches the config maps that match the given filters, for waits.
+func watchConfigMaps(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.CoreV1().ConfigMaps(options.Namespace).Watch(ctx, filters)
+	}
 }
@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 
 	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // ListCronJobs list cron jobs in namespace that match provided filters. This will fail the test if there is an error.
@@ -58,12 +58,25 @@ func WaitUntilCronJobSucceed(t testing.TestingT, options *KubectlOptions, cronJo
 // WaitUntilCronJobSucceedE waits until cron job will successfully complete a job, retrying the check for the specified
 // amount of times, sleeping for the provided duration between each try.
 func WaitUntilCronJobSucceedE(t testing.TestingT, options *KubectlOptions, cronJobName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilCronJobSucceedContextE(t, context.Background(), options, cronJobName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilCronJobSucceedContext is like WaitUntilCronJobSucceed, but stops waiting as soon as the given context is done.
+// This will fail the test if there is an error or if the check times out.
+func WaitUntilCronJobSucceedContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, cronJobName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilCronJobSucceedContextE(t, ctx, options, cronJobName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilCronJobSucceedContextE is like WaitUntilCronJobSucceedE, but stops waiting as soon as the given context is done.
+func WaitUntilCronJobSucceedContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, cronJobName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for CronJob %s to successfully schedule container", cronJobName)
-	message, err := retry.DoWithRetryE(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchCronJobs(t, options, nameSelector(cronJobName)),
 		func() (string, error) {
 			job, err := GetCronJobE(t, options, cronJobName)
 			if err != nil {
@@ -88,3 +101,14 @@ func WaitUntilCronJobSucceedE(t testing.TestingT, options *KubectlOptions, cronJ
 func IsCronJobSucceeded(cronJob *batchv1.CronJob) bool {
 	return cronJob.Status.LastScheduleTime != nil
 }
+
+// watchCronJobs returns a function that watches the cron jobs that match the given filters, for waits.
+func watchCronJobs(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.BatchV1().CronJobs(options.Namespace).Watch(ctx, filters)
+	}
+}
@@ -9,8 +9,8 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
@@ -69,13 +69,33 @@ func WaitUntilDeploymentAvailableE(
 	deploymentName string,
 	retries int,
 	sleepBetweenRetries time.Duration,
+) error {
+	return WaitUntilDeploymentAvailableContextE(t, context.Background(), options, deploymentName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilDeploymentAvailableContext is like WaitUntilDeploymentAvailable, but stops waiting as soon as the given context is done.
+// This will fail the test if there is an error or if the check times out.
+func WaitUntilDeploymentAvailableContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, deploymentName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilDeploymentAvailableContextE(t, ctx, options, deploymentName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilDeploymentAvailableContextE is like WaitUntilDeploymentAvailableE, but stops waiting as soon as the given context is done.
+func WaitUntilDeploymentAvailableContextE(
+	t testing.TestingT,
+	ctx context.Context,
+	options *KubectlOptions,
+	deploymentName string,
+	retries int,
+	sleepBetweenRetries time.Duration,
 ) error {
 	statusMsg := fmt.Sprintf("Wait for deployment %s to be provisioned.", deploymentName)
-	message, err := retry.DoWithRetryE(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchDeployments(t, options, nameSelector(deploymentName)),
 		func() (string, error) {
 			deployment, err := GetDeploymentE(t, options, deploymentName)
 			if err != nil {
@@ -110,3 +130,14 @@ func getDeploymentCondition(deploy *appsv1.Deployment, cType appsv1.DeploymentCo
 	}
 	return nil
 }
+
+// watchDeployments returns a function that watches the deployments that match the given filters, for waits.
+func watchDeployments(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.AppsV1().Deployments(options.Namespace).Watch(ctx, filters)
+	}
+}
@@ -0,0 +1,272 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// defaultDumpDirName is the directory in the temporary directory of the system that DumpNamespaceOnFailure dumps to if
+// no artifacts directory is set.
+const defaultDumpDirName = "terratest-namespace-dumps"
+
+// describedKinds are the kinds of objects that DumpNamespace describes with kubectl describe.
+var describedKinds = []string{"deployments", "statefulsets", "daemonsets", "services", "ingresses"}
+
+// cleaner is implemented by Go's testing.T and allows registering a function to run when the test finishes.
+type cleaner interface {
+	Cleanup(func())
+}
+
+// cleanupFailer is implemented by Go's testing.T and additionally allows checking whether the test failed.
+type cleanupFailer interface {
+	cleaner
+	Failed() bool
+}
+
+// DumpNamespaceOnFailure registers a function with t.Cleanup that, if the test failed, dumps the state of the
+// namespace of the given options with DumpNamespace, to <artifacts dir>/<test name>/<namespace>, alongside the other
+// artifacts of the test (see shell.TestArtifactsDir). If the TERRATEST_ARTIFACTS_DIR environment variable (see
+// shell.ArtifactsDirEnvVar) is not set, the artifacts dir is terratest-namespace-dumps in the temporary directory of the
+// system instead. Either way, the path of the dump is logged. This does nothing if the given testing.TestingT does not
+// support Cleanup, as Go's testing.T does.
+//
+// Cleanup functions run after the deferred calls of the test, and in the reverse order they were registered. To dump
+// the namespace before it is deleted, delete it in a function registered with t.Cleanup before calling
+// DumpNamespaceOnFailure, rather than with defer.
+func DumpNamespaceOnFailure(t testing.TestingT, options *KubectlOptions) {
+	tt, ok := t.(cleanupFailer)
+	if !ok {
+		options.Logger.Logf(t, "Not dumping namespace %s on failure: %T does not support Cleanup", options.Namespace, t)
+		return
+	}
+	tt.Cleanup(func() {
+		if !tt.Failed() {
+			return
+		}
+		testDir := shell.TestArtifactsDir(t)
+		if testDir == "" {
+			testDir = shell.TestArtifactsDirIn(t, filepath.Join(os.TempDir(), defaultDumpDirName))
+		}
+		dir := filepath.Join(testDir, options.Namespace)
+		if err := DumpNamespaceE(t, options, dir); err != nil {
+			options.Logger.Logf(t, "Error dumping namespace %s to %s: %s", options.Namespace, dir, err)
+		}
+	})
+}
+
+// DumpNamespace writes the state of the namespace of the given options to the given directory, for debugging. See
+// DumpNamespaceE for what is written. This will fail the test if there is an error.
+func DumpNamespace(t testing.TestingT, options *KubectlOptions, dir string) {
+	require.NoError(t, DumpNamespaceE(t, options, dir))
+}
+
+// DumpNamespaceE writes the state of the namespace of the given options to the given directory, for debugging:
+//   - pods.yaml: the pods, with their statuses, and pods.txt: a summary of their statuses
+//   - logs/<pod>/<container>.log: the logs of every container, including init containers, and
+//     <container>.previous.log: the logs of the previous instance of every container that restarted
+//   - events.txt: the events, oldest first
+//   - describe-<kind>.txt: the output of kubectl describe for deployments, statefulsets, daemonsets, services and
+//     ingresses
+//   - nodes.txt: the conditions of the nodes of the cluster
+//
+// This collects as much as it can, returning all the errors it runs into at the end.
+func DumpNamespaceE(t testing.TestingT, options *KubectlOptions, dir string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	options.Logger.Logf(t, "Dumping namespace %s to %s", options.Namespace, dir)
+
+	var errorsOccurred = new(multierror.Error)
+	errorsOccurred = multierror.Append(errorsOccurred, dumpPodsE(t, options, dir))
+	errorsOccurred = multierror.Append(errorsOccurred, dumpEventsE(t, options, dir))
+	for _, kind := range describedKinds {
+		errorsOccurred = multierror.Append(errorsOccurred, dumpDescribeE(t, options, dir, kind))
+	}
+	errorsOccurred = multierror.Append(errorsOccurred, dumpNodeConditionsE(t, options, dir))
+	return errorsOccurred.ErrorOrNil()
+}
+
+func dumpPodsE(t testing.TestingT, options *KubectlOptions, dir string) error {
+	pods, err := ListPodsE(t, options, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	podsYAML, err := yaml.Marshal(pods)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pods.yaml"), podsYAML, 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pods.txt"), []byte(formatPodStatuses(pods)), 0644); err != nil {
+		return err
+	}
+
+	var errorsOccurred = new(multierror.Error)
+	for i := range pods {
+		errorsOccurred = multierror.Append(errorsOccurred, dumpPodLogsE(t, options, dir, &pods[i]))
+	}
+	return errorsOccurred.ErrorOrNil()
+}
+
+// dumpPodLogsE writes the logs of all the containers of the given pod, as well as those of their previous instances
+// if they restarted.
+func dumpPodLogsE(t testing.TestingT, options *KubectlOptions, dir string, pod *corev1.Pod) error {
+	podDir := filepath.Join(dir, "logs", pod.Name)
+	if err := os.MkdirAll(podDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	var errorsOccurred = new(multierror.Error)
+	for _, status := range statuses {
+		// Containers that never started do not have logs
+		if status.State.Waiting == nil || status.RestartCount > 0 {
+			errorsOccurred = multierror.Append(errorsOccurred, dumpContainerLogsE(t, options, podDir, pod, status.Name, false))
+		}
+		if status.RestartCount > 0 {
+			errorsOccurred = multierror.Append(errorsOccurred, dumpContainerLogsE(t, options, podDir, pod, status.Name, true))
+		}
+	}
+	return errorsOccurred.ErrorOrNil()
+}
+
+func dumpContainerLogsE(t testing.TestingT, options *KubectlOptions, podDir string, pod *corev1.Pod, containerName string, previous bool) error {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return err
+	}
+	logs, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: containerName, Previous: previous}).DoRaw(context.Background())
+	if err != nil {
+		return fmt.Errorf("getting logs of container %s of pod %s: %w", containerName, pod.Name, err)
+	}
+	fileName := containerName + ".log"
+	if previous {
+		fileName = containerName + ".previous.log"
+	}
+	return os.WriteFile(filepath.Join(podDir, fileName), logs, 0644)
+}
+
+func dumpEventsE(t testing.TestingT, options *KubectlOptions, dir string) error {
+	events, err := ListEventsE(t, options, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "events.txt"), []byte(formatEvents(events)), 0644)
+}
+
+func dumpDescribeE(t testing.TestingT, options *KubectlOptions, dir string, kind string) error {
+	output, err := RunKubectlAndGetOutputE(t, options, "describe", kind)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, fmt.Sprintf("describe-%s.txt", kind)), []byte(output), 0644)
+}
+
+func dumpNodeConditionsE(t testing.TestingT, options *KubectlOptions, dir string) error {
+	nodes, err := GetNodesE(t, options)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "nodes.txt"), []byte(formatNodeConditions(nodes)), 0644)
+}
+
+// formatPodStatuses returns a line per pod with its phase, and a line per container with its state, readiness and
+// restarts.
+func formatPodStatuses(pods []corev1.Pod) string {
+	var builder strings.Builder
+	for _, pod := range pods {
+		fmt.Fprintf(&builder, "%s: %s", pod.Name, pod.Status.Phase)
+		if pod.Status.Reason != "" {
+			fmt.Fprintf(&builder, ", reason: %s", pod.Status.Reason)
+		}
+		if pod.Status.Message != "" {
+			fmt.Fprintf(&builder, ", message: %s", pod.Status.Message)
+		}
+		builder.WriteString("\n")
+
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			fmt.Fprintf(&builder, "  %s: %s, ready: %t, restarts: %d\n", status.Name, formatContainerState(status.State), status.Ready, status.RestartCount)
+			if status.RestartCount > 0 {
+				fmt.Fprintf(&builder, "    last state: %s\n", formatContainerState(status.LastTerminationState))
+			}
+		}
+	}
+	return builder.String()
+}
+
+func formatContainerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "running"
+	case state.Waiting != nil:
+		return fmt.Sprintf("waiting (%s: %s)", state.Waiting.Reason, state.Waiting.Message)
+	case state.Terminated != nil:
+		return fmt.Sprintf("terminated with exit code %d (%s: %s)", state.Terminated.ExitCode, state.Terminated.Reason, state.Terminated.Message)
+	default:
+		return "unknown"
+	}
+}
+
+// formatEvents returns a line per event, oldest first.
+func formatEvents(events []corev1.Event) string {
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	var builder strings.Builder
+	for _, event := range events {
+		fmt.Fprintf(
+			&builder,
+			"%s %s %s %s/%s (x%d): %s\n",
+			eventTime(event).UTC().Format("2006-01-02T15:04:05Z"),
+			event.Type,
+			event.Reason,
+			event.InvolvedObject.Kind,
+			event.InvolvedObject.Name,
+			max(event.Count, 1),
+			strings.TrimSpace(event.Message),
+		)
+	}
+	return builder.String()
+}
+
+// eventTime returns when the event last occurred, which, depending on the API the event was created with, is in
+// LastTimestamp, EventTime or the creation time of the event.
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// formatNodeConditions returns a line per node, and a line per condition of the node.
+func formatNodeConditions(nodes []corev1.Node) string {
+	var builder strings.Builder
+	for _, node := range nodes {
+		fmt.Fprintf(&builder, "%s:\n", node.Name)
+		for _, condition := range node.Status.Conditions {
+			fmt.Fprintf(&builder, "  %s: %s, reason: %s, message: %s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+	return builder.String()
+}
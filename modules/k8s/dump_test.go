@@ -0,0 +1,130 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/shell"
+)
+
+func TestDumpNamespace(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleDeploymentYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+	WaitUntilDeploymentAvailable(t, options, "nginx-deployment", 60, 1*time.Second)
+
+	dir := t.TempDir()
+	DumpNamespace(t, options, dir)
+
+	for _, file := range []string{"pods.yaml", "pods.txt", "events.txt", "describe-deployments.txt", "describe-services.txt", "describe-ingresses.txt", "nodes.txt"} {
+		assert.FileExists(t, filepath.Join(dir, file))
+	}
+	pods := ListPods(t, options, metav1.ListOptions{})
+	require.NotEmpty(t, pods)
+	assert.FileExists(t, filepath.Join(dir, "logs", pods[0].Name, "nginx.log"))
+
+	describe, err := os.ReadFile(filepath.Join(dir, "describe-deployments.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(describe), "nginx-deployment")
+}
+
+// failedT is a testing.T that runs its cleanup functions when asked to and reports that the test failed.
+type failedT struct {
+	*testing.T
+	cleanups []func()
+}
+
+func (t *failedT) Cleanup(cleanup func()) {
+	t.cleanups = append(t.cleanups, cleanup)
+}
+
+func (t *failedT) Failed() bool {
+	return true
+}
+
+func TestDumpNamespaceOnFailure(t *testing.T) {
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleDeploymentYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	artifactsDir := t.TempDir()
+	t.Setenv(shell.ArtifactsDirEnvVar, artifactsDir)
+
+	ft := &failedT{T: t}
+	DumpNamespaceOnFailure(ft, options)
+	require.Equal(t, 1, len(ft.cleanups))
+	ft.cleanups[0]()
+
+	assert.FileExists(t, filepath.Join(artifactsDir, "TestDumpNamespaceOnFailure", uniqueID, "pods.txt"))
+
+	// Without an artifacts directory, the namespace is dumped to the temporary directory of the system
+	t.Setenv(shell.ArtifactsDirEnvVar, "")
+	t.Setenv("TMPDIR", t.TempDir())
+	ft = &failedT{T: t}
+	DumpNamespaceOnFailure(ft, options)
+	require.Equal(t, 1, len(ft.cleanups))
+	ft.cleanups[0]()
+
+	assert.FileExists(t, filepath.Join(os.TempDir(), defaultDumpDirName, "TestDumpNamespaceOnFailure", uniqueID, "pods.txt"))
+}
+
+func TestFormatPodStatuses(t *testing.T) {
+	t.Parallel()
+
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "crashing"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:                 "app",
+						RestartCount:         2,
+						State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+						LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+					},
+				},
+			},
+		},
+	}
+	statuses := formatPodStatuses(pods)
+	assert.Contains(t, statuses, "crashing: Running")
+	assert.Contains(t, statuses, "app: waiting (CrashLoopBackOff: ), ready: false, restarts: 2")
+	assert.Contains(t, statuses, "last state: terminated with exit code 1 (Error: )")
+}
+
+func TestFormatEventsSortsOldestFirst(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	events := []corev1.Event{
+		{Reason: "Second", LastTimestamp: metav1.NewTime(now)},
+		{Reason: "First", LastTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+	}
+	formatted := formatEvents(events)
+	assert.Less(t, strings.Index(formatted, "First"), strings.Index(formatted, "Second"))
+}
@@ -0,0 +1,161 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ListEndpointSlices will look for endpoint slices in the given namespace that match the given filters and return them.
+// This will fail the test if there is an error.
+func ListEndpointSlices(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) []discoveryv1.EndpointSlice {
+	endpointSlices, err := ListEndpointSlicesE(t, options, filters)
+	require.NoError(t, err)
+	return endpointSlices
+}
+
+// ListEndpointSlicesE will look for endpoint slices in the given namespace that match the given filters and return
+// them.
+func ListEndpointSlicesE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) ([]discoveryv1.EndpointSlice, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := clientset.DiscoveryV1().EndpointSlices(options.Namespace).List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetEndpointSlice returns a Kubernetes endpoint slice resource in the provided namespace with the given name. This
+// will fail the test if there is an error.
+func GetEndpointSlice(t testing.TestingT, options *KubectlOptions, endpointSliceName string) *discoveryv1.EndpointSlice {
+	endpointSlice, err := GetEndpointSliceE(t, options, endpointSliceName)
+	require.NoError(t, err)
+	return endpointSlice
+}
+
+// GetEndpointSliceE returns a Kubernetes endpoint slice resource in the provided namespace with the given name.
+func GetEndpointSliceE(t testing.TestingT, options *KubectlOptions, endpointSliceName string) (*discoveryv1.EndpointSlice, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.DiscoveryV1().EndpointSlices(options.Namespace).Get(context.Background(), endpointSliceName, metav1.GetOptions{})
+}
+
+// ListEndpointSlicesForService returns the endpoint slices of the service with the given name in the provided
+// namespace. This will fail the test if there is an error.
+func ListEndpointSlicesForService(t testing.TestingT, options *KubectlOptions, serviceName string) []discoveryv1.EndpointSlice {
+	endpointSlices, err := ListEndpointSlicesForServiceE(t, options, serviceName)
+	require.NoError(t, err)
+	return endpointSlices
+}
+
+// ListEndpointSlicesForServiceE returns the endpoint slices of the service with the given name in the provided
+// namespace.
+func ListEndpointSlicesForServiceE(t testing.TestingT, options *KubectlOptions, serviceName string) ([]discoveryv1.EndpointSlice, error) {
+	return ListEndpointSlicesE(t, options, serviceSelector(serviceName))
+}
+
+// WaitUntilServiceHasReadyEndpoints waits until the endpoint slices of the service with the given name have at least
+// the given number of ready endpoints, i.e. pods that are ready to receive traffic through the service, retrying the
+// check for the specified amount of times, sleeping for the provided duration between each try. This will fail the
+// test if there is an error or if the check times out.
+func WaitUntilServiceHasReadyEndpoints(t testing.TestingT, options *KubectlOptions, serviceName string, minReadyEndpoints int, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilServiceHasReadyEndpointsE(t, options, serviceName, minReadyEndpoints, retries, sleepBetweenRetries))
+}
+
+// WaitUntilServiceHasReadyEndpointsE waits until the endpoint slices of the service with the given name have at least
+// the given number of ready endpoints, retrying the check for the specified amount of times, sleeping for the provided
+// duration between each try.
+func WaitUntilServiceHasReadyEndpointsE(t testing.TestingT, options *KubectlOptions, serviceName string, minReadyEndpoints int, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilServiceHasReadyEndpointsContextE(t, context.Background(), options, serviceName, minReadyEndpoints, retries, sleepBetweenRetries)
+}
+
+// WaitUntilServiceHasReadyEndpointsContext is like WaitUntilServiceHasReadyEndpoints, but stops waiting as soon as the
+// given context is done. This will fail the test if there is an error or if the check times out.
+func WaitUntilServiceHasReadyEndpointsContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, serviceName string, minReadyEndpoints int, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilServiceHasReadyEndpointsContextE(t, ctx, options, serviceName, minReadyEndpoints, retries, sleepBetweenRetries))
+}
+
+// WaitUntilServiceHasReadyEndpointsContextE is like WaitUntilServiceHasReadyEndpointsE, but stops waiting as soon as
+// the given context is done.
+func WaitUntilServiceHasReadyEndpointsContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, serviceName string, minReadyEndpoints int, retries int, sleepBetweenRetries time.Duration) error {
+	statusMsg := fmt.Sprintf("Wait for service %s to have %d ready endpoints.", serviceName, minReadyEndpoints)
+	message, err := waitUntilWatchedContextE(
+		t,
+		ctx,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		watchEndpointSlices(t, options, serviceSelector(serviceName)),
+		func() (string, error) {
+			endpointSlices, err := ListEndpointSlicesForServiceE(t, options, serviceName)
+			if err != nil {
+				return "", err
+			}
+			readyEndpoints := CountReadyEndpoints(endpointSlices)
+			if readyEndpoints < minReadyEndpoints {
+				return "", NewServiceNotEnoughReadyEndpointsError(serviceName, readyEndpoints, minReadyEndpoints)
+			}
+			return fmt.Sprintf("Service now has %d ready endpoints", readyEndpoints), nil
+		},
+	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for Service to have ready endpoints: %s", err)
+		return err
+	}
+	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// CountReadyEndpoints returns the number of endpoints in the given endpoint slices that are ready. Endpoints that do
+// not report their readiness are considered ready, as the API documents. An endpoint that is in several slices, such as
+// a pod of a dual-stack service, which has a slice for each IP family, is only counted once: endpoints are the same if
+// they refer to the same object, e.g. pod, or, for endpoints that do not refer to one, if they have the same addresses.
+func CountReadyEndpoints(endpointSlices []discoveryv1.EndpointSlice) int {
+	ready := map[string]bool{}
+	for _, endpointSlice := range endpointSlices {
+		for _, endpoint := range endpointSlice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready[endpointKey(endpoint)] = true
+			}
+		}
+	}
+	return len(ready)
+}
+
+// endpointKey returns what identifies the given endpoint across endpoint slices: the UID of the object it refers to,
+// or its addresses if it does not refer to one.
+func endpointKey(endpoint discoveryv1.Endpoint) string {
+	if endpoint.TargetRef != nil && endpoint.TargetRef.UID != "" {
+		return "uid:" + string(endpoint.TargetRef.UID)
+	}
+	return "addresses:" + strings.Join(endpoint.Addresses, ",")
+}
+
+// serviceSelector returns list options that select the endpoint slices of the service with the given name.
+func serviceSelector(serviceName string) metav1.ListOptions {
+	return metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, serviceName)}
+}
+
+// watchEndpointSlices returns a function that watches the endpoint slices that match the given filters, for waits.
+func watchEndpointSlices(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.DiscoveryV1().EndpointSlices(options.Namespace).Watch(ctx, filters)
+	}
+}
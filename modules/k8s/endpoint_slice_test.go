@@ -0,0 +1,134 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestGetEndpointSliceEReturnsError(t *testing.T) {
+	t.Parallel()
+
+	options := NewKubectlOptions("", "", "")
+	_, err := GetEndpointSliceE(t, options, "nginx-service-abcde")
+	require.Error(t, err)
+}
+
+func TestWaitUntilServiceHasReadyEndpoints(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleEndpointSliceYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	WaitUntilServiceHasReadyEndpoints(t, options, "nginx-service", 2, 60, 1*time.Second)
+
+	endpointSlices := ListEndpointSlicesForService(t, options, "nginx-service")
+	require.NotEmpty(t, endpointSlices)
+	require.Equal(t, "nginx-service", endpointSlices[0].Labels[discoveryv1.LabelServiceName])
+	require.Equal(t, 2, CountReadyEndpoints(endpointSlices))
+}
+
+func TestCountReadyEndpoints(t *testing.T) {
+	t.Parallel()
+
+	ready := true
+	notReady := false
+	endpointSlices := []discoveryv1.EndpointSlice{
+		{
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+				{Addresses: []string{"10.0.0.2"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+			},
+		},
+		{
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.3"}, Conditions: discoveryv1.EndpointConditions{}},
+			},
+		},
+	}
+	require.Equal(t, 2, CountReadyEndpoints(endpointSlices))
+}
+
+func TestCountReadyEndpointsCountsDualStackPodsOnce(t *testing.T) {
+	t.Parallel()
+
+	ready := true
+	pod := &corev1.ObjectReference{Kind: "Pod", Name: "nginx", UID: types.UID("0b1a2c3d")}
+	endpointSlices := []discoveryv1.EndpointSlice{
+		{
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}, TargetRef: pod},
+			},
+		},
+		{
+			AddressType: discoveryv1.AddressTypeIPv6,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"fd00::1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}, TargetRef: pod},
+			},
+		},
+	}
+	require.Equal(t, 1, CountReadyEndpoints(endpointSlices))
+}
+
+const ExampleEndpointSliceYAMLTemplate = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: nginx
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15.7
+        ports:
+        - containerPort: 80
+        readinessProbe:
+          httpGet:
+            path: /
+            port: 80
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx-service
+spec:
+  selector:
+    app: nginx
+  ports:
+  - protocol: TCP
+    port: 80
+`
@@ -2,13 +2,19 @@ package k8s
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // IngressNotAvailable is returned when a Kubernetes service is not yet available to accept traffic.
@@ -92,6 +98,134 @@ func NewDeploymentNotAvailableError(deploy *appsv1.Deployment) DeploymentNotAvai
 	return DeploymentNotAvailable{deploy}
 }
 
+// StatefulSetNotAvailable is returned when a Kubernetes statefulset is not yet rolled out with all of its pods ready.
+type StatefulSetNotAvailable struct {
+	statefulSet *appsv1.StatefulSet
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err StatefulSetNotAvailable) Error() string {
+	return fmt.Sprintf(
+		"StatefulSet %s is not available, replicas: %d, ready: %d, updated: %d, current revision: %s, update revision: %s",
+		err.statefulSet.Name,
+		err.statefulSet.Status.Replicas,
+		err.statefulSet.Status.ReadyReplicas,
+		err.statefulSet.Status.UpdatedReplicas,
+		err.statefulSet.Status.CurrentRevision,
+		err.statefulSet.Status.UpdateRevision,
+	)
+}
+
+// NewStatefulSetNotAvailableError returns a StatefulSetNotAvailable struct when Kubernetes deems a statefulset is not
+// available
+func NewStatefulSetNotAvailableError(statefulSet *appsv1.StatefulSet) StatefulSetNotAvailable {
+	return StatefulSetNotAvailable{statefulSet}
+}
+
+// HorizontalPodAutoscalerNotActive is returned when a Kubernetes horizontal pod autoscaler does not have current
+// metrics or is not able to scale its target.
+type HorizontalPodAutoscalerNotActive struct {
+	hpa *autoscalingv2.HorizontalPodAutoscaler
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err HorizontalPodAutoscalerNotActive) Error() string {
+	condition := getHorizontalPodAutoscalerCondition(err.hpa, autoscalingv2.ScalingActive)
+	if condition == nil {
+		return fmt.Sprintf(
+			"HorizontalPodAutoscaler %s is not active, missing '%s' condition, current metrics: %d of %d",
+			err.hpa.Name,
+			autoscalingv2.ScalingActive,
+			len(err.hpa.Status.CurrentMetrics),
+			len(err.hpa.Spec.Metrics),
+		)
+	}
+	return fmt.Sprintf(
+		"HorizontalPodAutoscaler %s is not active, current metrics: %d of %d, status: %v, reason: %s, message: %s",
+		err.hpa.Name,
+		len(err.hpa.Status.CurrentMetrics),
+		len(err.hpa.Spec.Metrics),
+		condition.Status,
+		condition.Reason,
+		condition.Message,
+	)
+}
+
+// NewHorizontalPodAutoscalerNotActiveError returns a HorizontalPodAutoscalerNotActive struct when Kubernetes deems a
+// horizontal pod autoscaler is not active
+func NewHorizontalPodAutoscalerNotActiveError(hpa *autoscalingv2.HorizontalPodAutoscaler) HorizontalPodAutoscalerNotActive {
+	return HorizontalPodAutoscalerNotActive{hpa}
+}
+
+// PodDisruptionBudgetNotAvailable is returned when a Kubernetes pod disruption budget does not have as many healthy
+// pods as it requires.
+type PodDisruptionBudgetNotAvailable struct {
+	pdb *policyv1.PodDisruptionBudget
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err PodDisruptionBudgetNotAvailable) Error() string {
+	return fmt.Sprintf(
+		"PodDisruptionBudget %s is not available, expected pods: %d, healthy: %d, desired healthy: %d",
+		err.pdb.Name,
+		err.pdb.Status.ExpectedPods,
+		err.pdb.Status.CurrentHealthy,
+		err.pdb.Status.DesiredHealthy,
+	)
+}
+
+// NewPodDisruptionBudgetNotAvailableError returns a PodDisruptionBudgetNotAvailable struct when Kubernetes deems a pod
+// disruption budget is not available
+func NewPodDisruptionBudgetNotAvailableError(pdb *policyv1.PodDisruptionBudget) PodDisruptionBudgetNotAvailable {
+	return PodDisruptionBudgetNotAvailable{pdb}
+}
+
+// ResourceQuotaNotCalculated is returned when the resource quota controller has not yet calculated the usage of a
+// Kubernetes resource quota.
+type ResourceQuotaNotCalculated struct {
+	resourceQuota *corev1.ResourceQuota
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err ResourceQuotaNotCalculated) Error() string {
+	return fmt.Sprintf(
+		"ResourceQuota %s is not calculated, limits %d resources but reports the usage of %d",
+		err.resourceQuota.Name,
+		len(err.resourceQuota.Spec.Hard),
+		len(err.resourceQuota.Status.Used),
+	)
+}
+
+// NewResourceQuotaNotCalculatedError returns a ResourceQuotaNotCalculated struct when the usage of a resource quota is
+// not calculated
+func NewResourceQuotaNotCalculatedError(resourceQuota *corev1.ResourceQuota) ResourceQuotaNotCalculated {
+	return ResourceQuotaNotCalculated{resourceQuota}
+}
+
+// ServiceNotEnoughReadyEndpoints is returned when the endpoint slices of a Kubernetes service do not have enough ready
+// endpoints.
+type ServiceNotEnoughReadyEndpoints struct {
+	serviceName       string
+	readyEndpoints    int
+	minReadyEndpoints int
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err ServiceNotEnoughReadyEndpoints) Error() string {
+	return fmt.Sprintf(
+		"Service %s has %d ready endpoints, expected at least %d",
+		err.serviceName,
+		err.readyEndpoints,
+		err.minReadyEndpoints,
+	)
+}
+
+// NewServiceNotEnoughReadyEndpointsError returns a ServiceNotEnoughReadyEndpoints struct when a service does not have
+// enough ready endpoints
+func NewServiceNotEnoughReadyEndpointsError(serviceName string, readyEndpoints int, minReadyEndpoints int) ServiceNotEnoughReadyEndpoints {
+	return ServiceNotEnoughReadyEndpoints{serviceName, readyEndpoints, minReadyEndpoints}
+}
+
 // PodNotAvailable is returned when a Kubernetes service is not yet available to accept traffic.
 type PodNotAvailable struct {
 	pod *corev1.Pod
@@ -296,3 +430,139 @@ func (err CronJobNotSucceeded) Error() string {
 func NewCronJobNotSucceeded(cronJob *batchv1.CronJob) CronJobNotSucceeded {
 	return CronJobNotSucceeded{cronJob}
 }
+
+// UnknownResource is returned when the API server does not serve the given resource.
+type UnknownResource struct {
+	GroupVersionResource schema.GroupVersionResource
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err UnknownResource) Error() string {
+	return fmt.Sprintf("The API server does not serve the resource %s", err.GroupVersionResource)
+}
+
+// ResourceConditionNotMet is returned when a Kubernetes resource does not have a condition with the expected status.
+type ResourceConditionNotMet struct {
+	resource       *unstructured.Unstructured
+	conditionType  string
+	expectedStatus metav1.ConditionStatus
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err ResourceConditionNotMet) Error() string {
+	condition := GetResourceCondition(err.resource, err.conditionType)
+	if condition == nil {
+		return fmt.Sprintf(
+			"%s %s does not have the condition '%s' yet",
+			err.resource.GetKind(),
+			err.resource.GetName(),
+			err.conditionType,
+		)
+	}
+	return fmt.Sprintf(
+		"%s %s has condition '%s' with status %s instead of %s, reason: %s, message: %s",
+		err.resource.GetKind(),
+		err.resource.GetName(),
+		err.conditionType,
+		condition.Status,
+		err.expectedStatus,
+		condition.Reason,
+		condition.Message,
+	)
+}
+
+// NewResourceConditionNotMetError returns a ResourceConditionNotMet struct when a resource does not have a condition
+// with the expected status
+func NewResourceConditionNotMetError(resource *unstructured.Unstructured, conditionType string, expectedStatus metav1.ConditionStatus) ResourceConditionNotMet {
+	return ResourceConditionNotMet{resource, conditionType, expectedStatus}
+}
+
+// ObjectsNotReady is returned when Kubernetes objects do not become ready, as computed by GetObjectReadiness.
+type ObjectsNotReady struct {
+	Objects []ObjectReadiness
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err ObjectsNotReady) Error() string {
+	lines := []string{fmt.Sprintf("%d objects are not ready:", len(err.Objects))}
+	for _, object := range err.Objects {
+		lines = append(lines, fmt.Sprintf("  - %s", object))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ObjectRejected is returned when the API server rejects a Kubernetes object, e.g. because it is invalid, because
+// another field manager manages the applied fields, or because an admission webhook or policy denies it.
+type ObjectRejected struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Status is the status the API server responded with, which has the reason and message of the rejection, as well
+	// as the causes of validation errors in Details.
+	Status metav1.Status
+
+	// Underlying is the error returned by the API server, so that apierrors.IsInvalid, IsForbidden, IsConflict and
+	// the like work on an ObjectRejected.
+	Underlying error
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err ObjectRejected) Error() string {
+	name := err.Name
+	if err.Namespace != "" {
+		name = err.Namespace + "/" + name
+	}
+	return fmt.Sprintf("%s %s was rejected (%s): %s", err.Kind, name, err.Status.Reason, err.Status.Message)
+}
+
+// Unwrap returns the error returned by the API server.
+func (err ObjectRejected) Unwrap() error {
+	return err.Underlying
+}
+
+// LogLineNotFound is returned when no container of the pods that match a selector logs a line matching the expected
+// regular expression.
+type LogLineNotFound struct {
+	Selector string
+	Regex    string
+	Timeout  time.Duration
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err LogLineNotFound) Error() string {
+	return fmt.Sprintf("no container of the pods matching %s logged a line matching %s within %s", err.Selector, err.Regex, err.Timeout)
+}
+
+// ExecCommandFailed is returned when a command run in a container of a pod exits with a non-zero code.
+type ExecCommandFailed struct {
+	PodName  string
+	Command  []string
+	ExitCode int
+	Stderr   string
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err ExecCommandFailed) Error() string {
+	return fmt.Sprintf("command %s in pod %s exited with code %d: %s", err.Command, err.PodName, err.ExitCode, strings.TrimSpace(err.Stderr))
+}
+
+// TunnelHasNoPorts is returned when a tunnel that forwards no ports is opened.
+type TunnelHasNoPorts struct {
+	ResourceName string
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err TunnelHasNoPorts) Error() string {
+	return fmt.Sprintf("tunnel to %s has no ports to forward", err.ResourceName)
+}
+
+// TunnelAlreadyOpened is returned when a tunnel that was already opened is opened again.
+type TunnelAlreadyOpened struct {
+	ResourceName string
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err TunnelAlreadyOpened) Error() string {
+	return fmt.Sprintf("tunnel to %s was already opened", err.ResourceName)
+}
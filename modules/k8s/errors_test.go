@@ -1,13 +1,17 @@
 package k8s
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestErrorDeploymentNotAvailable(t *testing.T) {
@@ -58,3 +62,18 @@ func TestErrorDeploymentNotAvailable(t *testing.T) {
 		})
 	}
 }
+
+func TestObjectRejectedUnwrapsTheAPIError(t *testing.T) {
+	t.Parallel()
+
+	object := &unstructured.Unstructured{}
+	object.SetKind("ConfigMap")
+	object.SetNamespace("test")
+	object.SetName("foo")
+	apiErr := apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "foo", errors.New("denied by policy"))
+
+	err := newObjectRejectedError(object, apiErr)
+	assert.IsType(t, ObjectRejected{}, err)
+	assert.True(t, apierrors.IsForbidden(err))
+	assert.Contains(t, err.Error(), "ConfigMap test/foo was rejected (Forbidden)")
+}
@@ -0,0 +1,338 @@
+package k8s
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// defaultContainerAnnotation is the annotation kubectl reads the container to use from when none is given.
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// ExecOptions are the options of ExecPodWithOptions.
+type ExecOptions struct {
+	// Container is the name of the container to run the command in. Defaults to the container the
+	// kubectl.kubernetes.io/default-container annotation of the pod names, or else its first container.
+	Container string
+
+	// Command is the command to run, along with its arguments. It is not run in a shell.
+	Command []string
+
+	// Stdin, if set, is streamed to the stdin of the command, which is closed once Stdin returns io.EOF.
+	Stdin io.Reader
+
+	// TTY allocates a terminal for the command. The terminal merges stderr into stdout.
+	TTY bool
+}
+
+// ExecResult is the outcome of a command run in a container with ExecPodWithOptions.
+type ExecResult struct {
+	Stdout string
+	// Stderr is always empty when TTY is set, as the terminal merges stderr into stdout.
+	Stderr   string
+	ExitCode int
+}
+
+// ExecPodWithOptions runs a command in a container of the given pod through the API server, like kubectl exec, and
+// returns its stdout, stderr and exit code. A command that exits with a non-zero code is not an error: check the
+// ExitCode of the result. This will fail the test if the command could not be run, e.g. because the pod does not exist.
+func ExecPodWithOptions(t testing.TestingT, options *KubectlOptions, podName string, execOptions ExecOptions) ExecResult {
+	result, err := ExecPodWithOptionsE(t, options, podName, execOptions)
+	require.NoError(t, err)
+	return result
+}
+
+// ExecPodWithOptionsE runs a command in a container of the given pod through the API server, like kubectl exec, and
+// returns its stdout, stderr and exit code. The error is only set if the command could not be run, e.g. because the
+// pod does not exist or the connection failed, and not if the command exits with a non-zero code.
+func ExecPodWithOptionsE(t testing.TestingT, options *KubectlOptions, podName string, execOptions ExecOptions) (ExecResult, error) {
+	return ExecPodWithOptionsContextE(t, context.Background(), options, podName, execOptions)
+}
+
+// ExecPodWithOptionsContext is like ExecPodWithOptions, but stops the command as soon as the given context is done.
+// This will fail the test if the command could not be run.
+func ExecPodWithOptionsContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, podName string, execOptions ExecOptions) ExecResult {
+	result, err := ExecPodWithOptionsContextE(t, ctx, options, podName, execOptions)
+	require.NoError(t, err)
+	return result
+}
+
+// ExecPodWithOptionsContextE is like ExecPodWithOptionsE, but stops the command as soon as the given context is done.
+func ExecPodWithOptionsContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, podName string, execOptions ExecOptions) (ExecResult, error) {
+	var stdout, stderr bytes.Buffer
+	exitCode, err := execPodStreamsE(t, ctx, options, podName, execOptions, &stdout, &stderr)
+	if err != nil {
+		return ExecResult{}, err
+	}
+	return ExecResult{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: exitCode}, nil
+}
+
+// CopyToPod copies the local file or directory at localPath to remotePath in a container of the given pod, like
+// kubectl cp. A directory is copied recursively, with remotePath becoming the directory. As with kubectl cp, the
+// container must have tar. Set containerName to "" to use the default container of the pod. This will fail the test if
+// there is an error.
+func CopyToPod(t testing.TestingT, options *KubectlOptions, podName string, containerName string, localPath string, remotePath string) {
+	require.NoError(t, CopyToPodE(t, options, podName, containerName, localPath, remotePath))
+}
+
+// CopyToPodE copies the local file or directory at localPath to remotePath in a container of the given pod, like
+// kubectl cp. A directory is copied recursively, with remotePath becoming the directory. As with kubectl cp, the
+// container must have tar. Set containerName to "" to use the default container of the pod.
+func CopyToPodE(t testing.TestingT, options *KubectlOptions, podName string, containerName string, localPath string, remotePath string) error {
+	if _, err := os.Stat(localPath); err != nil {
+		return err
+	}
+	options.Logger.Logf(t, "Copying %s to %s in pod %s", localPath, remotePath, podName)
+
+	remotePath = path.Clean(remotePath)
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeTar(writer, localPath, path.Base(remotePath)))
+	}()
+	// Stop writing the archive if the command fails before reading all of it
+	defer reader.Close()
+
+	command := []string{"tar", "-xmf", "-", "-C", path.Dir(remotePath)}
+	return runTarInPodE(t, options, podName, containerName, command, reader, io.Discard)
+}
+
+// CopyFromPod copies the file or directory at remotePath in a container of the given pod to localPath, like kubectl
+// cp. A directory is copied recursively, with localPath becoming the directory. Symbolic links are not copied. As with
+// kubectl cp, the container must have tar. Set containerName to "" to use the default container of the pod. This will
+// fail the test if there is an error.
+func CopyFromPod(t testing.TestingT, options *KubectlOptions, podName string, containerName string, remotePath string, localPath string) {
+	require.NoError(t, CopyFromPodE(t, options, podName, containerName, remotePath, localPath))
+}
+
+// CopyFromPodE copies the file or directory at remotePath in a container of the given pod to localPath, like kubectl
+// cp. A directory is copied recursively, with localPath becoming the directory. Symbolic links are not copied. As with
+// kubectl cp, the container must have tar. Set containerName to "" to use the default container of the pod.
+func CopyFromPodE(t testing.TestingT, options *KubectlOptions, podName string, containerName string, remotePath string, localPath string) error {
+	options.Logger.Logf(t, "Copying %s in pod %s to %s", remotePath, podName, localPath)
+
+	remotePath = path.Clean(remotePath)
+	reader, writer := io.Pipe()
+	extracted := make(chan error, 1)
+	go func() {
+		err := readTar(reader, path.Base(remotePath), localPath)
+		// Keep draining the archive, so that the command does not block on a full pipe
+		_, _ = io.Copy(io.Discard, reader)
+		extracted <- err
+	}()
+
+	command := []string{"tar", "-cf", "-", "-C", path.Dir(remotePath), path.Base(remotePath)}
+	err := runTarInPodE(t, options, podName, containerName, command, nil, writer)
+	writer.Close()
+	if extractErr := <-extracted; err == nil {
+		err = extractErr
+	}
+	return err
+}
+
+// execPodStreamsE runs a command in a container of the given pod, streaming its output to the given writers, and
+// returns its exit code.
+func execPodStreamsE(
+	t testing.TestingT,
+	ctx context.Context,
+	options *KubectlOptions,
+	podName string,
+	execOptions ExecOptions,
+	stdout io.Writer,
+	stderr io.Writer,
+) (int, error) {
+	containerName := execOptions.Container
+	if containerName == "" {
+		pod, err := GetPodE(t, options, podName)
+		if err != nil {
+			return 0, err
+		}
+		containerName = getDefaultContainerName(pod)
+	}
+
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return 0, err
+	}
+	config, err := GetRestConfigFromOptionsE(t, options)
+	if err != nil {
+		return 0, err
+	}
+
+	request := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(options.Namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   execOptions.Command,
+			Stdin:     execOptions.Stdin != nil,
+			Stdout:    true,
+			Stderr:    !execOptions.TTY,
+			TTY:       execOptions.TTY,
+		}, scheme.ParameterCodec)
+
+	// Like kubectl, prefer WebSockets, falling back to SPDY for API servers that do not support them
+	websocketExecutor, err := remotecommand.NewWebSocketExecutor(config, "GET", request.URL().String())
+	if err != nil {
+		return 0, err
+	}
+	spdyExecutor, err := remotecommand.NewSPDYExecutor(config, "POST", request.URL())
+	if err != nil {
+		return 0, err
+	}
+	executor, err := remotecommand.NewFallbackExecutor(websocketExecutor, spdyExecutor, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	options.Logger.Logf(t, "Running command %s in container %s of pod %s", execOptions.Command, containerName, podName)
+	streamOptions := remotecommand.StreamOptions{Stdin: execOptions.Stdin, Stdout: stdout, Tty: execOptions.TTY}
+	if !execOptions.TTY {
+		streamOptions.Stderr = stderr
+	}
+	err = executor.StreamWithContext(ctx, streamOptions)
+
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		return exitErr.ExitStatus(), nil
+	}
+	return 0, err
+}
+
+// runTarInPodE runs the given tar command in a container of the given pod, returning an ExecCommandFailed error if it
+// exits with a non-zero code.
+func runTarInPodE(t testing.TestingT, options *KubectlOptions, podName string, containerName string, command []string, stdin io.Reader, stdout io.Writer) error {
+	var stderr bytes.Buffer
+	execOptions := ExecOptions{Container: containerName, Command: command, Stdin: stdin}
+	exitCode, err := execPodStreamsE(t, context.Background(), options, podName, execOptions, stdout, &stderr)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return ExecCommandFailed{PodName: podName, Command: command, ExitCode: exitCode, Stderr: stderr.String()}
+	}
+	return nil
+}
+
+// getDefaultContainerName returns the container kubectl runs commands in when none is given.
+func getDefaultContainerName(pod *corev1.Pod) string {
+	if name := pod.Annotations[defaultContainerAnnotation]; name != "" {
+		return name
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+// writeTar writes a tar archive of the file or directory at localPath to the given writer, naming it rootName in the
+// archive.
+func writeTar(writer io.Writer, localPath string, rootName string) error {
+	tarWriter := tar.NewWriter(writer)
+	err := filepath.Walk(localPath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(localPath, filePath)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(filePath); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(rootName, filepath.ToSlash(relativePath))
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tarWriter.Close()
+}
+
+// readTar extracts the tar archive read from the given reader to localPath, which the entry named rootName in the
+// archive becomes. Entries outside of rootName, which a compromised container could use to write anywhere, and
+// symbolic links are skipped.
+func readTar(reader io.Reader, rootName string, localPath string) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(header.Name)
+		if name != rootName && !strings.HasPrefix(name, rootName+"/") {
+			continue
+		}
+		destination := filepath.Join(localPath, filepath.FromSlash(strings.TrimPrefix(name, rootName)))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(destination, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractFile(tarReader, destination, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func extractFile(reader io.Reader, destination string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(destination), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(destination, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, reader); err != nil {
+		return fmt.Errorf("extracting %s: %w", destination, err)
+	}
+	return nil
+}
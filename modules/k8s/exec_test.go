@@ -0,0 +1,164 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestExecPodWithOptions(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleExecPodYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+	WaitUntilPodAvailable(t, options, "exec-pod", 60, 1*time.Second)
+
+	result := ExecPodWithOptions(t, options, "exec-pod", ExecOptions{
+		Command: []string{"sh", "-c", "echo out; echo err >&2; exit 3"},
+	})
+	assert.Equal(t, "out\n", result.Stdout)
+	assert.Equal(t, "err\n", result.Stderr)
+	assert.Equal(t, 3, result.ExitCode)
+
+	result = ExecPodWithOptions(t, options, "exec-pod", ExecOptions{
+		Container: "sidecar",
+		Command:   []string{"cat"},
+		Stdin:     strings.NewReader("from stdin"),
+	})
+	assert.Equal(t, "from stdin", result.Stdout)
+	assert.Equal(t, 0, result.ExitCode)
+}
+
+func TestExecPodWithOptionsReturnsErrorForMissingPod(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	_, err := ExecPodWithOptionsE(t, options, "missing-pod", ExecOptions{Command: []string{"true"}})
+	require.Error(t, err)
+}
+
+func TestCopyToAndFromPod(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleExecPodYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+	WaitUntilPodAvailable(t, options, "exec-pod", 60, 1*time.Second)
+
+	localDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(localDir, "config", "nested"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "config", "app.conf"), []byte("key=value\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "config", "nested", "other.conf"), []byte("other\n"), 0600))
+
+	CopyToPod(t, options, "exec-pod", "", filepath.Join(localDir, "config"), "/tmp/copied")
+	result := ExecPodWithOptions(t, options, "exec-pod", ExecOptions{Command: []string{"cat", "/tmp/copied/nested/other.conf"}})
+	assert.Equal(t, "other\n", result.Stdout)
+
+	copiedBack := filepath.Join(localDir, "copied-back")
+	CopyFromPod(t, options, "exec-pod", "", "/tmp/copied", copiedBack)
+	content, err := os.ReadFile(filepath.Join(copiedBack, "app.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "key=value\n", string(content))
+
+	err = CopyFromPodE(t, options, "exec-pod", "", "/does/not/exist", filepath.Join(localDir, "missing"))
+	var failed ExecCommandFailed
+	require.True(t, errors.As(err, &failed))
+	assert.NotEqual(t, 0, failed.ExitCode)
+}
+
+func TestTarRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	source := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(source, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "sub", "file.txt"), []byte("content"), 0640))
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeTar(writer, source, "root"))
+	}()
+	destination := filepath.Join(t.TempDir(), "destination")
+	require.NoError(t, readTar(reader, "root", destination))
+
+	content, err := os.ReadFile(filepath.Join(destination, "sub", "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+	info, err := os.Stat(filepath.Join(destination, "sub", "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+}
+
+func TestReadTarSkipsEntriesOutsideRoot(t *testing.T) {
+	t.Parallel()
+
+	source := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(source, "file.txt"), []byte("content"), 0644))
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeTar(writer, filepath.Join(source, "file.txt"), "../escaped.txt"))
+	}()
+	destination := filepath.Join(t.TempDir(), "destination")
+	require.NoError(t, readTar(reader, "file.txt", destination))
+
+	_, err := os.Stat(filepath.Join(filepath.Dir(destination), "escaped.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGetDefaultContainerName(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "first"}, {Name: "second"}}},
+	}
+	assert.Equal(t, "first", getDefaultContainerName(pod))
+
+	pod.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{defaultContainerAnnotation: "second"}}
+	assert.Equal(t, "second", getDefaultContainerName(pod))
+}
+
+const ExampleExecPodYAMLTemplate = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: exec-pod
+spec:
+  containers:
+  - name: main
+    image: busybox:1.36
+    command: ["sleep", "3600"]
+  - name: sidecar
+    image: busybox:1.36
+    command: ["sleep", "3600"]
+`
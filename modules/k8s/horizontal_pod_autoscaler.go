@@ -0,0 +1,139 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ListHorizontalPodAutoscalers will look for horizontal pod autoscalers in the given namespace that match the given
+// filters and return them. This will fail the test if there is an error.
+func ListHorizontalPodAutoscalers(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) []autoscalingv2.HorizontalPodAutoscaler {
+	hpas, err := ListHorizontalPodAutoscalersE(t, options, filters)
+	require.NoError(t, err)
+	return hpas
+}
+
+// ListHorizontalPodAutoscalersE will look for horizontal pod autoscalers in the given namespace that match the given
+// filters and return them.
+func ListHorizontalPodAutoscalersE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(options.Namespace).List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetHorizontalPodAutoscaler returns a Kubernetes horizontal pod autoscaler resource in the provided namespace with the
+// given name. This will fail the test if there is an error.
+func GetHorizontalPodAutoscaler(t testing.TestingT, options *KubectlOptions, hpaName string) *autoscalingv2.HorizontalPodAutoscaler {
+	hpa, err := GetHorizontalPodAutoscalerE(t, options, hpaName)
+	require.NoError(t, err)
+	return hpa
+}
+
+// GetHorizontalPodAutoscalerE returns a Kubernetes horizontal pod autoscaler resource in the provided namespace with the
+// given name.
+func GetHorizontalPodAutoscalerE(t testing.TestingT, options *KubectlOptions, hpaName string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.AutoscalingV2().HorizontalPodAutoscalers(options.Namespace).Get(context.Background(), hpaName, metav1.GetOptions{})
+}
+
+// WaitUntilHorizontalPodAutoscalerActive waits until the horizontal pod autoscaler has current values for all of its
+// metrics and is able to scale its target, retrying the check for the specified amount of times, sleeping for the
+// provided duration between each try. This will fail the test if there is an error or if the check times out.
+func WaitUntilHorizontalPodAutoscalerActive(t testing.TestingT, options *KubectlOptions, hpaName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilHorizontalPodAutoscalerActiveE(t, options, hpaName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilHorizontalPodAutoscalerActiveE waits until the horizontal pod autoscaler has current values for all of its
+// metrics and is able to scale its target, retrying the check for the specified amount of times, sleeping for the
+// provided duration between each try.
+func WaitUntilHorizontalPodAutoscalerActiveE(t testing.TestingT, options *KubectlOptions, hpaName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilHorizontalPodAutoscalerActiveContextE(t, context.Background(), options, hpaName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilHorizontalPodAutoscalerActiveContext is like WaitUntilHorizontalPodAutoscalerActive, but stops waiting as
+// soon as the given context is done. This will fail the test if there is an error or if the check times out.
+func WaitUntilHorizontalPodAutoscalerActiveContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, hpaName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilHorizontalPodAutoscalerActiveContextE(t, ctx, options, hpaName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilHorizontalPodAutoscalerActiveContextE is like WaitUntilHorizontalPodAutoscalerActiveE, but stops waiting as
+// soon as the given context is done.
+func WaitUntilHorizontalPodAutoscalerActiveContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, hpaName string, retries int, sleepBetweenRetries time.Duration) error {
+	statusMsg := fmt.Sprintf("Wait for horizontal pod autoscaler %s to be active.", hpaName)
+	message, err := waitUntilWatchedContextE(
+		t,
+		ctx,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		watchHorizontalPodAutoscalers(t, options, nameSelector(hpaName)),
+		func() (string, error) {
+			hpa, err := GetHorizontalPodAutoscalerE(t, options, hpaName)
+			if err != nil {
+				return "", err
+			}
+			if !IsHorizontalPodAutoscalerActive(hpa) {
+				return "", NewHorizontalPodAutoscalerNotActiveError(hpa)
+			}
+			return "Horizontal pod autoscaler is now active", nil
+		},
+	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for HorizontalPodAutoscaler to be active: %s", err)
+		return err
+	}
+	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// IsHorizontalPodAutoscalerActive returns true if the horizontal pod autoscaler has a current value for each of its
+// metrics and its ScalingActive condition is true, i.e. it is able to compute the desired number of replicas.
+func IsHorizontalPodAutoscalerActive(hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	if hpa.Status.ObservedGeneration == nil || *hpa.Status.ObservedGeneration < hpa.Generation {
+		return false
+	}
+	if len(hpa.Status.CurrentMetrics) < len(hpa.Spec.Metrics) {
+		return false
+	}
+	condition := getHorizontalPodAutoscalerCondition(hpa, autoscalingv2.ScalingActive)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+func getHorizontalPodAutoscalerCondition(hpa *autoscalingv2.HorizontalPodAutoscaler, conditionType autoscalingv2.HorizontalPodAutoscalerConditionType) *autoscalingv2.HorizontalPodAutoscalerCondition {
+	for i := range hpa.Status.Conditions {
+		if hpa.Status.Conditions[i].Type == conditionType {
+			return &hpa.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// watchHorizontalPodAutoscalers returns a function that watches the horizontal pod autoscalers that match the given
+// filters, for waits.
+func watchHorizontalPodAutoscalers(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.AutoscalingV2().HorizontalPodAutoscalers(options.Namespace).Watch(ctx, filters)
+	}
+}
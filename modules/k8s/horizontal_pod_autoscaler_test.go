@@ -0,0 +1,175 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestGetHorizontalPodAutoscalerEReturnsError(t *testing.T) {
+	t.Parallel()
+
+	options := NewKubectlOptions("", "", "")
+	_, err := GetHorizontalPodAutoscalerE(t, options, "nginx-hpa")
+	require.Error(t, err)
+}
+
+func TestListHorizontalPodAutoscalers(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleHorizontalPodAutoscalerYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	hpas := ListHorizontalPodAutoscalers(t, options, metav1.ListOptions{})
+	require.Equal(t, len(hpas), 1)
+
+	hpa := GetHorizontalPodAutoscaler(t, options, "nginx-hpa")
+	require.Equal(t, hpa.Name, "nginx-hpa")
+	require.Equal(t, hpa.Spec.ScaleTargetRef.Name, "nginx-deployment")
+}
+
+func TestIsHorizontalPodAutoscalerActive(t *testing.T) {
+	t.Parallel()
+
+	observedGeneration := int64(1)
+	cpuMetric := autoscalingv2.MetricSpec{
+		Type:     autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{Name: corev1.ResourceCPU},
+	}
+	cpuMetricStatus := autoscalingv2.MetricStatus{
+		Type:     autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricStatus{Name: corev1.ResourceCPU},
+	}
+	testCases := []struct {
+		title          string
+		hpa            *autoscalingv2.HorizontalPodAutoscaler
+		expectedResult bool
+	}{
+		{
+			title: "ActiveWithCurrentMetrics",
+			hpa: &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Generation: 1},
+				Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{Metrics: []autoscalingv2.MetricSpec{cpuMetric}},
+				Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+					ObservedGeneration: &observedGeneration,
+					CurrentMetrics:     []autoscalingv2.MetricStatus{cpuMetricStatus},
+					Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+						{Type: autoscalingv2.ScalingActive, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			expectedResult: true,
+		},
+		{
+			title: "MissingCurrentMetrics",
+			hpa: &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Generation: 1},
+				Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{Metrics: []autoscalingv2.MetricSpec{cpuMetric}},
+				Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+					ObservedGeneration: &observedGeneration,
+					Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+						{Type: autoscalingv2.ScalingActive, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "FailedGetResourceMetric",
+			hpa: &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Generation: 1},
+				Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{Metrics: []autoscalingv2.MetricSpec{cpuMetric}},
+				Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+					ObservedGeneration: &observedGeneration,
+					CurrentMetrics:     []autoscalingv2.MetricStatus{cpuMetricStatus},
+					Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+						{Type: autoscalingv2.ScalingActive, Status: corev1.ConditionFalse, Reason: "FailedGetResourceMetric"},
+					},
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "NotObserved",
+			hpa: &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Generation: 1},
+				Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{Metrics: []autoscalingv2.MetricSpec{cpuMetric}},
+			},
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expectedResult, IsHorizontalPodAutoscalerActive(tc.hpa))
+		})
+	}
+}
+
+const ExampleHorizontalPodAutoscalerYAMLTemplate = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: nginx
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15.7
+        resources:
+          requests:
+            cpu: 10m
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: nginx-hpa
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: nginx-deployment
+  minReplicas: 1
+  maxReplicas: 2
+  metrics:
+  - type: Resource
+    resource:
+      name: cpu
+      target:
+        type: Utilization
+        averageUtilization: 80
+`
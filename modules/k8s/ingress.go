@@ -9,8 +9,8 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
@@ -62,12 +62,31 @@ func IsIngressAvailable(ingress *networkingv1.Ingress) bool {
 
 // WaitUntilIngressAvailable waits until the Ingress resource has an endpoint provisioned for it.
 func WaitUntilIngressAvailable(t testing.TestingT, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) {
+	WaitUntilIngressAvailableContext(t, context.Background(), options, ingressName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilIngressAvailableE waits until the Ingress resource has an endpoint provisioned for it, retrying the check
+// for the specified amount of times, sleeping for the provided duration between each try.
+func WaitUntilIngressAvailableE(t testing.TestingT, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilIngressAvailableContextE(t, context.Background(), options, ingressName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilIngressAvailableContext is like WaitUntilIngressAvailable, but stops waiting as soon as the given context is done.
+// This will fail the test if the context is done or the retries are exhausted.
+func WaitUntilIngressAvailableContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilIngressAvailableContextE(t, ctx, options, ingressName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilIngressAvailableContextE is like WaitUntilIngressAvailableE, but stops waiting as soon as the given context is done.
+func WaitUntilIngressAvailableContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for ingress %s to be provisioned.", ingressName)
-	message := retry.DoWithRetry(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchIngresses(t, options, nameSelector(ingressName)),
 		func() (string, error) {
 			ingress, err := GetIngressE(t, options, ingressName)
 			if err != nil {
@@ -79,7 +98,12 @@ func WaitUntilIngressAvailable(t testing.TestingT, options *KubectlOptions, ingr
 			return "Ingress is now available", nil
 		},
 	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for Ingress to be provisioned: %s", err)
+		return err
+	}
 	options.Logger.Logf(t, "%s", message)
+	return nil
 }
 
 // ListIngressesV1Beta1 will look for Ingress resources in the given namespace that match the given filters and return
@@ -134,12 +158,31 @@ func IsIngressAvailableV1Beta1(ingress *networkingv1beta1.Ingress) bool {
 // WaitUntilIngressAvailableV1Beta1 waits until the Ingress resource has an endpoint provisioned for it, using
 // networking.k8s.io/v1beta1 API.
 func WaitUntilIngressAvailableV1Beta1(t testing.TestingT, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) {
+	WaitUntilIngressAvailableV1Beta1Context(t, context.Background(), options, ingressName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilIngressAvailableV1Beta1E waits until the Ingress resource has an endpoint provisioned for it, retrying the
+// check for the specified amount of times, sleeping for the provided duration between each try.
+func WaitUntilIngressAvailableV1Beta1E(t testing.TestingT, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilIngressAvailableV1Beta1ContextE(t, context.Background(), options, ingressName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilIngressAvailableV1Beta1Context is like WaitUntilIngressAvailableV1Beta1, but stops waiting as soon as the given context is done.
+// This will fail the test if the context is done or the retries are exhausted.
+func WaitUntilIngressAvailableV1Beta1Context(t testing.TestingT, ctx context.Context, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilIngressAvailableV1Beta1ContextE(t, ctx, options, ingressName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilIngressAvailableV1Beta1ContextE is like WaitUntilIngressAvailableV1Beta1E, but stops waiting as soon as the given context is done.
+func WaitUntilIngressAvailableV1Beta1ContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, ingressName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for ingress %s to be provisioned.", ingressName)
-	message := retry.DoWithRetry(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchIngressesV1Beta1(t, options, nameSelector(ingressName)),
 		func() (string, error) {
 			ingress, err := GetIngressV1Beta1E(t, options, ingressName)
 			if err != nil {
@@ -151,5 +194,32 @@ func WaitUntilIngressAvailableV1Beta1(t testing.TestingT, options *KubectlOption
 			return "Ingress is now available", nil
 		},
 	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for Ingress to be provisioned: %s", err)
+		return err
+	}
 	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// watchIngresses returns a function that watches the ingresses that match the given filters, for waits.
+func watchIngresses(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.NetworkingV1().Ingresses(options.Namespace).Watch(ctx, filters)
+	}
+}
+
+// watchIngressesV1Beta1 returns a function that watches the v1beta1 ingresses that match the given filters, for waits.
+func watchIngressesV1Beta1(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.NetworkingV1beta1().Ingresses(options.Namespace).Watch(ctx, filters)
+	}
 }
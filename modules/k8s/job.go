@@ -9,8 +9,8 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
@@ -62,12 +62,25 @@ func WaitUntilJobSucceed(t testing.TestingT, options *KubectlOptions, jobName st
 // WaitUntilJobSucceedE waits until requested job is succeeded, retrying the check for the specified amount of times, sleeping
 // for the provided duration between each try.
 func WaitUntilJobSucceedE(t testing.TestingT, options *KubectlOptions, jobName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilJobSucceedContextE(t, context.Background(), options, jobName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilJobSucceedContext is like WaitUntilJobSucceed, but stops waiting as soon as the given context is done.
+// This will fail the test if there is an error or if the check times out.
+func WaitUntilJobSucceedContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, jobName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilJobSucceedContextE(t, ctx, options, jobName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilJobSucceedContextE is like WaitUntilJobSucceedE, but stops waiting as soon as the given context is done.
+func WaitUntilJobSucceedContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, jobName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for job %s to be provisioned.", jobName)
-	message, err := retry.DoWithRetryE(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchJobs(t, options, nameSelector(jobName)),
 		func() (string, error) {
 			job, err := GetJobE(t, options, jobName)
 			if err != nil {
@@ -137,3 +150,14 @@ func CreateJobFromCronJobE(t testing.TestingT, options *KubectlOptions, cronJobN
 	createdJob, err := clientset.BatchV1().Jobs(options.Namespace).Create(context.Background(), job, metav1.CreateOptions{})
 	return createdJob, err
 }
+
+// watchJobs returns a function that watches the jobs that match the given filters, for waits.
+func watchJobs(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.BatchV1().Jobs(options.Namespace).Watch(ctx, filters)
+	}
+}
@@ -24,6 +24,11 @@ func RunKubectlE(t testing.TestingT, options *KubectlOptions, args ...string) er
 // RunKubectlAndGetOutputE will call kubectl using the provided options and args, returning the output of stdout and
 // stderr.
 func RunKubectlAndGetOutputE(t testing.TestingT, options *KubectlOptions, args ...string) (string, error) {
+	return shell.RunCommandAndGetOutputE(t, newKubectlCommand(options, args...))
+}
+
+// newKubectlCommand returns the command that calls kubectl using the provided options and args.
+func newKubectlCommand(options *KubectlOptions, args ...string) shell.Command {
 	cmdArgs := []string{}
 	if options.ContextName != "" {
 		cmdArgs = append(cmdArgs, "--context", options.ContextName)
@@ -38,13 +43,12 @@ func RunKubectlAndGetOutputE(t testing.TestingT, options *KubectlOptions, args .
 		cmdArgs = append(cmdArgs, "--request-timeout", options.RequestTimeout.String())
 	}
 	cmdArgs = append(cmdArgs, args...)
-	command := shell.Command{
+	return shell.Command{
 		Command: "kubectl",
 		Args:    cmdArgs,
 		Env:     options.Env,
 		Logger:  options.Logger,
 	}
-	return shell.RunCommandAndGetOutputE(t, command)
 }
 
 // KubectlDelete will take in a file path and delete it from the cluster targeted by KubectlOptions. If there are any
@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ListLimitRanges will look for limit ranges in the given namespace that match the given filters and return them. This
+// will fail the test if there is an error.
+func ListLimitRanges(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) []corev1.LimitRange {
+	limitRanges, err := ListLimitRangesE(t, options, filters)
+	require.NoError(t, err)
+	return limitRanges
+}
+
+// ListLimitRangesE will look for limit ranges in the given namespace that match the given filters and return them.
+func ListLimitRangesE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) ([]corev1.LimitRange, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := clientset.CoreV1().LimitRanges(options.Namespace).List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetLimitRange returns a Kubernetes limit range resource in the provided namespace with the given name. This will fail
+// the test if there is an error.
+func GetLimitRange(t testing.TestingT, options *KubectlOptions, limitRangeName string) *corev1.LimitRange {
+	limitRange, err := GetLimitRangeE(t, options, limitRangeName)
+	require.NoError(t, err)
+	return limitRange
+}
+
+// GetLimitRangeE returns a Kubernetes limit range resource in the provided namespace with the given name.
+func GetLimitRangeE(t testing.TestingT, options *KubectlOptions, limitRangeName string) (*corev1.LimitRange, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.CoreV1().LimitRanges(options.Namespace).Get(context.Background(), limitRangeName, metav1.GetOptions{})
+}
@@ -0,0 +1,67 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestGetLimitRangeEReturnsErrorForNonExistantLimitRange(t *testing.T) {
+	t.Parallel()
+
+	options := NewKubectlOptions("", "", "default")
+	_, err := GetLimitRangeE(t, options, "non-existing-limit-range")
+	require.Error(t, err)
+}
+
+func TestGetLimitRangeReturnsCorrectLimitRangeInCorrectNamespace(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(EXAMPLE_LIMIT_RANGE_YAML_TEMPLATE, uniqueID, uniqueID)
+	defer KubectlDeleteFromString(t, options, configData)
+	KubectlApplyFromString(t, options, configData)
+
+	limitRange := GetLimitRange(t, options, "terratest-limit-range")
+	require.Equal(t, "terratest-limit-range", limitRange.Name)
+	require.Equal(t, uniqueID, limitRange.Namespace)
+
+	limitRanges := ListLimitRanges(t, options, metav1.ListOptions{})
+	require.Len(t, limitRanges, 1)
+	require.Equal(t, "terratest-limit-range", limitRanges[0].Name)
+}
+
+const EXAMPLE_LIMIT_RANGE_YAML_TEMPLATE = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: '%s'
+---
+apiVersion: v1
+kind: LimitRange
+metadata:
+  name: 'terratest-limit-range'
+  namespace: '%s'
+spec:
+  limits:
+  - type: Container
+    default:
+      cpu: 500m
+    defaultRequest:
+      cpu: 100m
+`
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ParseManifest returns the Kubernetes objects in the given YAML or JSON manifest, which may contain several
+// documents and objects of kind List, e.g. to wait until all the objects applied with KubectlApplyFromString are
+// ready with WaitUntilReady. This will fail the test if there is an error.
+func ParseManifest(t testing.TestingT, manifest string) []*unstructured.Unstructured {
+	objects, err := ParseManifestE(t, manifest)
+	require.NoError(t, err)
+	return objects
+}
+
+// ParseManifestE returns the Kubernetes objects in the given YAML or JSON manifest, which may contain several
+// documents and objects of kind List.
+func ParseManifestE(t testing.TestingT, manifest string) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewBufferString(manifest), 4096)
+
+	objects := []*unstructured.Unstructured{}
+	for {
+		var document json.RawMessage
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Empty documents, e.g. between two `---` separators
+		if len(document) == 0 || string(document) == "null" {
+			continue
+		}
+
+		// Unlike encoding/json, the unstructured decoder keeps integers as int64, as the API server returns them
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(document); err != nil {
+			return nil, err
+		}
+		if !object.IsList() {
+			objects = append(objects, object)
+			continue
+		}
+		list, err := object.ToList()
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	}
+}
+
+// ParseManifestFile returns the Kubernetes objects in the YAML or JSON manifest at the given path, e.g. to wait until
+// all the objects applied with KubectlApply are ready with WaitUntilReady. This will fail the test if there is an
+// error.
+func ParseManifestFile(t testing.TestingT, path string) []*unstructured.Unstructured {
+	objects, err := ParseManifestFileE(t, path)
+	require.NoError(t, err)
+	return objects
+}
+
+// ParseManifestFileE returns the Kubernetes objects in the YAML or JSON manifest at the given path.
+func ParseManifestFileE(t testing.TestingT, path string) ([]*unstructured.Unstructured, error) {
+	manifest, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseManifestE(t, string(manifest))
+}
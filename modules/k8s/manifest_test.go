@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+
+	objects := ParseManifest(t, `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: example
+---
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: first
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: second
+---
+{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "nginx", "namespace": "example"}}
+`)
+
+	require.Len(t, objects, 4)
+	assert.Equal(t, "Namespace", objects[0].GetKind())
+	assert.Equal(t, "first", objects[1].GetName())
+	assert.Equal(t, "second", objects[2].GetName())
+	assert.Equal(t, "apps/v1", objects[3].GetAPIVersion())
+	assert.Equal(t, "example", objects[3].GetNamespace())
+}
+
+func TestParseManifestEMalformed(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseManifestE(t, "kind: [ConfigMap")
+	require.Error(t, err)
+}
@@ -2,12 +2,16 @@ package k8s
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // CreateNamespace will create a new Kubernetes namespace on the cluster targeted by the provided options. This will
@@ -81,6 +85,62 @@ func DeleteNamespaceE(t testing.TestingT, options *KubectlOptions, namespaceName
 	return clientset.CoreV1().Namespaces().Delete(context.Background(), namespaceName, metav1.DeleteOptions{})
 }
 
+// WaitUntilNamespaceDeleted waits until the namespace with the given name is gone from the cluster, retrying the check
+// for the specified amount of times, sleeping for the provided duration between each try. Namespaces are deleted
+// asynchronously, after all the objects in them are. This will fail the test if the namespace is still there after the
+// retries.
+func WaitUntilNamespaceDeleted(t testing.TestingT, options *KubectlOptions, namespaceName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilNamespaceDeletedE(t, options, namespaceName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilNamespaceDeletedE waits until the namespace with the given name is gone from the cluster, retrying the check
+// for the specified amount of times, sleeping for the provided duration between each try.
+func WaitUntilNamespaceDeletedE(t testing.TestingT, options *KubectlOptions, namespaceName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilNamespaceDeletedContextE(t, context.Background(), options, namespaceName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilNamespaceDeletedContext is like WaitUntilNamespaceDeleted, but stops waiting as soon as the given context is done.
+// This will fail the test if there is an error or if the check times out.
+func WaitUntilNamespaceDeletedContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, namespaceName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilNamespaceDeletedContextE(t, ctx, options, namespaceName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilNamespaceDeletedContextE is like WaitUntilNamespaceDeletedE, but stops waiting as soon as the given context is done.
+func WaitUntilNamespaceDeletedContextE(
+	t testing.TestingT,
+	ctx context.Context,
+	options *KubectlOptions,
+	namespaceName string,
+	retries int,
+	sleepBetweenRetries time.Duration,
+) error {
+	statusMsg := fmt.Sprintf("Wait for namespace %s to be deleted", namespaceName)
+	message, err := waitUntilWatchedContextE(
+		t,
+		ctx,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		watchNamespaces(t, options, nameSelector(namespaceName)),
+		func() (string, error) {
+			namespace, err := GetNamespaceE(t, options, namespaceName)
+			if apierrors.IsNotFound(err) {
+				return "Namespace is now deleted", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			return "", fmt.Errorf("Namespace %s is still %s", namespaceName, namespace.Status.Phase)
+		},
+	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for namespace to be deleted: %s", err)
+		return err
+	}
+	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
 // ListNamespaces will list all namespaces in the Kubernetes cluster targeted by the provided options.
 // This will fail the test if there is an error in listing the namespaces.
 func ListNamespaces(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) []corev1.Namespace {
@@ -114,3 +174,14 @@ func ListNamespacesE(t testing.TestingT, options *KubectlOptions, filters metav1
 
 	return namespaceList.Items, nil
 }
+
+// watchNamespaces returns a function that watches the namespaces that match the given filters, for waits.
+func watchNamespaces(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.CoreV1().Namespaces().Watch(ctx, filters)
+	}
+}
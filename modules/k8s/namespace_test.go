@@ -10,11 +10,14 @@
 package k8s
 
 import (
+	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/gruntwork-io/terratest/modules/random"
@@ -85,3 +88,20 @@ func TestListNamespaces(t *testing.T) {
 		require.True(t, found, "Should find the created namespace in the list")
 	})
 }
+
+func TestWaitUntilNamespaceDeleted(t *testing.T) {
+	t.Parallel()
+
+	uniqueId := random.UniqueId()
+	namespaceName := strings.ToLower(uniqueId)
+	options := NewKubectlOptions("", "", namespaceName)
+	CreateNamespace(t, options, namespaceName)
+	DeleteNamespace(t, options, namespaceName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	WaitUntilNamespaceDeletedContext(t, ctx, options, namespaceName, 60, 5*time.Second)
+
+	_, err := GetNamespaceE(t, options, namespaceName)
+	require.True(t, apierrors.IsNotFound(err))
+}
@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // GetNetworkPolicy returns a Kubernetes networkpolicy resource in the provided namespace with the given name. The namespace used
@@ -33,12 +33,31 @@ func GetNetworkPolicyE(t testing.TestingT, options *KubectlOptions, networkPolic
 // WaitUntilNetworkPolicyAvailable waits until the networkpolicy is present on the cluster in cases where it is not immediately
 // available (for example, when using ClusterIssuer to request a certificate).
 func WaitUntilNetworkPolicyAvailable(t testing.TestingT, options *KubectlOptions, networkPolicyName string, retries int, sleepBetweenRetries time.Duration) {
+	WaitUntilNetworkPolicyAvailableContext(t, context.Background(), options, networkPolicyName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilNetworkPolicyAvailableE waits until the networkpolicy is present on the cluster, retrying the check for the
+// specified amount of times, sleeping for the provided duration between each try.
+func WaitUntilNetworkPolicyAvailableE(t testing.TestingT, options *KubectlOptions, networkPolicyName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilNetworkPolicyAvailableContextE(t, context.Background(), options, networkPolicyName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilNetworkPolicyAvailableContext is like WaitUntilNetworkPolicyAvailable, but stops waiting as soon as the given context is done.
+// This will fail the test if the context is done or the retries are exhausted.
+func WaitUntilNetworkPolicyAvailableContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, networkPolicyName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilNetworkPolicyAvailableContextE(t, ctx, options, networkPolicyName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilNetworkPolicyAvailableContextE is like WaitUntilNetworkPolicyAvailableE, but stops waiting as soon as the given context is done.
+func WaitUntilNetworkPolicyAvailableContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, networkPolicyName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for networkpolicy %s to be provisioned.", networkPolicyName)
-	message := retry.DoWithRetry(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchNetworkPolicies(t, options, nameSelector(networkPolicyName)),
 		func() (string, error) {
 			_, err := GetNetworkPolicyE(t, options, networkPolicyName)
 			if err != nil {
@@ -48,5 +67,21 @@ func WaitUntilNetworkPolicyAvailable(t testing.TestingT, options *KubectlOptions
 			return "networkpolicy is now available", nil
 		},
 	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for NetworkPolicy to be provisioned: %s", err)
+		return err
+	}
 	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// watchNetworkPolicies returns a function that watches the network policies that match the given filters, for waits.
+func watchNetworkPolicies(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.NetworkingV1().NetworkPolicies(options.Namespace).Watch(ctx, filters)
+	}
 }
@@ -8,8 +8,8 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
@@ -88,11 +88,24 @@ func WaitUntilAllNodesReady(t testing.TestingT, options *KubectlOptions, retries
 // WaitUntilAllNodesReadyE continuously polls the Kubernetes cluster until all nodes in the cluster reach the ready
 // state, or runs out of retries.
 func WaitUntilAllNodesReadyE(t testing.TestingT, options *KubectlOptions, retries int, sleepBetweenRetries time.Duration) error {
-	message, err := retry.DoWithRetryE(
+	return WaitUntilAllNodesReadyContextE(t, context.Background(), options, retries, sleepBetweenRetries)
+}
+
+// WaitUntilAllNodesReadyContext is like WaitUntilAllNodesReady, but stops waiting as soon as the given context is done.
+// This will fail the test if there is an error or if the check times out.
+func WaitUntilAllNodesReadyContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilAllNodesReadyContextE(t, ctx, options, retries, sleepBetweenRetries))
+}
+
+// WaitUntilAllNodesReadyContextE is like WaitUntilAllNodesReadyE, but stops waiting as soon as the given context is done.
+func WaitUntilAllNodesReadyContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, retries int, sleepBetweenRetries time.Duration) error {
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		"Wait for all Kube Nodes to be ready",
 		retries,
 		sleepBetweenRetries,
+		watchNodes(t, options, metav1.ListOptions{}),
 		func() (string, error) {
 			_, err := AreAllNodesReadyE(t, options)
 			if err != nil {
@@ -128,3 +141,14 @@ func AreAllNodesReadyE(t testing.TestingT, options *KubectlOptions) (bool, error
 	}
 	return true, nil
 }
+
+// watchNodes returns a function that watches the nodes that match the given filters, for waits.
+func watchNodes(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.CoreV1().Nodes().Watch(ctx, filters)
+	}
+}
@@ -9,8 +9,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
@@ -70,13 +70,34 @@ func WaitUntilPersistentVolumeInStatusE(
 	pvStatusPhase *corev1.PersistentVolumePhase,
 	retries int,
 	sleepBetweenRetries time.Duration,
+) error {
+	return WaitUntilPersistentVolumeInStatusContextE(t, context.Background(), options, pvName, pvStatusPhase, retries, sleepBetweenRetries)
+}
+
+// WaitUntilPersistentVolumeInStatusContext is like WaitUntilPersistentVolumeInStatus, but stops waiting as soon as the given context is done.
+// This will fail the test if there is an error or if the check times out.
+func WaitUntilPersistentVolumeInStatusContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, pvName string, pvStatusPhase *corev1.PersistentVolumePhase, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilPersistentVolumeInStatusContextE(t, ctx, options, pvName, pvStatusPhase, retries, sleepBetweenRetries))
+}
+
+// WaitUntilPersistentVolumeInStatusContextE is like WaitUntilPersistentVolumeInStatusE, but stops waiting as soon as the given context is done.
+func WaitUntilPersistentVolumeInStatusContextE(
+	t testing.TestingT,
+	ctx context.Context,
+	options *KubectlOptions,
+	pvName string,
+	pvStatusPhase *corev1.PersistentVolumePhase,
+	retries int,
+	sleepBetweenRetries time.Duration,
 ) error {
 	statusMsg := fmt.Sprintf("Wait for Persistent Volume %s to be '%s'", pvName, *pvStatusPhase)
-	message, err := retry.DoWithRetryE(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchPersistentVolumes(t, options, nameSelector(pvName)),
 		func() (string, error) {
 			pv, err := GetPersistentVolumeE(t, options, pvName)
 			if err != nil {
@@ -100,3 +121,14 @@ func WaitUntilPersistentVolumeInStatusE(
 func IsPersistentVolumeInStatus(pv *corev1.PersistentVolume, pvStatusPhase *corev1.PersistentVolumePhase) bool {
 	return pv != nil && pv.Status.Phase == *pvStatusPhase
 }
+
+// watchPersistentVolumes returns a function that watches the persistent volumes that match the given filters, for waits.
+func watchPersistentVolumes(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.CoreV1().PersistentVolumes().Watch(ctx, filters)
+	}
+}
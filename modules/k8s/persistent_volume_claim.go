@@ -9,9 +9,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/gruntwork-io/terratest/modules/logger"
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
@@ -67,12 +67,25 @@ func WaitUntilPersistentVolumeClaimInStatus(t testing.TestingT, options *Kubectl
 // for the provided duration between each try.
 // This will fail the test if there is an error.
 func WaitUntilPersistentVolumeClaimInStatusE(t testing.TestingT, options *KubectlOptions, pvcName string, pvcStatusPhase *corev1.PersistentVolumeClaimPhase, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilPersistentVolumeClaimInStatusContextE(t, context.Background(), options, pvcName, pvcStatusPhase, retries, sleepBetweenRetries)
+}
+
+// WaitUntilPersistentVolumeClaimInStatusContext is like WaitUntilPersistentVolumeClaimInStatus, but stops waiting as soon as the given context is done.
+// This will fail the test if there is an error or if the check times out.
+func WaitUntilPersistentVolumeClaimInStatusContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, pvcName string, pvcStatusPhase *corev1.PersistentVolumeClaimPhase, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilPersistentVolumeClaimInStatusContextE(t, ctx, options, pvcName, pvcStatusPhase, retries, sleepBetweenRetries))
+}
+
+// WaitUntilPersistentVolumeClaimInStatusContextE is like WaitUntilPersistentVolumeClaimInStatusE, but stops waiting as soon as the given context is done.
+func WaitUntilPersistentVolumeClaimInStatusContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, pvcName string, pvcStatusPhase *corev1.PersistentVolumeClaimPhase, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for PersistentVolumeClaim %s to be '%s'.", pvcName, *pvcStatusPhase)
-	message, err := retry.DoWithRetryE(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchPersistentVolumeClaims(t, options, nameSelector(pvcName)),
 		func() (string, error) {
 			pvc, err := GetPersistentVolumeClaimE(t, options, pvcName)
 			if err != nil {
@@ -96,3 +109,14 @@ func WaitUntilPersistentVolumeClaimInStatusE(t testing.TestingT, options *Kubect
 func IsPersistentVolumeClaimInStatus(pvc *corev1.PersistentVolumeClaim, pvcStatusPhase *corev1.PersistentVolumeClaimPhase) bool {
 	return pvc != nil && pvc.Status.Phase == *pvcStatusPhase
 }
+
+// watchPersistentVolumeClaims returns a function that watches the persistent volume claims that match the given filters, for waits.
+func watchPersistentVolumeClaims(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.CoreV1().PersistentVolumeClaims(options.Namespace).Watch(ctx, filters)
+	}
+}
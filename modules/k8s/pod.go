@@ -8,8 +8,8 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
@@ -75,13 +75,34 @@ func WaitUntilNumPodsCreatedE(
 	desiredCount int,
 	retries int,
 	sleepBetweenRetries time.Duration,
+) error {
+	return WaitUntilNumPodsCreatedContextE(t, context.Background(), options, filters, desiredCount, retries, sleepBetweenRetries)
+}
+
+// WaitUntilNumPodsCreatedContext is like WaitUntilNumPodsCreated, but stops waiting as soon as the given context is done.
+// This will fail the test if there is an error or if the check times out.
+func WaitUntilNumPodsCreatedContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, filters metav1.ListOptions, desiredCount int, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilNumPodsCreatedContextE(t, ctx, options, filters, desiredCount, retries, sleepBetweenRetries))
+}
+
+// WaitUntilNumPodsCreatedContextE is like WaitUntilNumPodsCreatedE, but stops waiting as soon as the given context is done.
+func WaitUntilNumPodsCreatedContextE(
+	t testing.TestingT,
+	ctx context.Context,
+	options *KubectlOptions,
+	filters metav1.ListOptions,
+	desiredCount int,
+	retries int,
+	sleepBetweenRetries time.Duration,
 ) error {
 	statusMsg := fmt.Sprintf("Wait for num pods created to match desired count %d.", desiredCount)
-	message, err := retry.DoWithRetryE(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchPods(t, options, filters),
 		func() (string, error) {
 			pods, err := ListPodsE(t, options, filters)
 			if err != nil {
@@ -110,12 +131,25 @@ func WaitUntilPodAvailable(t testing.TestingT, options *KubectlOptions, podName
 // WaitUntilPodAvailableE waits until all of the containers within the pod are ready and started, retrying the check for the specified amount of times, sleeping
 // for the provided duration between each try.
 func WaitUntilPodAvailableE(t testing.TestingT, options *KubectlOptions, podName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilPodAvailableContextE(t, context.Background(), options, podName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilPodAvailableContext is like WaitUntilPodAvailable, but stops waiting as soon as the given context is done.
+// This will fail the test if there is an error or if the check times out.
+func WaitUntilPodAvailableContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, podName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilPodAvailableContextE(t, ctx, options, podName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilPodAvailableContextE is like WaitUntilPodAvailableE, but stops waiting as soon as the given context is done.
+func WaitUntilPodAvailableContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, podName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for pod %s to be provisioned.", podName)
-	message, err := retry.DoWithRetryE(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchPods(t, options, nameSelector(podName)),
 		func() (string, error) {
 			pod, err := GetPodE(t, options, podName)
 			if err != nil {
@@ -196,3 +230,14 @@ func ExecPodE(t testing.TestingT, options *KubectlOptions, podName string, conta
 	}
 	return RunKubectlAndGetOutputE(t, options, args...)
 }
+
+// watchPods returns a function that watches the pods that match the given filters, for waits.
+func watchPods(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.CoreV1().Pods(options.Namespace).Watch(ctx, filters)
+	}
+}
@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ListPodDisruptionBudgets will look for pod disruption budgets in the given namespace that match the given filters and
+// return them. This will fail the test if there is an error.
+func ListPodDisruptionBudgets(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) []policyv1.PodDisruptionBudget {
+	pdbs, err := ListPodDisruptionBudgetsE(t, options, filters)
+	require.NoError(t, err)
+	return pdbs
+}
+
+// ListPodDisruptionBudgetsE will look for pod disruption budgets in the given namespace that match the given filters
+// and return them.
+func ListPodDisruptionBudgetsE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) ([]policyv1.PodDisruptionBudget, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := clientset.PolicyV1().PodDisruptionBudgets(options.Namespace).List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetPodDisruptionBudget returns a Kubernetes pod disruption budget resource in the provided namespace with the given
+// name. This will fail the test if there is an error.
+func GetPodDisruptionBudget(t testing.TestingT, options *KubectlOptions, pdbName string) *policyv1.PodDisruptionBudget {
+	pdb, err := GetPodDisruptionBudgetE(t, options, pdbName)
+	require.NoError(t, err)
+	return pdb
+}
+
+// GetPodDisruptionBudgetE returns a Kubernetes pod disruption budget resource in the provided namespace with the given
+// name.
+func GetPodDisruptionBudgetE(t testing.TestingT, options *KubectlOptions, pdbName string) (*policyv1.PodDisruptionBudget, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.PolicyV1().PodDisruptionBudgets(options.Namespace).Get(context.Background(), pdbName, metav1.GetOptions{})
+}
+
+// WaitUntilPodDisruptionBudgetAvailable waits until the pod disruption budget has as many healthy pods as it requires,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try. This will
+// fail the test if there is an error or if the check times out.
+func WaitUntilPodDisruptionBudgetAvailable(t testing.TestingT, options *KubectlOptions, pdbName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilPodDisruptionBudgetAvailableE(t, options, pdbName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilPodDisruptionBudgetAvailableE waits until the pod disruption budget has as many healthy pods as it requires,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try.
+func WaitUntilPodDisruptionBudgetAvailableE(t testing.TestingT, options *KubectlOptions, pdbName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilPodDisruptionBudgetAvailableContextE(t, context.Background(), options, pdbName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilPodDisruptionBudgetAvailableContext is like WaitUntilPodDisruptionBudgetAvailable, but stops waiting as soon
+// as the given context is done. This will fail the test if there is an error or if the check times out.
+func WaitUntilPodDisruptionBudgetAvailableContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, pdbName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilPodDisruptionBudgetAvailableContextE(t, ctx, options, pdbName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilPodDisruptionBudgetAvailableContextE is like WaitUntilPodDisruptionBudgetAvailableE, but stops waiting as
+// soon as the given context is done.
+func WaitUntilPodDisruptionBudgetAvailableContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, pdbName string, retries int, sleepBetweenRetries time.Duration) error {
+	statusMsg := fmt.Sprintf("Wait for pod disruption budget %s to be available.", pdbName)
+	message, err := waitUntilWatchedContextE(
+		t,
+		ctx,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		watchPodDisruptionBudgets(t, options, nameSelector(pdbName)),
+		func() (string, error) {
+			pdb, err := GetPodDisruptionBudgetE(t, options, pdbName)
+			if err != nil {
+				return "", err
+			}
+			if !IsPodDisruptionBudgetAvailable(pdb) {
+				return "", NewPodDisruptionBudgetNotAvailableError(pdb)
+			}
+			return "Pod disruption budget is now available", nil
+		},
+	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for PodDisruptionBudget to be available: %s", err)
+		return err
+	}
+	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// IsPodDisruptionBudgetAvailable returns true if the disruption controller has observed the latest spec of the pod
+// disruption budget and at least as many of the pods it covers are healthy as it requires.
+func IsPodDisruptionBudgetAvailable(pdb *policyv1.PodDisruptionBudget) bool {
+	return pdb.Status.ObservedGeneration >= pdb.Generation &&
+		pdb.Status.ExpectedPods > 0 &&
+		pdb.Status.CurrentHealthy >= pdb.Status.DesiredHealthy
+}
+
+// watchPodDisruptionBudgets returns a function that watches the pod disruption budgets that match the given filters,
+// for waits.
+func watchPodDisruptionBudgets(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.PolicyV1().PodDisruptionBudgets(options.Namespace).Watch(ctx, filters)
+	}
+}
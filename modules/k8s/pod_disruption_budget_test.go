@@ -0,0 +1,123 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestGetPodDisruptionBudgetEReturnsError(t *testing.T) {
+	t.Parallel()
+
+	options := NewKubectlOptions("", "", "")
+	_, err := GetPodDisruptionBudgetE(t, options, "nginx-pdb")
+	require.Error(t, err)
+}
+
+func TestWaitUntilPodDisruptionBudgetAvailable(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExamplePodDisruptionBudgetYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	WaitUntilPodDisruptionBudgetAvailable(t, options, "nginx-pdb", 60, 1*time.Second)
+
+	pdbs := ListPodDisruptionBudgets(t, options, metav1.ListOptions{})
+	require.Equal(t, len(pdbs), 1)
+	require.Equal(t, int32(2), pdbs[0].Status.CurrentHealthy)
+	require.Equal(t, int32(1), pdbs[0].Status.DisruptionsAllowed)
+}
+
+func TestIsPodDisruptionBudgetAvailable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		title          string
+		pdb            *policyv1.PodDisruptionBudget
+		expectedResult bool
+	}{
+		{
+			title: "EnoughHealthyPods",
+			pdb: &policyv1.PodDisruptionBudget{
+				Status: policyv1.PodDisruptionBudgetStatus{ExpectedPods: 2, CurrentHealthy: 2, DesiredHealthy: 1},
+			},
+			expectedResult: true,
+		},
+		{
+			title: "NotEnoughHealthyPods",
+			pdb: &policyv1.PodDisruptionBudget{
+				Status: policyv1.PodDisruptionBudgetStatus{ExpectedPods: 2, CurrentHealthy: 0, DesiredHealthy: 1},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "NoPods",
+			pdb: &policyv1.PodDisruptionBudget{
+				Status: policyv1.PodDisruptionBudgetStatus{},
+			},
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expectedResult, IsPodDisruptionBudgetAvailable(tc.pdb))
+		})
+	}
+}
+
+const ExamplePodDisruptionBudgetYAMLTemplate = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: nginx
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15.7
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: nginx-pdb
+spec:
+  minAvailable: 1
+  selector:
+    matchLabels:
+      app: nginx
+`
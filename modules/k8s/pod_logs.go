@@ -0,0 +1,302 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// maxLogLineSize is the size of the longest log line PodLogFollower reads, beyond which lines are split.
+const maxLogLineSize = 1024 * 1024
+
+// PodLogLine is a single line of the logs of a container.
+type PodLogLine struct {
+	Pod       string
+	Container string
+	Text      string
+}
+
+// PodLogFollower follows the logs of all the containers of the pods that match a selector, started with FollowPodLogs.
+// Pods that match the selector later on, and containers that restart, are followed as well. Every line is logged with
+// the Logger of the options, prefixed with the pod and container it comes from.
+type PodLogFollower struct {
+	t        testing.TestingT
+	options  *KubectlOptions
+	filters  metav1.ListOptions
+	cancel   context.CancelFunc
+	streams  sync.WaitGroup
+	watching chan struct{}
+
+	mutex sync.Mutex
+	// newLine is broadcast whenever a line is appended to lines and when the follower stops.
+	newLine *sync.Cond
+	lines   []PodLogLine
+	stopped bool
+	// followed holds the IDs of the containers whose logs are followed, so that each is followed once.
+	followed map[string]bool
+}
+
+// FollowPodLogs starts following the logs of all the containers of the pods in the namespace of the options that
+// match the given filters, e.g. a label selector, from the start of their logs. If the given testing.TestingT supports
+// Cleanup (as Go's testing.T does), the follower is stopped when the test finishes. This will fail the test if there
+// is an error.
+func FollowPodLogs(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) *PodLogFollower {
+	follower, err := FollowPodLogsE(t, options, filters)
+	require.NoError(t, err)
+	return follower
+}
+
+// FollowPodLogsE starts following the logs of all the containers of the pods in the namespace of the options that
+// match the given filters, from the start of their logs. If the given testing.TestingT supports Cleanup (as Go's
+// testing.T does), the follower is stopped when the test finishes.
+func FollowPodLogsE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) (*PodLogFollower, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	// List the pods once up front, so that errors such as a wrong selector are returned rather than retried
+	podList, err := clientset.CoreV1().Pods(options.Namespace).List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	follower := &PodLogFollower{
+		t:        t,
+		options:  options,
+		filters:  filters,
+		cancel:   cancel,
+		watching: make(chan struct{}),
+		followed: map[string]bool{},
+	}
+	follower.newLine = sync.NewCond(&follower.mutex)
+
+	options.Logger.Logf(t, "Following logs of pods matching %s in namespace %s", selectorDescription(filters), options.Namespace)
+	for i := range podList.Items {
+		follower.followContainers(ctx, &podList.Items[i])
+	}
+	go follower.watch(ctx, podList.ResourceVersion)
+
+	if tt, ok := t.(cleaner); ok {
+		tt.Cleanup(follower.Stop)
+	}
+	return follower, nil
+}
+
+// Lines returns a copy of all the lines the followed containers have logged so far, in the order they were read.
+func (follower *PodLogFollower) Lines() []PodLogLine {
+	follower.mutex.Lock()
+	defer follower.mutex.Unlock()
+
+	lines := make([]PodLogLine, len(follower.lines))
+	copy(lines, follower.lines)
+	return lines
+}
+
+// WaitUntilLogLineMatches waits until one of the followed containers logs a line that matches the given regular
+// expression, and returns that line. Lines logged before WaitUntilLogLineMatches was called count as well. This will
+// fail the test if no such line appears before the timeout passes or the follower is stopped.
+func (follower *PodLogFollower) WaitUntilLogLineMatches(t testing.TestingT, regex string, timeout time.Duration) PodLogLine {
+	line, err := follower.WaitUntilLogLineMatchesE(t, regex, timeout)
+	require.NoError(t, err)
+	return line
+}
+
+// WaitUntilLogLineMatchesE waits until one of the followed containers logs a line that matches the given regular
+// expression, and returns that line. Lines logged before WaitUntilLogLineMatchesE was called count as well. If no such
+// line appears before the timeout passes or the follower is stopped, return a LogLineNotFound error.
+func (follower *PodLogFollower) WaitUntilLogLineMatchesE(t testing.TestingT, regex string, timeout time.Duration) (PodLogLine, error) {
+	pattern, err := regexp.Compile(regex)
+	if err != nil {
+		return PodLogLine{}, err
+	}
+
+	follower.options.Logger.Logf(t, "Waiting up to %s for pods matching %s to log a line matching %s", timeout, selectorDescription(follower.filters), regex)
+
+	timedOut := false
+	timer := time.AfterFunc(timeout, func() {
+		follower.mutex.Lock()
+		defer follower.mutex.Unlock()
+		timedOut = true
+		follower.newLine.Broadcast()
+	})
+	defer timer.Stop()
+
+	follower.mutex.Lock()
+	defer follower.mutex.Unlock()
+
+	for next := 0; ; {
+		for ; next < len(follower.lines); next++ {
+			if pattern.MatchString(follower.lines[next].Text) {
+				return follower.lines[next], nil
+			}
+		}
+		if follower.stopped || timedOut {
+			return PodLogLine{}, LogLineNotFound{Selector: selectorDescription(follower.filters), Regex: regex, Timeout: timeout}
+		}
+		follower.newLine.Wait()
+	}
+}
+
+// Stop stops following the logs and waits until all the streams are closed. It is safe to call Stop several times.
+func (follower *PodLogFollower) Stop() {
+	follower.cancel()
+	<-follower.watching
+	follower.streams.Wait()
+
+	follower.mutex.Lock()
+	defer follower.mutex.Unlock()
+	follower.stopped = true
+	follower.newLine.Broadcast()
+}
+
+// WaitUntilLogLineMatches follows the logs of all the containers of the pods in the namespace of the options that match
+// the given filters, including pods that are created while waiting, until one of them logs a line that matches the
+// given regular expression, and returns that line. Lines logged before WaitUntilLogLineMatches was called count as
+// well. This will fail the test if there is an error or if no such line appears before the timeout passes.
+func WaitUntilLogLineMatches(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions, regex string, timeout time.Duration) PodLogLine {
+	line, err := WaitUntilLogLineMatchesE(t, options, filters, regex, timeout)
+	require.NoError(t, err)
+	return line
+}
+
+// WaitUntilLogLineMatchesE follows the logs of all the containers of the pods in the namespace of the options that
+// match the given filters until one of them logs a line that matches the given regular expression, and returns that
+// line. If no such line appears before the timeout passes, return a LogLineNotFound error.
+func WaitUntilLogLineMatchesE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions, regex string, timeout time.Duration) (PodLogLine, error) {
+	follower, err := FollowPodLogsE(t, options, filters)
+	if err != nil {
+		return PodLogLine{}, err
+	}
+	defer follower.Stop()
+	return follower.WaitUntilLogLineMatchesE(t, regex, timeout)
+}
+
+// watch follows the containers of the pods that match the filters as they start, until the given context is done.
+func (follower *PodLogFollower) watch(ctx context.Context, resourceVersion string) {
+	defer close(follower.watching)
+
+	for ctx.Err() == nil {
+		filters := follower.filters
+		filters.ResourceVersion = resourceVersion
+		watcher, err := watchPods(follower.t, follower.options, filters)(ctx)
+		if err != nil {
+			follower.options.Logger.Logf(follower.t, "Error watching pods matching %s, retrying: %s", selectorDescription(follower.filters), err)
+			resourceVersion = ""
+			sleepContext(ctx, time.Second)
+			continue
+		}
+
+		for event := range watcher.ResultChan() {
+			if event.Type == watch.Error {
+				// The resource version is too old, e.g. after a long disconnection
+				resourceVersion = ""
+				break
+			}
+			pod, isPod := event.Object.(*corev1.Pod)
+			if !isPod {
+				continue
+			}
+			resourceVersion = pod.ResourceVersion
+			if event.Type == watch.Added || event.Type == watch.Modified {
+				follower.followContainers(ctx, pod)
+			}
+		}
+		watcher.Stop()
+	}
+}
+
+// followContainers starts following the logs of the containers of the given pod that have started and that are not
+// followed yet.
+func (follower *PodLogFollower) followContainers(ctx context.Context, pod *corev1.Pod) {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		// The container ID changes when the container restarts, so that the new instance is followed as well
+		if status.ContainerID == "" || (status.State.Running == nil && status.State.Terminated == nil) {
+			continue
+		}
+
+		follower.mutex.Lock()
+		isFollowed := follower.followed[status.ContainerID]
+		follower.followed[status.ContainerID] = true
+		follower.mutex.Unlock()
+		if isFollowed {
+			continue
+		}
+
+		follower.streams.Add(1)
+		go follower.followContainer(ctx, pod.Name, status.Name)
+	}
+}
+
+// followContainer reads the logs of the given container until the container exits or the given context is done.
+func (follower *PodLogFollower) followContainer(ctx context.Context, podName string, containerName string) {
+	defer follower.streams.Done()
+
+	stream, err := follower.streamLogs(ctx, podName, containerName)
+	if err != nil {
+		if ctx.Err() == nil {
+			follower.options.Logger.Logf(follower.t, "Error following logs of container %s of pod %s: %s", containerName, podName, err)
+		}
+		return
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+	for scanner.Scan() {
+		line := PodLogLine{Pod: podName, Container: containerName, Text: scanner.Text()}
+		if ctx.Err() != nil {
+			return
+		}
+		follower.options.Logger.Logf(follower.t, "[%s/%s] %s", line.Pod, line.Container, line.Text)
+
+		follower.mutex.Lock()
+		follower.lines = append(follower.lines, line)
+		follower.newLine.Broadcast()
+		follower.mutex.Unlock()
+	}
+}
+
+func (follower *PodLogFollower) streamLogs(ctx context.Context, podName string, containerName string) (io.ReadCloser, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(follower.t, follower.options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.CoreV1().Pods(follower.options.Namespace).GetLogs(podName, &corev1.PodLogOptions{Container: containerName, Follow: true}).Stream(ctx)
+}
+
+// selectorDescription describes the pods the given filters select, for log and error messages.
+func selectorDescription(filters metav1.ListOptions) string {
+	description := filters.LabelSelector
+	if filters.FieldSelector != "" {
+		if description != "" {
+			description += ","
+		}
+		description += filters.FieldSelector
+	}
+	if description == "" {
+		return "<all pods>"
+	}
+	return description
+}
+
+// sleepContext sleeps for the given duration or until the given context is done, whichever comes first.
+func sleepContext(ctx context.Context, duration time.Duration) {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
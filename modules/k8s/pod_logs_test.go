@@ -0,0 +1,107 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestWaitUntilLogLineMatches(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExamplePodLogsYAMLTemplate, uniqueID, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	line := WaitUntilLogLineMatches(t, options, metav1.ListOptions{LabelSelector: "app=logger"}, `tick [0-9]+`, 2*time.Minute)
+	assert.True(t, strings.HasPrefix(line.Pod, "logger-"))
+	assert.Equal(t, "logger", line.Container)
+}
+
+func TestFollowPodLogsPicksUpNewPods(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	CreateNamespace(t, options, uniqueID)
+	defer DeleteNamespace(t, options, uniqueID)
+
+	// Follow before the pods exist, so that only pods created later can match
+	follower := FollowPodLogs(t, options, metav1.ListOptions{LabelSelector: "app=logger"})
+	defer follower.Stop()
+	require.Empty(t, follower.Lines())
+
+	configData := fmt.Sprintf(ExamplePodLogsYAMLTemplate, uniqueID, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+
+	line := follower.WaitUntilLogLineMatches(t, fmt.Sprintf("started %s", uniqueID), 2*time.Minute)
+	assert.Equal(t, "logger", line.Container)
+	assert.NotEmpty(t, follower.Lines())
+}
+
+func TestWaitUntilLogLineMatchesReturnsLogLineNotFound(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExamplePodLogsYAMLTemplate, uniqueID, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	_, err := WaitUntilLogLineMatchesE(t, options, metav1.ListOptions{LabelSelector: "app=logger"}, "never logged", 10*time.Second)
+	var notFound LogLineNotFound
+	require.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "app=logger", notFound.Selector)
+}
+
+func TestSelectorDescription(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "<all pods>", selectorDescription(metav1.ListOptions{}))
+	assert.Equal(t, "app=logger", selectorDescription(metav1.ListOptions{LabelSelector: "app=logger"}))
+	assert.Equal(t, "app=logger,status.phase=Running", selectorDescription(metav1.ListOptions{LabelSelector: "app=logger", FieldSelector: "status.phase=Running"}))
+}
+
+const ExamplePodLogsYAMLTemplate = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: logger
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: logger
+  template:
+    metadata:
+      labels:
+        app: logger
+    spec:
+      containers:
+      - name: logger
+        image: busybox:1.36
+        command: ["sh", "-c", "echo started %s; i=0; while true; do i=$((i+1)); echo tick $i; sleep 1; done"]
+`
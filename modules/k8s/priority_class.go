@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/require"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ListPriorityClasses will look for priority classes that match the given filters and return them. This will fail the
+// test if there is an error.
+func ListPriorityClasses(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) []schedulingv1.PriorityClass {
+	priorityClasses, err := ListPriorityClassesE(t, options, filters)
+	require.NoError(t, err)
+	return priorityClasses
+}
+
+// ListPriorityClassesE will look for priority classes that match the given filters and return them.
+func ListPriorityClassesE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) ([]schedulingv1.PriorityClass, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := clientset.SchedulingV1().PriorityClasses().List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetPriorityClass returns a Kubernetes priority class resource with the given name. This will fail the test if there
+// is an error.
+func GetPriorityClass(t testing.TestingT, options *KubectlOptions, priorityClassName string) *schedulingv1.PriorityClass {
+	priorityClass, err := GetPriorityClassE(t, options, priorityClassName)
+	require.NoError(t, err)
+	return priorityClass
+}
+
+// GetPriorityClassE returns a Kubernetes priority class resource with the given name.
+func GetPriorityClassE(t testing.TestingT, options *KubectlOptions, priorityClassName string) (*schedulingv1.PriorityClass, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.SchedulingV1().PriorityClasses().Get(context.Background(), priorityClassName, metav1.GetOptions{})
+}
@@ -0,0 +1,543 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ReadinessStatus summarizes whether a Kubernetes object has reached the state its spec asks for, following the rules
+// of kstatus (https://github.com/kubernetes-sigs/cli-utils/tree/master/pkg/kstatus).
+type ReadinessStatus string
+
+const (
+	// ReadinessCurrent means the object has reached the state its spec asks for, e.g. a Deployment whose replicas are
+	// all updated and available.
+	ReadinessCurrent ReadinessStatus = "Current"
+	// ReadinessInProgress means the object is still on its way to the state its spec asks for.
+	ReadinessInProgress ReadinessStatus = "InProgress"
+	// ReadinessFailed means the object will not reach the state its spec asks for without intervention, e.g. a
+	// Deployment that exceeded its progress deadline or a failed Job.
+	ReadinessFailed ReadinessStatus = "Failed"
+	// ReadinessTerminating means the object is being deleted.
+	ReadinessTerminating ReadinessStatus = "Terminating"
+	// ReadinessNotFound means the object does not exist (yet).
+	ReadinessNotFound ReadinessStatus = "NotFound"
+)
+
+// ObjectReadiness is the readiness of a Kubernetes object, along with a message that explains it.
+type ObjectReadiness struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Status    ReadinessStatus
+	Message   string
+}
+
+func (readiness ObjectReadiness) String() string {
+	name := readiness.Name
+	if readiness.Namespace != "" {
+		name = readiness.Namespace + "/" + name
+	}
+	return fmt.Sprintf("%s %s: %s: %s", readiness.Kind, name, readiness.Status, readiness.Message)
+}
+
+// WaitUntilReady waits until all the given objects are ready, as computed by GetObjectReadiness, retrying the check
+// for the specified amount of times, sleeping for the provided duration between each try. Only the kind, namespace and
+// name of the given objects are used, so they can be parsed from the applied manifests with ParseManifest, e.g.:
+//
+//	KubectlApply(t, options, manifestPath)
+//	WaitUntilReady(t, options, 60, 5*time.Second, ParseManifestFile(t, manifestPath)...)
+//
+// Objects without a namespace are looked up in the namespace of the options, unless their kind is cluster scoped. All
+// objects are checked every time, so they must all be ready at once: an object that was ready, but fails before the
+// others are, e.g. a pod that starts crash looping, fails the wait. The readiness of every object is logged whenever it
+// changes. This will fail the test if there is an error, if an object failed, or if the check times out, with an error
+// that lists the objects that are not ready and why.
+func WaitUntilReady(t testing.TestingT, options *KubectlOptions, retries int, sleepBetweenRetries time.Duration, objects ...*unstructured.Unstructured) {
+	require.NoError(t, WaitUntilReadyE(t, options, retries, sleepBetweenRetries, objects...))
+}
+
+// WaitUntilReadyE waits until all the given objects are ready, as computed by GetObjectReadiness, retrying the check
+// for the specified amount of times, sleeping for the provided duration between each try. Returns an ObjectsNotReady
+// error that lists the objects that are not ready and why if an object failed or the check times out.
+func WaitUntilReadyE(t testing.TestingT, options *KubectlOptions, retries int, sleepBetweenRetries time.Duration, objects ...*unstructured.Unstructured) error {
+	return WaitUntilReadyContextE(t, context.Background(), options, retries, sleepBetweenRetries, objects...)
+}
+
+// WaitUntilReadyContext is like WaitUntilReady, but stops waiting as soon as the given context is done. This will fail
+// the test if there is an error, if an object failed, or if the check times out.
+func WaitUntilReadyContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, retries int, sleepBetweenRetries time.Duration, objects ...*unstructured.Unstructured) {
+	require.NoError(t, WaitUntilReadyContextE(t, ctx, options, retries, sleepBetweenRetries, objects...))
+}
+
+// WaitUntilReadyContextE is like WaitUntilReadyE, but stops waiting as soon as the given context is done.
+func WaitUntilReadyContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, retries int, sleepBetweenRetries time.Duration, objects ...*unstructured.Unstructured) error {
+	client, err := GetDynamicClientFromOptionsE(t, options)
+	if err != nil {
+		return err
+	}
+
+	var mapper meta.RESTMapper
+	notReady := []ObjectReadiness{}
+	lastReadiness := map[string]ObjectReadiness{}
+
+	statusMsg := fmt.Sprintf("Wait for %d objects to be ready.", len(objects))
+	message, err := waitUntilWatchedContextE(
+		t,
+		ctx,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		func(ctx context.Context) (watch.Interface, error) {
+			if mapper == nil {
+				var err error
+				if mapper, err = newRESTMapperE(t, options); err != nil {
+					return nil, err
+				}
+			}
+			return watchObjects(ctx, client, mapper, options, objects)
+		},
+		func() (string, error) {
+			// The mapper is discovered again after a kind is not found, which happens until the CRD of a custom resource
+			// is established
+			if mapper == nil {
+				var err error
+				if mapper, err = newRESTMapperE(t, options); err != nil {
+					return "", err
+				}
+			}
+
+			// All objects are checked every time, as an object that was ready may fail before the others are, e.g. a pod
+			// that starts crash looping. lastReadiness only serves to log the changes.
+			notReady = []ObjectReadiness{}
+			failed := false
+			for _, object := range objects {
+				readiness, err := getObjectReadinessFromClusterE(ctx, client, mapper, options, object)
+				if meta.IsNoMatchError(err) {
+					mapper = nil
+					readiness = newObjectReadiness(object, ReadinessNotFound, fmt.Sprintf("The API server does not serve the kind %s yet", object.GroupVersionKind()))
+				} else if err != nil {
+					return "", err
+				}
+
+				key := readiness.Kind + " " + readiness.Namespace + "/" + readiness.Name
+				if last, seen := lastReadiness[key]; !seen || last != readiness {
+					options.Logger.Logf(t, "%s", readiness)
+					lastReadiness[key] = readiness
+				}
+				if readiness.Status != ReadinessCurrent {
+					notReady = append(notReady, readiness)
+					failed = failed || readiness.Status == ReadinessFailed
+				}
+			}
+			if failed {
+				return "", retry.FatalError{Underlying: ObjectsNotReady{Objects: notReady}}
+			}
+			if len(notReady) > 0 {
+				return "", ObjectsNotReady{Objects: notReady}
+			}
+			return fmt.Sprintf("All %d objects are ready", len(objects)), nil
+		},
+	)
+	if err != nil {
+		if _, isFatalErr := err.(retry.FatalError); isFatalErr {
+			options.Logger.Logf(t, "Stopped waiting for objects to be ready, as some failed: %s", err)
+		} else {
+			options.Logger.Logf(t, "Timedout waiting for objects to be ready: %s", err)
+		}
+		if len(notReady) > 0 {
+			return ObjectsNotReady{Objects: notReady}
+		}
+		return err
+	}
+	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// getObjectReadinessFromClusterE fetches the current state of the given object and returns its readiness.
+func getObjectReadinessFromClusterE(
+	ctx context.Context,
+	client dynamic.Interface,
+	mapper meta.RESTMapper,
+	options *KubectlOptions,
+	object *unstructured.Unstructured,
+) (ObjectReadiness, error) {
+	resourceClient, namespace, err := getObjectResourceClient(client, mapper, options, object)
+	if err != nil {
+		return ObjectReadiness{}, err
+	}
+
+	current, err := resourceClient.Get(ctx, object.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		readiness := newObjectReadiness(object, ReadinessNotFound, "Object does not exist")
+		readiness.Namespace = namespace
+		return readiness, nil
+	}
+	if err != nil {
+		return ObjectReadiness{}, err
+	}
+	return GetObjectReadiness(current), nil
+}
+
+// getObjectResourceClient returns the client for the resource of the given object, scoped to its namespace, which is
+// the namespace of the options if the object has none, along with that namespace. The namespace is empty for cluster
+// scoped objects.
+func getObjectResourceClient(
+	client dynamic.Interface,
+	mapper meta.RESTMapper,
+	options *KubectlOptions,
+	object *unstructured.Unstructured,
+) (dynamic.ResourceInterface, string, error) {
+	mapping, err := getRESTMapping(mapper, object.GroupVersionKind())
+	if err != nil {
+		return nil, "", err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return client.Resource(mapping.Resource), "", nil
+	}
+	namespace := object.GetNamespace()
+	if namespace == "" {
+		namespace = options.Namespace
+	}
+	return client.Resource(mapping.Resource).Namespace(namespace), namespace, nil
+}
+
+// watchObjects watches all the given objects, for waits. This fails if the kind of any of them is not served, e.g.
+// because its CRD is not established yet, in which case the wait polls instead.
+func watchObjects(
+	ctx context.Context,
+	client dynamic.Interface,
+	mapper meta.RESTMapper,
+	options *KubectlOptions,
+	objects []*unstructured.Unstructured,
+) (watch.Interface, error) {
+	watchers := []watch.Interface{}
+	for _, object := range objects {
+		resourceClient, _, err := getObjectResourceClient(client, mapper, options, object)
+		if err == nil {
+			var watcher watch.Interface
+			if watcher, err = resourceClient.Watch(ctx, nameSelector(object.GetName())); err == nil {
+				watchers = append(watchers, watcher)
+				continue
+			}
+		}
+		for _, watcher := range watchers {
+			watcher.Stop()
+		}
+		return nil, err
+	}
+	return newMergedWatch(watchers), nil
+}
+
+// GetObjectReadiness computes whether the given object has reached the state its spec asks for, following the rules of
+// kstatus:
+//
+//   - Objects that are being deleted are Terminating.
+//   - Objects whose status.observedGeneration is behind their metadata.generation are InProgress, as their controller
+//     has not acted on the latest spec yet.
+//   - Deployments, StatefulSets, DaemonSets and ReplicaSets are Current once all their replicas are updated, ready and
+//     available. Deployments that exceeded their progress deadline are Failed.
+//   - Pods are Current once they are running and ready, or succeeded, and Failed if they failed.
+//   - Jobs are Current once they started, and Failed if they failed.
+//   - PersistentVolumeClaims are Current once bound, Services of type LoadBalancer once their load balancer has an
+//     ingress, and CustomResourceDefinitions once established.
+//   - All other objects, including custom resources, are Failed if their Stalled condition is True, InProgress if
+//     their Reconciling condition is True or their Ready or Available condition is not True, and Current otherwise.
+func GetObjectReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	if object.GetDeletionTimestamp() != nil {
+		return newObjectReadiness(object, ReadinessTerminating, "Object is being deleted")
+	}
+
+	generation := object.GetGeneration()
+	if observedGeneration, found := nestedInt(object.Object, "status", "observedGeneration"); found && observedGeneration < generation {
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Controller has observed generation %d of %d", observedGeneration, generation))
+	}
+
+	gvk := object.GroupVersionKind()
+	switch gvk.GroupKind().String() {
+	case "Deployment.apps":
+		return deploymentReadiness(object)
+	case "StatefulSet.apps":
+		return statefulSetReadiness(object)
+	case "DaemonSet.apps":
+		return daemonSetReadiness(object)
+	case "ReplicaSet.apps":
+		return replicaSetReadiness(object)
+	case "Pod":
+		return podReadiness(object)
+	case "Job.batch":
+		return jobReadiness(object)
+	case "PersistentVolumeClaim":
+		return persistentVolumeClaimReadiness(object)
+	case "Service":
+		return serviceReadiness(object)
+	case "CustomResourceDefinition.apiextensions.k8s.io":
+		return customResourceDefinitionReadiness(object)
+	default:
+		return genericReadiness(object)
+	}
+}
+
+func deploymentReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	if progressing := GetResourceCondition(object, "Progressing"); progressing != nil && progressing.Reason == "ProgressDeadlineExceeded" {
+		return newObjectReadiness(object, ReadinessFailed, fmt.Sprintf("Progress deadline exceeded: %s", progressing.Message))
+	}
+
+	replicas := nestedIntOr(object.Object, 1, "spec", "replicas")
+	statusReplicas := nestedIntOr(object.Object, 0, "status", "replicas")
+	updated := nestedIntOr(object.Object, 0, "status", "updatedReplicas")
+	ready := nestedIntOr(object.Object, 0, "status", "readyReplicas")
+	available := nestedIntOr(object.Object, 0, "status", "availableReplicas")
+
+	switch {
+	case updated < replicas:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Updated replicas: %d/%d", updated, replicas))
+	case statusReplicas > updated:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Old replicas pending termination: %d", statusReplicas-updated))
+	case available < updated:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Available replicas: %d/%d", available, updated))
+	case ready < updated:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Ready replicas: %d/%d", ready, updated))
+	}
+	if availableCondition := GetResourceCondition(object, "Available"); availableCondition != nil && availableCondition.Status != metav1.ConditionTrue {
+		return newObjectReadiness(object, ReadinessInProgress, conditionMessage(availableCondition))
+	}
+	return newObjectReadiness(object, ReadinessCurrent, fmt.Sprintf("Deployment is available. Replicas: %d", replicas))
+}
+
+func statefulSetReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	replicas := nestedIntOr(object.Object, 1, "spec", "replicas")
+	statusReplicas := nestedIntOr(object.Object, 0, "status", "replicas")
+	ready := nestedIntOr(object.Object, 0, "status", "readyReplicas")
+	updated := nestedIntOr(object.Object, 0, "status", "updatedReplicas")
+
+	switch {
+	case statusReplicas < replicas:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Replicas: %d/%d", statusReplicas, replicas))
+	case statusReplicas > replicas:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Pending termination. Replicas: %d/%d", statusReplicas, replicas))
+	case ready < replicas:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Ready replicas: %d/%d", ready, replicas))
+	}
+
+	strategy, _, _ := unstructured.NestedString(object.Object, "spec", "updateStrategy", "type")
+	if strategy == "OnDelete" {
+		return newObjectReadiness(object, ReadinessCurrent, fmt.Sprintf("StatefulSet is ready. Replicas: %d", replicas))
+	}
+
+	// With a partition, only the ordinals at or above the partition are updated
+	partition := nestedIntOr(object.Object, 0, "spec", "updateStrategy", "rollingUpdate", "partition")
+	if partition > 0 {
+		if updated < replicas-partition {
+			return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Partitioned rollout in progress. Updated replicas: %d/%d", updated, replicas-partition))
+		}
+		return newObjectReadiness(object, ReadinessCurrent, fmt.Sprintf("Partitioned rollout complete. Updated replicas: %d", updated))
+	}
+
+	currentRevision, _, _ := unstructured.NestedString(object.Object, "status", "currentRevision")
+	updateRevision, _, _ := unstructured.NestedString(object.Object, "status", "updateRevision")
+	if currentRevision != updateRevision {
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Rolling update in progress. Updated replicas: %d/%d", updated, replicas))
+	}
+	return newObjectReadiness(object, ReadinessCurrent, fmt.Sprintf("StatefulSet is ready. Replicas: %d", replicas))
+}
+
+func daemonSetReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	if _, hasStatus := object.Object["status"]; !hasStatus {
+		return newObjectReadiness(object, ReadinessInProgress, "DaemonSet has no status yet")
+	}
+
+	desired := nestedIntOr(object.Object, 0, "status", "desiredNumberScheduled")
+	scheduled := nestedIntOr(object.Object, 0, "status", "currentNumberScheduled")
+	updated := nestedIntOr(object.Object, 0, "status", "updatedNumberScheduled")
+	available := nestedIntOr(object.Object, 0, "status", "numberAvailable")
+	ready := nestedIntOr(object.Object, 0, "status", "numberReady")
+
+	switch {
+	case scheduled < desired:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Scheduled pods: %d/%d", scheduled, desired))
+	case updated < desired:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Updated pods: %d/%d", updated, desired))
+	case available < desired:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Available pods: %d/%d", available, desired))
+	case ready < desired:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Ready pods: %d/%d", ready, desired))
+	}
+	return newObjectReadiness(object, ReadinessCurrent, fmt.Sprintf("All pods are scheduled and ready. Pods: %d", desired))
+}
+
+func replicaSetReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	replicas := nestedIntOr(object.Object, 1, "spec", "replicas")
+	ready := nestedIntOr(object.Object, 0, "status", "readyReplicas")
+	available := nestedIntOr(object.Object, 0, "status", "availableReplicas")
+
+	switch {
+	case ready < replicas:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Ready replicas: %d/%d", ready, replicas))
+	case available < replicas:
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Available replicas: %d/%d", available, replicas))
+	}
+	return newObjectReadiness(object, ReadinessCurrent, fmt.Sprintf("ReplicaSet is available. Replicas: %d", replicas))
+}
+
+func podReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	phase, _, _ := unstructured.NestedString(object.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return newObjectReadiness(object, ReadinessCurrent, "Pod has completed successfully")
+	case "Failed":
+		return newObjectReadiness(object, ReadinessFailed, "Pod has completed, but not successfully")
+	}
+
+	if ready := GetResourceCondition(object, "Ready"); phase == "Running" && ready != nil && ready.Status == metav1.ConditionTrue {
+		return newObjectReadiness(object, ReadinessCurrent, "Pod is running and ready")
+	}
+	if scheduled := GetResourceCondition(object, "PodScheduled"); scheduled != nil && scheduled.Status == metav1.ConditionFalse {
+		return newObjectReadiness(object, ReadinessInProgress, conditionMessage(scheduled))
+	}
+
+	// Explain why the pod is not ready, e.g. an image that can not be pulled or a container that keeps crashing
+	reasons := []string{}
+	containerStatuses, _, _ := unstructured.NestedSlice(object.Object, "status", "containerStatuses")
+	for _, item := range containerStatuses {
+		containerStatus, isMap := item.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(containerStatus, "name")
+		if reason, found, _ := unstructured.NestedString(containerStatus, "state", "waiting", "reason"); found {
+			reasons = append(reasons, fmt.Sprintf("container %s is waiting: %s", name, reason))
+		}
+	}
+	if len(reasons) > 0 {
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Pod is %s, %s", strings.ToLower(phase), strings.Join(reasons, ", ")))
+	}
+	return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("Pod is %s, but not ready", strings.ToLower(phase)))
+}
+
+func jobReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	if failed := GetResourceCondition(object, "Failed"); failed != nil && failed.Status == metav1.ConditionTrue {
+		return newObjectReadiness(object, ReadinessFailed, conditionMessage(failed))
+	}
+	if complete := GetResourceCondition(object, "Complete"); complete != nil && complete.Status == metav1.ConditionTrue {
+		return newObjectReadiness(object, ReadinessCurrent, "Job completed")
+	}
+
+	// Jobs may run for as long as they like, so they are current once they started
+	if _, started, _ := unstructured.NestedString(object.Object, "status", "startTime"); !started {
+		return newObjectReadiness(object, ReadinessInProgress, "Job has not started yet")
+	}
+	return newObjectReadiness(object, ReadinessCurrent, fmt.Sprintf(
+		"Job in progress. succeeded: %d, active: %d, failed: %d",
+		nestedIntOr(object.Object, 0, "status", "succeeded"),
+		nestedIntOr(object.Object, 0, "status", "active"),
+		nestedIntOr(object.Object, 0, "status", "failed"),
+	))
+}
+
+func persistentVolumeClaimReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	phase, _, _ := unstructured.NestedString(object.Object, "status", "phase")
+	if phase != "Bound" {
+		return newObjectReadiness(object, ReadinessInProgress, fmt.Sprintf("PersistentVolumeClaim is not bound, phase: %s", phase))
+	}
+	return newObjectReadiness(object, ReadinessCurrent, "PersistentVolumeClaim is bound")
+}
+
+func serviceReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	serviceType, _, _ := unstructured.NestedString(object.Object, "spec", "type")
+	if serviceType == "LoadBalancer" {
+		ingress, _, _ := unstructured.NestedSlice(object.Object, "status", "loadBalancer", "ingress")
+		if len(ingress) == 0 {
+			return newObjectReadiness(object, ReadinessInProgress, "Waiting for the load balancer of the Service")
+		}
+	}
+	return newObjectReadiness(object, ReadinessCurrent, "Service is ready")
+}
+
+func customResourceDefinitionReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	if namesAccepted := GetResourceCondition(object, "NamesAccepted"); namesAccepted != nil && namesAccepted.Status == metav1.ConditionFalse {
+		return newObjectReadiness(object, ReadinessFailed, conditionMessage(namesAccepted))
+	}
+	if established := GetResourceCondition(object, "Established"); established == nil || established.Status != metav1.ConditionTrue {
+		return newObjectReadiness(object, ReadinessInProgress, "CustomResourceDefinition is not established yet")
+	}
+	return newObjectReadiness(object, ReadinessCurrent, "CustomResourceDefinition is established")
+}
+
+func genericReadiness(object *unstructured.Unstructured) ObjectReadiness {
+	if stalled := GetResourceCondition(object, "Stalled"); stalled != nil && stalled.Status == metav1.ConditionTrue {
+		return newObjectReadiness(object, ReadinessFailed, conditionMessage(stalled))
+	}
+	if reconciling := GetResourceCondition(object, "Reconciling"); reconciling != nil && reconciling.Status == metav1.ConditionTrue {
+		return newObjectReadiness(object, ReadinessInProgress, conditionMessage(reconciling))
+	}
+	for _, conditionType := range []string{"Ready", "Available"} {
+		if condition := GetResourceCondition(object, conditionType); condition != nil && condition.Status != metav1.ConditionTrue {
+			return newObjectReadiness(object, ReadinessInProgress, conditionMessage(condition))
+		}
+	}
+	return newObjectReadiness(object, ReadinessCurrent, "Object is current")
+}
+
+func newObjectReadiness(object *unstructured.Unstructured, status ReadinessStatus, message string) ObjectReadiness {
+	return ObjectReadiness{
+		Kind:      object.GetKind(),
+		Namespace: object.GetNamespace(),
+		Name:      object.GetName(),
+		Status:    status,
+		Message:   message,
+	}
+}
+
+// conditionMessage returns a single line description of the given condition, e.g. `Ready: False, reason: Pending,
+// message: Issuing certificate`.
+func conditionMessage(condition *metav1.Condition) string {
+	message := fmt.Sprintf("%s: %s", condition.Type, condition.Status)
+	if condition.Reason != "" {
+		message += ", reason: " + condition.Reason
+	}
+	if condition.Message != "" {
+		message += ", message: " + condition.Message
+	}
+	return message
+}
+
+// nestedInt returns the integer at the given path of the given object. Integers are int64 in objects returned by the
+// API server, but may be float64 in objects decoded from manifests.
+func nestedInt(object map[string]interface{}, fields ...string) (int64, bool) {
+	value, found, err := unstructured.NestedFieldNoCopy(object, fields...)
+	if !found || err != nil {
+		return 0, false
+	}
+	switch number := value.(type) {
+	case int64:
+		return number, true
+	case int:
+		return int64(number), true
+	case float64:
+		return int64(number), true
+	default:
+		return 0, false
+	}
+}
+
+// nestedIntOr returns the integer at the given path of the given object, or the given default if there is none.
+func nestedIntOr(object map[string]interface{}, defaultValue int64, fields ...string) int64 {
+	if value, found := nestedInt(object, fields...); found {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,263 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: See the notes in the other Kubernetes testing files for information on why this build tag is here.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitUntilReady(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleDeploymentYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	WaitUntilReady(t, options, 60, 1*time.Second, ParseManifest(t, configData)...)
+}
+
+func TestWaitUntilReadyReportsObjectsNotReady(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleDeploymentYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	objects := ParseManifest(t, configData+`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: never-created
+`)
+	err := WaitUntilReadyE(t, options, 5, 1*time.Second, objects...)
+	require.Error(t, err)
+	require.IsType(t, ObjectsNotReady{}, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("ConfigMap %s/never-created: NotFound", uniqueID))
+}
+
+func TestGetObjectReadiness(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		title           string
+		manifest        string
+		expectedStatus  ReadinessStatus
+		expectedMessage string
+	}{
+		{
+			title: "ConfigMap",
+			manifest: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: example
+`,
+			expectedStatus:  ReadinessCurrent,
+			expectedMessage: "Object is current",
+		},
+		{
+			title: "ObservedGenerationBehind",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+  generation: 3
+spec:
+  replicas: 1
+status:
+  observedGeneration: 2
+  replicas: 1
+  updatedReplicas: 1
+  readyReplicas: 1
+  availableReplicas: 1
+`,
+			expectedStatus:  ReadinessInProgress,
+			expectedMessage: "Controller has observed generation 2 of 3",
+		},
+		{
+			title: "DeploymentRollingOut",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+  generation: 2
+spec:
+  replicas: 3
+status:
+  observedGeneration: 2
+  replicas: 4
+  updatedReplicas: 3
+  readyReplicas: 3
+  availableReplicas: 3
+`,
+			expectedStatus:  ReadinessInProgress,
+			expectedMessage: "Old replicas pending termination: 1",
+		},
+		{
+			title: "DeploymentProgressDeadlineExceeded",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  replicas: 1
+status:
+  conditions:
+  - type: Progressing
+    status: "False"
+    reason: ProgressDeadlineExceeded
+    message: ReplicaSet "example-5d4f" has timed out progressing.
+`,
+			expectedStatus:  ReadinessFailed,
+			expectedMessage: `Progress deadline exceeded: ReplicaSet "example-5d4f" has timed out progressing.`,
+		},
+		{
+			title: "DeploymentAvailable",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  replicas: 2
+status:
+  replicas: 2
+  updatedReplicas: 2
+  readyReplicas: 2
+  availableReplicas: 2
+`,
+			expectedStatus:  ReadinessCurrent,
+			expectedMessage: "Deployment is available. Replicas: 2",
+		},
+		{
+			title: "StatefulSetRollingUpdate",
+			manifest: `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: example
+spec:
+  replicas: 3
+status:
+  replicas: 3
+  readyReplicas: 3
+  updatedReplicas: 1
+  currentRevision: example-1
+  updateRevision: example-2
+`,
+			expectedStatus:  ReadinessInProgress,
+			expectedMessage: "Rolling update in progress. Updated replicas: 1/3",
+		},
+		{
+			title: "PodCrashLooping",
+			manifest: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: example
+status:
+  phase: Running
+  conditions:
+  - type: Ready
+    status: "False"
+  containerStatuses:
+  - name: app
+    state:
+      waiting:
+        reason: CrashLoopBackOff
+`,
+			expectedStatus:  ReadinessInProgress,
+			expectedMessage: "Pod is running, container app is waiting: CrashLoopBackOff",
+		},
+		{
+			title: "JobFailed",
+			manifest: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: example
+status:
+  conditions:
+  - type: Failed
+    status: "True"
+    reason: BackoffLimitExceeded
+    message: Job has reached the specified backoff limit
+`,
+			expectedStatus:  ReadinessFailed,
+			expectedMessage: "Failed: True, reason: BackoffLimitExceeded, message: Job has reached the specified backoff limit",
+		},
+		{
+			title: "LoadBalancerPending",
+			manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: example
+spec:
+  type: LoadBalancer
+`,
+			expectedStatus:  ReadinessInProgress,
+			expectedMessage: "Waiting for the load balancer of the Service",
+		},
+		{
+			title: "CustomResourceNotReady",
+			manifest: `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: example
+status:
+  conditions:
+  - type: Ready
+    status: "False"
+    reason: Pending
+`,
+			expectedStatus:  ReadinessInProgress,
+			expectedMessage: "Ready: False, reason: Pending",
+		},
+		{
+			title: "Terminating",
+			manifest: `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: example
+  deletionTimestamp: "2024-01-01T00:00:00Z"
+`,
+			expectedStatus:  ReadinessTerminating,
+			expectedMessage: "Object is being deleted",
+		},
+	}
+
+	for _, testCase := range testCases {
+		// capture range variable so that it doesn't update when the subtest goroutine swaps.
+		testCase := testCase
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+
+			objects := ParseManifest(t, testCase.manifest)
+			require.Len(t, objects, 1)
+
+			readiness := GetObjectReadiness(objects[0])
+			assert.Equal(t, testCase.expectedStatus, readiness.Status)
+			assert.Equal(t, testCase.expectedMessage, readiness.Message)
+		})
+	}
+}
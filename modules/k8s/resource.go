@@ -0,0 +1,294 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// GetResource returns the Kubernetes resource of any kind, including custom resources, with the given name. The
+// resource is looked up in the namespace of the options, unless its kind is cluster scoped. This will fail the test if
+// there is an error.
+//
+// Example, for a cert-manager Certificate:
+//
+//	gvr := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+//	certificate := GetResource(t, options, gvr, "example-tls")
+func GetResource(t testing.TestingT, options *KubectlOptions, gvr schema.GroupVersionResource, name string) *unstructured.Unstructured {
+	resource, err := GetResourceE(t, options, gvr, name)
+	require.NoError(t, err)
+	return resource
+}
+
+// GetResourceE returns the Kubernetes resource of any kind, including custom resources, with the given name. The
+// resource is looked up in the namespace of the options, unless its kind is cluster scoped.
+func GetResourceE(t testing.TestingT, options *KubectlOptions, gvr schema.GroupVersionResource, name string) (*unstructured.Unstructured, error) {
+	client, err := getResourceClientE(t, options, gvr)
+	if err != nil {
+		return nil, err
+	}
+	return client.Get(context.Background(), name, metav1.GetOptions{})
+}
+
+// ListResources will look for Kubernetes resources of any kind, including custom resources, that match the given
+// filters and return them. The resources are looked up in the namespace of the options, unless their kind is cluster
+// scoped. This will fail the test if there is an error.
+func ListResources(t testing.TestingT, options *KubectlOptions, gvr schema.GroupVersionResource, filters metav1.ListOptions) []unstructured.Unstructured {
+	resources, err := ListResourcesE(t, options, gvr, filters)
+	require.NoError(t, err)
+	return resources
+}
+
+// ListResourcesE will look for Kubernetes resources of any kind, including custom resources, that match the given
+// filters and return them. The resources are looked up in the namespace of the options, unless their kind is cluster
+// scoped.
+func ListResourcesE(t testing.TestingT, options *KubectlOptions, gvr schema.GroupVersionResource, filters metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	client, err := getResourceClientE(t, options, gvr)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := client.List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+	return resources.Items, nil
+}
+
+// WaitUntilResourceCondition waits until the resource with the given name has a condition of the given type with the
+// given status in its status.conditions, e.g. a Certificate with the condition Ready set to True, retrying the check
+// for the specified amount of times, sleeping for the provided duration between each try. This will fail the test if
+// there is an error or if the check times out.
+func WaitUntilResourceCondition(
+	t testing.TestingT,
+	options *KubectlOptions,
+	gvr schema.GroupVersionResource,
+	name string,
+	conditionType string,
+	status metav1.ConditionStatus,
+	retries int,
+	sleepBetweenRetries time.Duration,
+) {
+	require.NoError(t, WaitUntilResourceConditionE(t, options, gvr, name, conditionType, status, retries, sleepBetweenRetries))
+}
+
+// WaitUntilResourceConditionE waits until the resource with the given name has a condition of the given type with the
+// given status in its status.conditions, retrying the check for the specified amount of times, sleeping for the
+// provided duration between each try.
+func WaitUntilResourceConditionE(
+	t testing.TestingT,
+	options *KubectlOptions,
+	gvr schema.GroupVersionResource,
+	name string,
+	conditionType string,
+	status metav1.ConditionStatus,
+	retries int,
+	sleepBetweenRetries time.Duration,
+) error {
+	return WaitUntilResourceConditionContextE(t, context.Background(), options, gvr, name, conditionType, status, retries, sleepBetweenRetries)
+}
+
+// WaitUntilResourceConditionContext is like WaitUntilResourceCondition, but stops waiting as soon as the given context
+// is done. This will fail the test if there is an error or if the check times out.
+func WaitUntilResourceConditionContext(
+	t testing.TestingT,
+	ctx context.Context,
+	options *KubectlOptions,
+	gvr schema.GroupVersionResource,
+	name string,
+	conditionType string,
+	status metav1.ConditionStatus,
+	retries int,
+	sleepBetweenRetries time.Duration,
+) {
+	require.NoError(t, WaitUntilResourceConditionContextE(t, ctx, options, gvr, name, conditionType, status, retries, sleepBetweenRetries))
+}
+
+// WaitUntilResourceConditionContextE is like WaitUntilResourceConditionE, but stops waiting as soon as the given
+// context is done.
+func WaitUntilResourceConditionContextE(
+	t testing.TestingT,
+	ctx context.Context,
+	options *KubectlOptions,
+	gvr schema.GroupVersionResource,
+	name string,
+	conditionType string,
+	status metav1.ConditionStatus,
+	retries int,
+	sleepBetweenRetries time.Duration,
+) error {
+	statusMsg := fmt.Sprintf("Wait for %s %s to have condition %s=%s.", gvr.Resource, name, conditionType, status)
+	message, err := waitUntilWatchedContextE(
+		t,
+		ctx,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		watchResources(t, options, gvr, nameSelector(name)),
+		func() (string, error) {
+			resource, err := GetResourceE(t, options, gvr, name)
+			if err != nil {
+				return "", err
+			}
+			if !IsResourceConditionStatus(resource, conditionType, status) {
+				return "", NewResourceConditionNotMetError(resource, conditionType, status)
+			}
+			return fmt.Sprintf("%s %s now has condition %s=%s", gvr.Resource, name, conditionType, status), nil
+		},
+	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for %s %s to have condition %s=%s: %s", gvr.Resource, name, conditionType, status, err)
+		return err
+	}
+	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// IsResourceConditionStatus returns true if the given resource has a condition of the given type with the given
+// status in its status.conditions.
+func IsResourceConditionStatus(resource *unstructured.Unstructured, conditionType string, status metav1.ConditionStatus) bool {
+	condition := GetResourceCondition(resource, conditionType)
+	return condition != nil && condition.Status == status
+}
+
+// GetResourceCondition returns the condition of the given type in the status.conditions of the given resource, or nil
+// if the resource has no such condition. Only the type, status, reason and message of the condition are set.
+func GetResourceCondition(resource *unstructured.Unstructured, conditionType string) *metav1.Condition {
+	conditions, _, _ := unstructured.NestedSlice(resource.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, isMap := item.(map[string]interface{})
+		if !isMap || fmt.Sprint(condition["type"]) != conditionType {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(condition, "status")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		return &metav1.Condition{Type: conditionType, Status: metav1.ConditionStatus(status), Reason: reason, Message: message}
+	}
+	return nil
+}
+
+// UnmarshalResourceJSONPath queries the given resource with the given JSONPath and unmarshals the result into the given
+// output, as with UnmarshalJSONPath. This will fail the test if there is an error.
+//
+// Example:
+//
+//	var hosts []string
+//	UnmarshalResourceJSONPath(t, virtualService, "{.spec.hosts[*]}", &hosts)
+func UnmarshalResourceJSONPath(t testing.TestingT, resource *unstructured.Unstructured, jsonpathStr string, output interface{}) {
+	require.NoError(t, UnmarshalResourceJSONPathE(t, resource, jsonpathStr, output))
+}
+
+// UnmarshalResourceJSONPathE queries the given resource with the given JSONPath and unmarshals the result into the
+// given output, as with UnmarshalJSONPathE.
+func UnmarshalResourceJSONPathE(t testing.TestingT, resource *unstructured.Unstructured, jsonpathStr string, output interface{}) error {
+	jsonData, err := resource.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return UnmarshalJSONPathE(t, jsonData, jsonpathStr, output)
+}
+
+// GetGroupVersionResourceForKind returns the resource that the API server serves objects of the given kind as, e.g.
+// the resource deployments in the group apps for the kind Deployment, as discovered from the API server. If the version
+// of the kind is empty, the preferred version of its group is used. This will fail the test if there is an error.
+func GetGroupVersionResourceForKind(t testing.TestingT, options *KubectlOptions, gvk schema.GroupVersionKind) schema.GroupVersionResource {
+	gvr, err := GetGroupVersionResourceForKindE(t, options, gvk)
+	require.NoError(t, err)
+	return gvr
+}
+
+// GetGroupVersionResourceForKindE returns the resource that the API server serves objects of the given kind as, e.g.
+// the resource deployments in the group apps for the kind Deployment, as discovered from the API server. If the version
+// of the kind is empty, the preferred version of its group is used.
+func GetGroupVersionResourceForKindE(t testing.TestingT, options *KubectlOptions, gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	mapper, err := newRESTMapperE(t, options)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	mapping, err := getRESTMapping(mapper, gvk)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapping.Resource, nil
+}
+
+// newRESTMapperE returns a mapper from the kinds to the resources the API server serves, as discovered from the API
+// server.
+func newRESTMapperE(t testing.TestingT, options *KubectlOptions) (meta.RESTMapper, error) {
+	discoveryClient, err := GetDiscoveryClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+	return restmapper.NewDiscoveryRESTMapper(groupResources), nil
+}
+
+// getRESTMapping returns the resource for the given kind, in the preferred version of its group if the version of the
+// kind is empty.
+func getRESTMapping(mapper meta.RESTMapper, gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	versions := []string{}
+	if gvk.Version != "" {
+		versions = append(versions, gvk.Version)
+	}
+	return mapper.RESTMapping(gvk.GroupKind(), versions...)
+}
+
+// getResourceClientE returns a dynamic client for the given resource, scoped to the namespace of the options if the
+// resource is namespaced.
+func getResourceClientE(t testing.TestingT, options *KubectlOptions, gvr schema.GroupVersionResource) (dynamic.ResourceInterface, error) {
+	namespaced, err := isResourceNamespacedE(t, options, gvr)
+	if err != nil {
+		return nil, err
+	}
+	client, err := GetDynamicClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	if namespaced {
+		return client.Resource(gvr).Namespace(options.Namespace), nil
+	}
+	return client.Resource(gvr), nil
+}
+
+// isResourceNamespacedE returns whether the API server serves the given resource per namespace.
+func isResourceNamespacedE(t testing.TestingT, options *KubectlOptions, gvr schema.GroupVersionResource) (bool, error) {
+	discoveryClient, err := GetDiscoveryClientFromOptionsE(t, options)
+	if err != nil {
+		return false, err
+	}
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if err != nil {
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == gvr.Resource {
+			return resource.Namespaced, nil
+		}
+	}
+	return false, UnknownResource{GroupVersionResource: gvr}
+}
+
+// watchResources returns a function that watches the resources that match the given filters, for waits.
+func watchResources(t testing.TestingT, options *KubectlOptions, gvr schema.GroupVersionResource, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		client, err := getResourceClientE(t, options, gvr)
+		if err != nil {
+			return nil, err
+		}
+		return client.Watch(ctx, filters)
+	}
+}
@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ListResourceQuotas will look for resource quotas in the given namespace that match the given filters and return them.
+// This will fail the test if there is an error.
+func ListResourceQuotas(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) []corev1.ResourceQuota {
+	resourceQuotas, err := ListResourceQuotasE(t, options, filters)
+	require.NoError(t, err)
+	return resourceQuotas
+}
+
+// ListResourceQuotasE will look for resource quotas in the given namespace that match the given filters and return
+// them.
+func ListResourceQuotasE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) ([]corev1.ResourceQuota, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := clientset.CoreV1().ResourceQuotas(options.Namespace).List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetResourceQuota returns a Kubernetes resource quota resource in the provided namespace with the given name. This
+// will fail the test if there is an error.
+func GetResourceQuota(t testing.TestingT, options *KubectlOptions, resourceQuotaName string) *corev1.ResourceQuota {
+	resourceQuota, err := GetResourceQuotaE(t, options, resourceQuotaName)
+	require.NoError(t, err)
+	return resourceQuota
+}
+
+// GetResourceQuotaE returns a Kubernetes resource quota resource in the provided namespace with the given name.
+func GetResourceQuotaE(t testing.TestingT, options *KubectlOptions, resourceQuotaName string) (*corev1.ResourceQuota, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.CoreV1().ResourceQuotas(options.Namespace).Get(context.Background(), resourceQuotaName, metav1.GetOptions{})
+}
+
+// WaitUntilResourceQuotaCalculated waits until the resource quota controller has calculated the usage of every resource
+// the resource quota limits, retrying the check for the specified amount of times, sleeping for the provided duration
+// between each try. Until then, the resource quota is not enforced. This will fail the test if there is an error or if
+// the check times out.
+func WaitUntilResourceQuotaCalculated(t testing.TestingT, options *KubectlOptions, resourceQuotaName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilResourceQuotaCalculatedE(t, options, resourceQuotaName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilResourceQuotaCalculatedE waits until the resource quota controller has calculated the usage of every
+// resource the resource quota limits, retrying the check for the specified amount of times, sleeping for the provided
+// duration between each try.
+func WaitUntilResourceQuotaCalculatedE(t testing.TestingT, options *KubectlOptions, resourceQuotaName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilResourceQuotaCalculatedContextE(t, context.Background(), options, resourceQuotaName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilResourceQuotaCalculatedContext is like WaitUntilResourceQuotaCalculated, but stops waiting as soon as the
+// given context is done. This will fail the test if there is an error or if the check times out.
+func WaitUntilResourceQuotaCalculatedContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, resourceQuotaName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilResourceQuotaCalculatedContextE(t, ctx, options, resourceQuotaName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilResourceQuotaCalculatedContextE is like WaitUntilResourceQuotaCalculatedE, but stops waiting as soon as the
+// given context is done.
+func WaitUntilResourceQuotaCalculatedContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, resourceQuotaName string, retries int, sleepBetweenRetries time.Duration) error {
+	statusMsg := fmt.Sprintf("Wait for resource quota %s to be calculated.", resourceQuotaName)
+	message, err := waitUntilWatchedContextE(
+		t,
+		ctx,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		watchResourceQuotas(t, options, nameSelector(resourceQuotaName)),
+		func() (string, error) {
+			resourceQuota, err := GetResourceQuotaE(t, options, resourceQuotaName)
+			if err != nil {
+				return "", err
+			}
+			if !IsResourceQuotaCalculated(resourceQuota) {
+				return "", NewResourceQuotaNotCalculatedError(resourceQuota)
+			}
+			return "Resource quota is now calculated", nil
+		},
+	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for ResourceQuota to be calculated: %s", err)
+		return err
+	}
+	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// IsResourceQuotaCalculated returns true if the status of the resource quota reports the usage of every resource that
+// its spec limits, with the limits of its spec.
+func IsResourceQuotaCalculated(resourceQuota *corev1.ResourceQuota) bool {
+	for resourceName, hard := range resourceQuota.Spec.Hard {
+		statusHard, hasHard := resourceQuota.Status.Hard[resourceName]
+		if !hasHard || statusHard.Cmp(hard) != 0 {
+			return false
+		}
+		if _, hasUsed := resourceQuota.Status.Used[resourceName]; !hasUsed {
+			return false
+		}
+	}
+	return true
+}
+
+// watchResourceQuotas returns a function that watches the resource quotas that match the given filters, for waits.
+func watchResourceQuotas(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.CoreV1().ResourceQuotas(options.Namespace).Watch(ctx, filters)
+	}
+}
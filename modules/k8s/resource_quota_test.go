@@ -0,0 +1,122 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestWaitUntilResourceQuotaCalculated(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleResourceQuotaYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	WaitUntilResourceQuotaCalculated(t, options, "compute-quota", 60, 1*time.Second)
+
+	resourceQuota := GetResourceQuota(t, options, "compute-quota")
+	used := resourceQuota.Status.Used[corev1.ResourcePods]
+	require.Equal(t, int64(0), used.Value())
+
+	limitRanges := ListLimitRanges(t, options, metav1.ListOptions{})
+	require.Equal(t, len(limitRanges), 1)
+	limitRange := GetLimitRange(t, options, "compute-limits")
+	require.Equal(t, corev1.LimitTypeContainer, limitRange.Spec.Limits[0].Type)
+}
+
+func TestIsResourceQuotaCalculated(t *testing.T) {
+	t.Parallel()
+
+	hard := corev1.ResourceList{corev1.ResourcePods: resource.MustParse("2")}
+	testCases := []struct {
+		title          string
+		resourceQuota  *corev1.ResourceQuota
+		expectedResult bool
+	}{
+		{
+			title: "Calculated",
+			resourceQuota: &corev1.ResourceQuota{
+				Spec: corev1.ResourceQuotaSpec{Hard: hard},
+				Status: corev1.ResourceQuotaStatus{
+					Hard: hard,
+					Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("0")},
+				},
+			},
+			expectedResult: true,
+		},
+		{
+			title: "NotCalculated",
+			resourceQuota: &corev1.ResourceQuota{
+				Spec: corev1.ResourceQuotaSpec{Hard: hard},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "OutdatedLimits",
+			resourceQuota: &corev1.ResourceQuota{
+				Spec: corev1.ResourceQuotaSpec{Hard: hard},
+				Status: corev1.ResourceQuotaStatus{
+					Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+					Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("0")},
+				},
+			},
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expectedResult, IsResourceQuotaCalculated(tc.resourceQuota))
+		})
+	}
+}
+
+const ExampleResourceQuotaYAMLTemplate = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: compute-quota
+spec:
+  hard:
+    pods: "2"
+    requests.cpu: "1"
+---
+apiVersion: v1
+kind: LimitRange
+metadata:
+  name: compute-limits
+spec:
+  limits:
+  - type: Container
+    default:
+      cpu: 100m
+    defaultRequest:
+      cpu: 50m
+`
@@ -0,0 +1,110 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: See the notes in the other Kubernetes testing files for information on why this build tag is here.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var deploymentsResource = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+func TestGetResourceEReturnsError(t *testing.T) {
+	t.Parallel()
+
+	options := NewKubectlOptions("", "", "")
+	_, err := GetResourceE(t, options, deploymentsResource, "nginx-deployment")
+	require.Error(t, err)
+}
+
+func TestGetAndListResources(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleDeploymentYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	deployment := GetResource(t, options, deploymentsResource, "nginx-deployment")
+	require.Equal(t, "nginx-deployment", deployment.GetName())
+	require.Equal(t, uniqueID, deployment.GetNamespace())
+
+	var replicas []int
+	UnmarshalResourceJSONPath(t, deployment, "{.spec.replicas}", &replicas)
+	require.Equal(t, []int{2}, replicas)
+
+	deployments := ListResources(t, options, deploymentsResource, metav1.ListOptions{LabelSelector: "app=nginx"})
+	require.Len(t, deployments, 1)
+
+	// Namespaces are cluster scoped, so the namespace of the options must be ignored
+	namespace := GetResource(t, options, schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, uniqueID)
+	require.Equal(t, uniqueID, namespace.GetName())
+}
+
+func TestWaitUntilResourceCondition(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleDeploymentYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	WaitUntilResourceCondition(t, options, deploymentsResource, "nginx-deployment", "Available", metav1.ConditionTrue, 60, 1*time.Second)
+}
+
+func TestGetGroupVersionResourceForKind(t *testing.T) {
+	t.Parallel()
+
+	options := NewKubectlOptions("", "", "default")
+	gvr := GetGroupVersionResourceForKind(t, options, schema.GroupVersionKind{Group: "apps", Kind: "Deployment"})
+	require.Equal(t, deploymentsResource, gvr)
+}
+
+func TestResourceConditions(t *testing.T) {
+	t.Parallel()
+
+	resource := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "Certificate",
+		"metadata": map[string]interface{}{"name": "example-tls"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":    "Ready",
+					"status":  "False",
+					"reason":  "Pending",
+					"message": "Issuing certificate as Secret does not exist",
+				},
+			},
+		},
+	}}
+
+	assert.True(t, IsResourceConditionStatus(resource, "Ready", metav1.ConditionFalse))
+	assert.False(t, IsResourceConditionStatus(resource, "Ready", metav1.ConditionTrue))
+	assert.False(t, IsResourceConditionStatus(resource, "Issuing", metav1.ConditionTrue))
+	assert.Nil(t, GetResourceCondition(resource, "Issuing"))
+
+	assert.EqualError(
+		t,
+		NewResourceConditionNotMetError(resource, "Ready", metav1.ConditionTrue),
+		"Certificate example-tls has condition 'Ready' with status False instead of True, reason: Pending, message: Issuing certificate as Secret does not exist",
+	)
+	assert.EqualError(
+		t,
+		NewResourceConditionNotMetError(resource, "Issuing", metav1.ConditionTrue),
+		"Certificate example-tls does not have the condition 'Issuing' yet",
+	)
+}
@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // GetSecret returns a Kubernetes secret resource in the provided namespace with the given name. The namespace used
@@ -33,12 +33,31 @@ func GetSecretE(t testing.TestingT, options *KubectlOptions, secretName string)
 // WaitUntilSecretAvailable waits until the secret is present on the cluster in cases where it is not immediately
 // available (for example, when using ClusterIssuer to request a certificate).
 func WaitUntilSecretAvailable(t testing.TestingT, options *KubectlOptions, secretName string, retries int, sleepBetweenRetries time.Duration) {
+	WaitUntilSecretAvailableContext(t, context.Background(), options, secretName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilSecretAvailableE waits until the secret is present on the cluster, retrying the check for the specified
+// amount of times, sleeping for the provided duration between each try.
+func WaitUntilSecretAvailableE(t testing.TestingT, options *KubectlOptions, secretName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilSecretAvailableContextE(t, context.Background(), options, secretName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilSecretAvailableContext is like WaitUntilSecretAvailable, but stops waiting as soon as the given context is done.
+// This will fail the test if the context is done or the retries are exhausted.
+func WaitUntilSecretAvailableContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, secretName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilSecretAvailableContextE(t, ctx, options, secretName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilSecretAvailableContextE is like WaitUntilSecretAvailableE, but stops waiting as soon as the given context is done.
+func WaitUntilSecretAvailableContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, secretName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for secret %s to be provisioned.", secretName)
-	message := retry.DoWithRetry(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchSecrets(t, options, nameSelector(secretName)),
 		func() (string, error) {
 			_, err := GetSecretE(t, options, secretName)
 			if err != nil {
@@ -48,5 +67,21 @@ func WaitUntilSecretAvailable(t testing.TestingT, options *KubectlOptions, secre
 			return "Secret is now available", nil
 		},
 	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for Secret to be provisioned: %s", err)
+		return err
+	}
 	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// watchSecrets returns a function that watches the secrets that match the given filters, for waits.
+func watchSecrets(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.CoreV1().Secrets(options.Namespace).Watch(ctx, filters)
+	}
 }
@@ -10,10 +10,10 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/gruntwork-io/terratest/modules/aws"
 	"github.com/gruntwork-io/terratest/modules/random"
-	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
@@ -57,12 +57,31 @@ func GetServiceE(t testing.TestingT, options *KubectlOptions, serviceName string
 
 // WaitUntilServiceAvailable waits until the service endpoint is ready to accept traffic.
 func WaitUntilServiceAvailable(t testing.TestingT, options *KubectlOptions, serviceName string, retries int, sleepBetweenRetries time.Duration) {
+	WaitUntilServiceAvailableContext(t, context.Background(), options, serviceName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilServiceAvailableE waits until the service endpoint is ready to accept traffic, retrying the check for the
+// specified amount of times, sleeping for the provided duration between each try.
+func WaitUntilServiceAvailableE(t testing.TestingT, options *KubectlOptions, serviceName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilServiceAvailableContextE(t, context.Background(), options, serviceName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilServiceAvailableContext is like WaitUntilServiceAvailable, but stops waiting as soon as the given context is done.
+// This will fail the test if the context is done or the retries are exhausted.
+func WaitUntilServiceAvailableContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, serviceName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilServiceAvailableContextE(t, ctx, options, serviceName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilServiceAvailableContextE is like WaitUntilServiceAvailableE, but stops waiting as soon as the given context is done.
+func WaitUntilServiceAvailableContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, serviceName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for service %s to be provisioned.", serviceName)
-	message := retry.DoWithRetry(
+	message, err := waitUntilWatchedContextE(
 		t,
+		ctx,
 		statusMsg,
 		retries,
 		sleepBetweenRetries,
+		watchServices(t, options, nameSelector(serviceName)),
 		func() (string, error) {
 			service, err := GetServiceE(t, options, serviceName)
 			if err != nil {
@@ -82,7 +101,12 @@ func WaitUntilServiceAvailable(t testing.TestingT, options *KubectlOptions, serv
 			return "Service is now available", nil
 		},
 	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for Service to be provisioned: %s", err)
+		return err
+	}
 	options.Logger.Logf(t, "%s", message)
+	return nil
 }
 
 // IsServiceAvailable returns true if the service endpoint is ready to accept traffic. Note that for Minikube, this
@@ -242,3 +266,14 @@ func findDefaultNodeHostnameE(node corev1.Node) (string, error) {
 	}
 	return "", NewNodeHasNoHostnameError(&node)
 }
+
+// watchServices returns a function that watches the services that match the given filters, for waits.
+func watchServices(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.CoreV1().Services(options.Namespace).Watch(ctx, filters)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/clientcmd"
@@ -103,6 +104,37 @@ func GetServiceAccountAuthTokenE(t testing.TestingT, kubectlOptions *KubectlOpti
 	return string(secret.Data["token"]), nil
 }
 
+// CreateServiceAccountToken will request a new token for the ServiceAccount from the TokenRequest API, valid for the
+// given duration, so it can be used to authenticate requests as that ServiceAccount. Unlike GetServiceAccountAuthToken,
+// this does not rely on the token secrets that Kubernetes no longer creates for ServiceAccounts since version 1.24. This
+// will fail the test if there is an error.
+func CreateServiceAccountToken(t testing.TestingT, kubectlOptions *KubectlOptions, serviceAccountName string, expiration time.Duration) string {
+	token, err := CreateServiceAccountTokenE(t, kubectlOptions, serviceAccountName, expiration)
+	require.NoError(t, err)
+	return token
+}
+
+// CreateServiceAccountTokenE will request a new token for the ServiceAccount from the TokenRequest API, valid for the
+// given duration, so it can be used to authenticate requests as that ServiceAccount.
+func CreateServiceAccountTokenE(t testing.TestingT, kubectlOptions *KubectlOptions, serviceAccountName string, expiration time.Duration) (string, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, kubectlOptions)
+	if err != nil {
+		return "", err
+	}
+
+	expirationSeconds := int64(expiration.Seconds())
+	tokenRequest := authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+	response, err := clientset.CoreV1().ServiceAccounts(kubectlOptions.Namespace).CreateToken(context.Background(), serviceAccountName, &tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return response.Status.Token, nil
+}
+
 // AddConfigContextForServiceAccountE will add a new config context that binds the ServiceAccount auth token to the
 // Kubernetes cluster of the current config context.
 func AddConfigContextForServiceAccountE(
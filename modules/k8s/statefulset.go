@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ListStatefulSets will look for statefulsets in the given namespace that match the given filters and return them. This
+// will fail the test if there is an error.
+func ListStatefulSets(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) []appsv1.StatefulSet {
+	statefulSets, err := ListStatefulSetsE(t, options, filters)
+	require.NoError(t, err)
+	return statefulSets
+}
+
+// ListStatefulSetsE will look for statefulsets in the given namespace that match the given filters and return them.
+func ListStatefulSetsE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) ([]appsv1.StatefulSet, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := clientset.AppsV1().StatefulSets(options.Namespace).List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetStatefulSet returns a Kubernetes statefulset resource in the provided namespace with the given name. This will
+// fail the test if there is an error.
+func GetStatefulSet(t testing.TestingT, options *KubectlOptions, statefulSetName string) *appsv1.StatefulSet {
+	statefulSet, err := GetStatefulSetE(t, options, statefulSetName)
+	require.NoError(t, err)
+	return statefulSet
+}
+
+// GetStatefulSetE returns a Kubernetes statefulset resource in the provided namespace with the given name.
+func GetStatefulSetE(t testing.TestingT, options *KubectlOptions, statefulSetName string) (*appsv1.StatefulSet, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.AppsV1().StatefulSets(options.Namespace).Get(context.Background(), statefulSetName, metav1.GetOptions{})
+}
+
+// WaitUntilStatefulSetAvailable waits until the statefulset is rolled out with all of its pods ready, retrying the
+// check for the specified amount of times, sleeping for the provided duration between each try. This will fail the
+// test if there is an error or if the check times out.
+func WaitUntilStatefulSetAvailable(t testing.TestingT, options *KubectlOptions, statefulSetName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilStatefulSetAvailableE(t, options, statefulSetName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilStatefulSetAvailableE waits until the statefulset is rolled out with all of its pods ready, retrying the
+// check for the specified amount of times, sleeping for the provided duration between each try.
+func WaitUntilStatefulSetAvailableE(t testing.TestingT, options *KubectlOptions, statefulSetName string, retries int, sleepBetweenRetries time.Duration) error {
+	return WaitUntilStatefulSetAvailableContextE(t, context.Background(), options, statefulSetName, retries, sleepBetweenRetries)
+}
+
+// WaitUntilStatefulSetAvailableContext is like WaitUntilStatefulSetAvailable, but stops waiting as soon as the given
+// context is done. This will fail the test if there is an error or if the check times out.
+func WaitUntilStatefulSetAvailableContext(t testing.TestingT, ctx context.Context, options *KubectlOptions, statefulSetName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilStatefulSetAvailableContextE(t, ctx, options, statefulSetName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilStatefulSetAvailableContextE is like WaitUntilStatefulSetAvailableE, but stops waiting as soon as the given
+// context is done.
+func WaitUntilStatefulSetAvailableContextE(t testing.TestingT, ctx context.Context, options *KubectlOptions, statefulSetName string, retries int, sleepBetweenRetries time.Duration) error {
+	statusMsg := fmt.Sprintf("Wait for statefulset %s to be provisioned.", statefulSetName)
+	message, err := waitUntilWatchedContextE(
+		t,
+		ctx,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		watchStatefulSets(t, options, nameSelector(statefulSetName)),
+		func() (string, error) {
+			statefulSet, err := GetStatefulSetE(t, options, statefulSetName)
+			if err != nil {
+				return "", err
+			}
+			if !IsStatefulSetAvailable(statefulSet) {
+				return "", NewStatefulSetNotAvailableError(statefulSet)
+			}
+			return "StatefulSet is now available", nil
+		},
+	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for StatefulSet to be provisioned: %s", err)
+		return err
+	}
+	options.Logger.Logf(t, "%s", message)
+	return nil
+}
+
+// IsStatefulSetAvailable returns true if the statefulset controller has observed the latest spec of the statefulset,
+// the pods of all of its ordinals are ready, and its rollout is complete: all pods run the latest revision, or, for a
+// partitioned rolling update, all pods with an ordinal at or above the partition do. This uses the same rules as
+// GetObjectReadiness.
+func IsStatefulSetAvailable(statefulSet *appsv1.StatefulSet) bool {
+	if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+		return false
+	}
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
+	if err != nil {
+		return false
+	}
+	return statefulSetReadiness(&unstructured.Unstructured{Object: object}).Status == ReadinessCurrent
+}
+
+// watchStatefulSets returns a function that watches the statefulsets that match the given filters, for waits.
+func watchStatefulSets(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) watchFunc {
+	return func(ctx context.Context) (watch.Interface, error) {
+		clientset, err := GetKubernetesClientFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		return clientset.AppsV1().StatefulSets(options.Namespace).Watch(ctx, filters)
+	}
+}
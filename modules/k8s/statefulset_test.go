@@ -0,0 +1,233 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestGetStatefulSetEReturnsError(t *testing.T) {
+	t.Parallel()
+
+	options := NewKubectlOptions("", "", "")
+	_, err := GetStatefulSetE(t, options, "nginx-statefulset")
+	require.Error(t, err)
+}
+
+func TestGetStatefulSets(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleStatefulSetYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	statefulSet := GetStatefulSet(t, options, "nginx-statefulset")
+	require.Equal(t, statefulSet.Name, "nginx-statefulset")
+	require.Equal(t, statefulSet.Namespace, uniqueID)
+}
+
+func TestListStatefulSets(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleStatefulSetYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	statefulSets := ListStatefulSets(t, options, metav1.ListOptions{})
+	require.Equal(t, len(statefulSets), 1)
+
+	statefulSet := statefulSets[0]
+	require.Equal(t, statefulSet.Name, "nginx-statefulset")
+	require.Equal(t, statefulSet.Namespace, uniqueID)
+}
+
+func TestWaitUntilStatefulSetAvailable(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleStatefulSetYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	WaitUntilStatefulSetAvailable(t, options, "nginx-statefulset", 60, 1*time.Second)
+
+	statefulSet := GetStatefulSet(t, options, "nginx-statefulset")
+	require.Equal(t, int32(2), statefulSet.Status.ReadyReplicas)
+}
+
+func TestIsStatefulSetAvailable(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(3)
+	partition := int32(2)
+	testCases := []struct {
+		title          string
+		statefulSet    *appsv1.StatefulSet
+		expectedResult bool
+	}{
+		{
+			title: "RolledOut",
+			statefulSet: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{
+					Replicas:        3,
+					ReadyReplicas:   3,
+					UpdatedReplicas: 3,
+					CurrentRevision: "web-1",
+					UpdateRevision:  "web-1",
+				},
+			},
+			expectedResult: true,
+		},
+		{
+			title: "NotAllOrdinalsReady",
+			statefulSet: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{
+					Replicas:        3,
+					ReadyReplicas:   2,
+					UpdatedReplicas: 3,
+					CurrentRevision: "web-1",
+					UpdateRevision:  "web-1",
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "RollingOut",
+			statefulSet: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{
+					Replicas:        3,
+					ReadyReplicas:   3,
+					UpdatedReplicas: 1,
+					CurrentRevision: "web-1",
+					UpdateRevision:  "web-2",
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "PartitionRolledOut",
+			statefulSet: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &replicas,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+					},
+				},
+				Status: appsv1.StatefulSetStatus{
+					Replicas:        3,
+					ReadyReplicas:   3,
+					UpdatedReplicas: 1,
+					CurrentRevision: "web-1",
+					UpdateRevision:  "web-2",
+				},
+			},
+			expectedResult: true,
+		},
+		{
+			title: "ScalingDown",
+			statefulSet: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{
+					Replicas:        4,
+					ReadyReplicas:   4,
+					UpdatedReplicas: 4,
+					CurrentRevision: "web-1",
+					UpdateRevision:  "web-1",
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "GenerationNotObserved",
+			statefulSet: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 1,
+					Replicas:           3,
+					ReadyReplicas:      3,
+					UpdatedReplicas:    3,
+					CurrentRevision:    "web-1",
+					UpdateRevision:     "web-1",
+				},
+			},
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expectedResult, IsStatefulSetAvailable(tc.statefulSet))
+		})
+	}
+}
+
+const ExampleStatefulSetYAMLTemplate = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+spec:
+  clusterIP: None
+  selector:
+    app: nginx
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: nginx-statefulset
+spec:
+  serviceName: nginx
+  replicas: 2
+  selector:
+    matchLabels:
+      app: nginx
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15.7
+        ports:
+        - containerPort: 80
+        readinessProbe:
+          httpGet:
+            path: /
+            port: 80
+`
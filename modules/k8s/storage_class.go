@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/require"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ListStorageClasses will look for storage classes that match the given filters and return them. This will fail the
+// test if there is an error.
+func ListStorageClasses(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) []storagev1.StorageClass {
+	storageClasses, err := ListStorageClassesE(t, options, filters)
+	require.NoError(t, err)
+	return storageClasses
+}
+
+// ListStorageClassesE will look for storage classes that match the given filters and return them.
+func ListStorageClassesE(t testing.TestingT, options *KubectlOptions, filters metav1.ListOptions) ([]storagev1.StorageClass, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := clientset.StorageV1().StorageClasses().List(context.Background(), filters)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetStorageClass returns a Kubernetes storage class resource with the given name. This will fail the test if there is
+// an error.
+func GetStorageClass(t testing.TestingT, options *KubectlOptions, storageClassName string) *storagev1.StorageClass {
+	storageClass, err := GetStorageClassE(t, options, storageClassName)
+	require.NoError(t, err)
+	return storageClass
+}
+
+// GetStorageClassE returns a Kubernetes storage class resource with the given name.
+func GetStorageClassE(t testing.TestingT, options *KubectlOptions, storageClassName string) (*storagev1.StorageClass, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.StorageV1().StorageClasses().Get(context.Background(), storageClassName, metav1.GetOptions{})
+}
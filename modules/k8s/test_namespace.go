@@ -0,0 +1,247 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+const (
+	// DefaultTestNamespaceServiceAccountName is the name of the ServiceAccount NewTestNamespace creates, unless
+	// TestNamespaceOptions sets another one.
+	DefaultTestNamespaceServiceAccountName = "terratest"
+
+	// testNamespaceTokenExpiration is how long the token of the ServiceAccount of a test namespace is valid for.
+	testNamespaceTokenExpiration = 24 * time.Hour
+
+	testNamespaceDeletionRetries = 60
+	testNamespaceDeletionSleep   = 5 * time.Second
+)
+
+// DefaultTestNamespaceRules are the rules of the Role NewTestNamespace binds to the ServiceAccount, unless
+// TestNamespaceOptions sets others: full access to every resource in the namespace, and nothing outside of it.
+var DefaultTestNamespaceRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{"*"},
+		Resources: []string{"*"},
+		Verbs:     []string{"*"},
+	},
+}
+
+// TestNamespaceOptions are the options of NewTestNamespace.
+type TestNamespaceOptions struct {
+	// NamePrefix is prepended to the unique name of the namespace, e.g. to tell which test a namespace belongs to.
+	NamePrefix string
+
+	// Labels are the labels of the namespace.
+	Labels map[string]string
+
+	// ServiceAccountName is the name of the ServiceAccount the returned options authenticate as. Defaults to
+	// DefaultTestNamespaceServiceAccountName.
+	ServiceAccountName string
+
+	// Rules are the rules of the Role bound to the ServiceAccount in the namespace. Defaults to
+	// DefaultTestNamespaceRules.
+	Rules []rbacv1.PolicyRule
+}
+
+// NewTestNamespace creates a uniquely named namespace, with a ServiceAccount that is bound to a Role with the rules of
+// the given namespace options, and returns KubectlOptions that target the namespace and authenticate as the
+// ServiceAccount. The namespace is created with the given options, which must be allowed to create namespaces and
+// roles, e.g. those of a cluster admin. The namespace options may be nil, to use the defaults.
+//
+// This gives every test its own sandbox with the least privileges it needs, and catches resources that silently need
+// more, e.g. cluster-scoped ones. If the given options use a kubeconfig, the returned options use a copy of it with a
+// context for the ServiceAccount, so that the original is left untouched.
+//
+// If the given testing.TestingT supports Cleanup (as Go's testing.T does), the namespace, and everything in it, is
+// deleted when the test finishes, waiting until the namespace is gone. This will fail the test if there is an error.
+func NewTestNamespace(t testing.TestingT, options *KubectlOptions, namespaceOptions *TestNamespaceOptions) *KubectlOptions {
+	namespaceKubectlOptions, err := NewTestNamespaceE(t, options, namespaceOptions)
+	require.NoError(t, err)
+	return namespaceKubectlOptions
+}
+
+// NewTestNamespaceE creates a uniquely named namespace, with a ServiceAccount that is bound to a Role with the rules of
+// the given namespace options, and returns KubectlOptions that target the namespace and authenticate as the
+// ServiceAccount. See NewTestNamespace for details.
+func NewTestNamespaceE(t testing.TestingT, options *KubectlOptions, namespaceOptions *TestNamespaceOptions) (*KubectlOptions, error) {
+	if namespaceOptions == nil {
+		namespaceOptions = &TestNamespaceOptions{}
+	}
+	serviceAccountName := namespaceOptions.ServiceAccountName
+	if serviceAccountName == "" {
+		serviceAccountName = DefaultTestNamespaceServiceAccountName
+	}
+	rules := namespaceOptions.Rules
+	if rules == nil {
+		rules = DefaultTestNamespaceRules
+	}
+
+	namespaceName := namespaceOptions.NamePrefix + strings.ToLower(random.UniqueId())
+	options.Logger.Logf(t, "Creating test namespace %s with service account %s", namespaceName, serviceAccountName)
+	err := CreateNamespaceWithMetadataE(t, options, metav1.ObjectMeta{Name: namespaceName, Labels: namespaceOptions.Labels})
+	if err != nil {
+		return nil, err
+	}
+
+	// The options the namespace is set up with, as opposed to the ones of the ServiceAccount that are returned
+	adminOptions := *options
+	adminOptions.Namespace = namespaceName
+
+	// Register the cleanup right away, so that the namespace is deleted even if setting it up fails
+	kubeConfigPath := ""
+	if tt, ok := t.(cleaner); ok {
+		tt.Cleanup(func() {
+			if kubeConfigPath != "" {
+				os.Remove(kubeConfigPath)
+			}
+			require.NoError(t, deleteTestNamespaceE(t, &adminOptions))
+		})
+	} else {
+		options.Logger.Logf(t, "Not deleting test namespace %s when the test finishes: %T does not support Cleanup", namespaceName, t)
+	}
+
+	if err := CreateServiceAccountE(t, &adminOptions, serviceAccountName); err != nil {
+		return nil, err
+	}
+	if err := bindTestNamespaceRoleE(t, &adminOptions, serviceAccountName, rules); err != nil {
+		return nil, err
+	}
+	token, err := CreateServiceAccountTokenE(t, &adminOptions, serviceAccountName, testNamespaceTokenExpiration)
+	if err != nil {
+		return nil, err
+	}
+
+	// Options that are not backed by a kubeconfig get a REST config with the token instead of a kubeconfig context
+	if options.RestConfig != nil || options.InClusterAuth {
+		config, err := GetRestConfigFromOptionsE(t, options)
+		if err != nil {
+			return nil, err
+		}
+		serviceAccountConfig := rest.AnonymousClientConfig(config)
+		serviceAccountConfig.BearerToken = token
+		serviceAccountOptions := NewKubectlOptionsWithRestConfig(serviceAccountConfig, namespaceName)
+		serviceAccountOptions.Logger = options.Logger
+		serviceAccountOptions.RequestTimeout = options.RequestTimeout
+		return serviceAccountOptions, nil
+	}
+
+	kubeConfigPath, err = copyKubeConfigForContextE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	serviceAccountOptions := NewKubectlOptions(namespaceName, kubeConfigPath, namespaceName)
+	// The auth info is named after the namespace as well, as the ServiceAccount name is the same in every test namespace
+	err = AddConfigContextForServiceAccountE(t, serviceAccountOptions, namespaceName, namespaceName, token)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range options.Env {
+		serviceAccountOptions.Env[key] = value
+	}
+	serviceAccountOptions.Logger = options.Logger
+	serviceAccountOptions.RequestTimeout = options.RequestTimeout
+	return serviceAccountOptions, nil
+}
+
+// bindTestNamespaceRoleE creates a Role with the given rules in the namespace of the given options, and binds it to the
+// given ServiceAccount. Both are named after the ServiceAccount.
+func bindTestNamespaceRoleE(t testing.TestingT, options *KubectlOptions, serviceAccountName string, rules []rbacv1.PolicyRule) error {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return err
+	}
+
+	role := rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceAccountName,
+			Namespace: options.Namespace,
+		},
+		Rules: rules,
+	}
+	if _, err := clientset.RbacV1().Roles(options.Namespace).Create(context.Background(), &role, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	roleBinding := rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceAccountName,
+			Namespace: options.Namespace,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     role.Name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      serviceAccountName,
+				Namespace: options.Namespace,
+			},
+		},
+	}
+	_, err = clientset.RbacV1().RoleBindings(options.Namespace).Create(context.Background(), &roleBinding, metav1.CreateOptions{})
+	return err
+}
+
+// copyKubeConfigForContextE copies the kubeconfig of the given options to a temp file, whose current context is the
+// context of the options, so that contexts added to the copy point to the same cluster.
+func copyKubeConfigForContextE(t testing.TestingT, options *KubectlOptions) (string, error) {
+	configPath, err := options.GetConfigPath(t)
+	if err != nil {
+		return "", err
+	}
+	tmpConfig, err := os.CreateTemp("", "terratest-kubeconfig-")
+	if err != nil {
+		return "", err
+	}
+	tmpConfig.Close()
+	if err := files.CopyFile(configPath, tmpConfig.Name()); err != nil {
+		os.Remove(tmpConfig.Name())
+		return "", err
+	}
+	if options.ContextName == "" {
+		return tmpConfig.Name(), nil
+	}
+
+	config, err := clientcmd.LoadFromFile(tmpConfig.Name())
+	if err == nil {
+		if _, ok := config.Contexts[options.ContextName]; !ok {
+			err = fmt.Errorf("context %s not found in kubeconfig %s", options.ContextName, configPath)
+		}
+	}
+	if err == nil {
+		config.CurrentContext = options.ContextName
+		err = clientcmd.WriteToFile(*config, tmpConfig.Name())
+	}
+	if err != nil {
+		os.Remove(tmpConfig.Name())
+		return "", err
+	}
+	return tmpConfig.Name(), nil
+}
+
+// deleteTestNamespaceE deletes the namespace of the given options, and everything in it, and waits until it is gone.
+func deleteTestNamespaceE(t testing.TestingT, options *KubectlOptions) error {
+	options.Logger.Logf(t, "Deleting test namespace %s", options.Namespace)
+	err := DeleteNamespaceE(t, options, options.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return WaitUntilNamespaceDeletedE(t, options, options.Namespace, testNamespaceDeletionRetries, testNamespaceDeletionSleep)
+}
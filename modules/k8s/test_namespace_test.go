@@ -0,0 +1,72 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewTestNamespaceReturnsOptionsScopedToTheNamespace(t *testing.T) {
+	t.Parallel()
+
+	adminOptions := NewKubectlOptions("", "", "default")
+	var namespaceName string
+	t.Run("sandbox", func(t *testing.T) {
+		options := NewTestNamespace(t, adminOptions, &TestNamespaceOptions{NamePrefix: "terratest-"})
+		namespaceName = options.Namespace
+		require.Contains(t, namespaceName, "terratest-")
+
+		// The service account can do anything in its namespace, but nothing outside of it
+		require.True(t, CanIDo(t, options, authv1.ResourceAttributes{Namespace: namespaceName, Verb: "create", Resource: "configmaps"}))
+		require.False(t, CanIDo(t, options, authv1.ResourceAttributes{Namespace: "kube-system", Verb: "list", Resource: "pods"}))
+		require.False(t, CanIDo(t, options, authv1.ResourceAttributes{Verb: "create", Resource: "namespaces"}))
+
+		KubectlApplyFromString(t, options, EXAMPLE_CONFIGMAP_IN_NAMESPACE_YAML)
+		RunKubectl(t, options, "get", "configmap", "terratest-configmap")
+	})
+
+	// The namespace is deleted once the subtest finishes
+	_, err := GetNamespaceE(t, adminOptions, namespaceName)
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestNewTestNamespaceBindsTheGivenRules(t *testing.T) {
+	t.Parallel()
+
+	options := NewTestNamespace(t, NewKubectlOptions("", "", "default"), &TestNamespaceOptions{
+		ServiceAccountName: "reader",
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	})
+
+	require.True(t, CanIDo(t, options, authv1.ResourceAttributes{Namespace: options.Namespace, Verb: "list", Resource: "pods"}))
+	require.False(t, CanIDo(t, options, authv1.ResourceAttributes{Namespace: options.Namespace, Verb: "create", Resource: "pods"}))
+	require.Empty(t, ListPods(t, options, metav1.ListOptions{}))
+}
+
+const EXAMPLE_CONFIGMAP_IN_NAMESPACE_YAML = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: terratest-configmap
+data:
+  key: value
+`
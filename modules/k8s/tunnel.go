@@ -6,6 +6,7 @@ package k8s
 // See: https://github.com/helm/helm/blob/master/pkg/kube/tunnel.go
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -14,11 +15,15 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
 	"k8s.io/client-go/transport/spdy"
 
@@ -64,17 +69,49 @@ func makeLabels(labels map[string]string) string {
 	return strings.Join(out, ",")
 }
 
+// TunnelPort is a port that a Tunnel forwards.
+type TunnelPort struct {
+	// Local is the port on the host. If it is 0, an open port on the host is selected when the tunnel is opened.
+	Local int
+	// Remote is the port of the resource. For services, this is the port of the service, which is mapped to the target
+	// port of the pod.
+	Remote int
+}
+
+// TunnelOptions are the options of NewTunnelWithOptions.
+type TunnelOptions struct {
+	// Ports are the ports to forward.
+	Ports []TunnelPort
+
+	// Reconnect has the tunnel select a new pod of the resource and reconnect to it when the pod it is connected to
+	// goes away, e.g. during a rolling update of a deployment, instead of breaking permanently. The local ports stay the
+	// same.
+	Reconnect bool
+
+	// Logger is the logger of the tunnel. Defaults to logger.Terratest.
+	Logger logger.TestLogger
+}
+
 // Tunnel is the main struct that configures and manages port forwading tunnels to Kubernetes resources.
 type Tunnel struct {
 	out            io.Writer
-	localPort      int
-	remotePort     int
+	ports          []TunnelPort
+	reconnect      bool
 	kubectlOptions *KubectlOptions
 	resourceType   KubeResourceType
 	resourceName   string
 	logger         logger.TestLogger
 	stopChan       chan struct{}
 	readyChan      chan struct{}
+	closeOnce      sync.Once
+
+	mutex   sync.Mutex
+	podName string
+	// opened is true once ForwardPortE started opening the tunnel, unless that failed.
+	opened bool
+	// monitorDone is closed once the goroutine that keeps the tunnel connected has returned, or nil if the tunnel was
+	// never opened.
+	monitorDone chan struct{}
 }
 
 // NewTunnel creates a new tunnel with NewTunnelWithLogger, setting logger.Terratest as the logger.
@@ -93,27 +130,97 @@ func NewTunnelWithLogger(
 	remote int,
 	logger logger.TestLogger,
 ) *Tunnel {
+	return NewTunnelWithOptions(kubectlOptions, resourceType, resourceName, TunnelOptions{
+		Ports:  []TunnelPort{{Local: local, Remote: remote}},
+		Logger: logger,
+	})
+}
+
+// NewTunnelWithOptions will create a new Tunnel struct that forwards all the given ports at once. Local ports that are
+// 0 are selected automatically when the tunnel is opened, and can be looked up with LocalPort.
+func NewTunnelWithOptions(
+	kubectlOptions *KubectlOptions,
+	resourceType KubeResourceType,
+	resourceName string,
+	tunnelOptions TunnelOptions,
+) *Tunnel {
+	tunnelLogger := tunnelOptions.Logger
+	if tunnelLogger == nil {
+		tunnelLogger = logger.Terratest
+	}
 	return &Tunnel{
 		out:            io.Discard,
-		localPort:      local,
-		remotePort:     remote,
+		ports:          append([]TunnelPort{}, tunnelOptions.Ports...),
+		reconnect:      tunnelOptions.Reconnect,
 		kubectlOptions: kubectlOptions,
 		resourceType:   resourceType,
 		resourceName:   resourceName,
-		logger:         logger,
+		logger:         tunnelLogger,
 		stopChan:       make(chan struct{}, 1),
 		readyChan:      make(chan struct{}, 1),
 	}
 }
 
-// Endpoint returns the tunnel endpoint
+// Endpoint returns the tunnel endpoint of the first port the tunnel forwards, or an empty string if the tunnel does not
+// forward any port.
 func (tunnel *Tunnel) Endpoint() string {
-	return fmt.Sprintf("localhost:%d", tunnel.localPort)
+	tunnel.mutex.Lock()
+	defer tunnel.mutex.Unlock()
+	if len(tunnel.ports) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("localhost:%d", tunnel.ports[0].Local)
 }
 
-// Close disconnects a tunnel connection by closing the StopChan, thereby stopping the goroutine.
+// EndpointForPort returns the tunnel endpoint of the given remote port, or an empty string if the tunnel does not
+// forward the port.
+func (tunnel *Tunnel) EndpointForPort(remote int) string {
+	local := tunnel.LocalPort(remote)
+	if local == 0 {
+		return ""
+	}
+	return fmt.Sprintf("localhost:%d", local)
+}
+
+// LocalPort returns the local port that the given remote port is forwarded from, or 0 if the tunnel does not forward
+// the port or has not selected a local port for it yet.
+func (tunnel *Tunnel) LocalPort(remote int) int {
+	tunnel.mutex.Lock()
+	defer tunnel.mutex.Unlock()
+	for _, port := range tunnel.ports {
+		if port.Remote == remote {
+			return port.Local
+		}
+	}
+	return 0
+}
+
+// Ports returns the ports the tunnel forwards, including the local ports selected when the tunnel was opened.
+func (tunnel *Tunnel) Ports() []TunnelPort {
+	tunnel.mutex.Lock()
+	defer tunnel.mutex.Unlock()
+	return append([]TunnelPort{}, tunnel.ports...)
+}
+
+// PodName returns the name of the pod the tunnel is connected to, which changes when the tunnel reconnects.
+func (tunnel *Tunnel) PodName() string {
+	tunnel.mutex.Lock()
+	defer tunnel.mutex.Unlock()
+	return tunnel.podName
+}
+
+// Close disconnects a tunnel connection by closing the StopChan, thereby stopping the goroutine, and waits for it to
+// stop. It is safe to call Close several times.
 func (tunnel *Tunnel) Close() {
-	close(tunnel.stopChan)
+	tunnel.closeOnce.Do(func() {
+		close(tunnel.stopChan)
+	})
+	tunnel.mutex.Lock()
+	monitorDone := tunnel.monitorDone
+	tunnel.mutex.Unlock()
+	if monitorDone != nil {
+		<-monitorDone
+	}
 }
 
 // getAttachablePodForResource will find a pod that can be port forwarded to given the provided resource type and return
@@ -143,7 +250,7 @@ func (tunnel *Tunnel) getAttachablePodForDeploymentE(t testing.TestingT) (string
 		return "", err
 	}
 	for _, pod := range deploymentPods {
-		if IsPodAvailable(&pod) {
+		if isPodAttachable(&pod) {
 			return pod.Name, nil
 		}
 	}
@@ -162,28 +269,62 @@ func (tunnel *Tunnel) getAttachablePodForServiceE(t testing.TestingT) (string, e
 		return "", err
 	}
 	for _, pod := range servicePods {
-		if IsPodAvailable(&pod) {
+		if isPodAttachable(&pod) {
 			return pod.Name, nil
 		}
 	}
 	return "", ServiceNotAvailable{service}
 }
 
-// ForwardPort opens a tunnel to a kubernetes resource, as specified by the provided tunnel struct. This will fail the
-// test if there is an error attempting to open the port.
+// isPodAttachable returns true if the pod is available and is not being deleted, e.g. by a rolling update.
+func isPodAttachable(pod *corev1.Pod) bool {
+	return pod.DeletionTimestamp == nil && IsPodAvailable(pod)
+}
+
+// ForwardPort opens a tunnel to a kubernetes resource, as specified by the provided tunnel struct. If the given
+// testing.TestingT supports Cleanup (as Go's testing.T does), the tunnel is closed when the test finishes. Otherwise,
+// Close MUST be called before the test finishes, as the tunnel logs to the test from the background when it loses its
+// connection, which panics once the test has finished. This will fail the test if there is an error attempting to open
+// the port.
 func (tunnel *Tunnel) ForwardPort(t testing.TestingT) {
 	require.NoError(t, tunnel.ForwardPortE(t))
 }
 
-// ForwardPortE opens a tunnel to a kubernetes resource, as specified by the provided tunnel struct.
+// ForwardPortE opens a tunnel to a kubernetes resource, as specified by the provided tunnel struct. If the given
+// testing.TestingT supports Cleanup (as Go's testing.T does), the tunnel is closed when the test finishes. Otherwise,
+// Close MUST be called before the test finishes (see ForwardPort). A tunnel can only be opened once: this returns a
+// TunnelAlreadyOpened error if it was, and a TunnelHasNoPorts error if the tunnel does not forward any port.
 func (tunnel *Tunnel) ForwardPortE(t testing.TestingT) error {
+	tunnel.mutex.Lock()
+	if len(tunnel.ports) == 0 {
+		tunnel.mutex.Unlock()
+		return TunnelHasNoPorts{ResourceName: tunnel.resourceName}
+	}
+	if tunnel.opened {
+		tunnel.mutex.Unlock()
+		return TunnelAlreadyOpened{ResourceName: tunnel.resourceName}
+	}
+	tunnel.opened = true
+	tunnel.mutex.Unlock()
+
+	err := tunnel.forwardPortE(t)
+	if err != nil {
+		// Opening the tunnel can be tried again
+		tunnel.mutex.Lock()
+		tunnel.opened = false
+		tunnel.mutex.Unlock()
+	}
+	return err
+}
+
+// forwardPortE opens the tunnel for ForwardPortE, once it made sure the tunnel is not already open.
+func (tunnel *Tunnel) forwardPortE(t testing.TestingT) error {
 	tunnel.logger.Logf(
 		t,
-		"Creating a port forwarding tunnel for resource %s/%s routing local port %d to remote port %d",
+		"Creating a port forwarding tunnel for resource %s/%s routing local ports to remote ports %s",
 		tunnel.resourceType.String(),
 		tunnel.resourceName,
-		tunnel.localPort,
-		tunnel.remotePort,
+		tunnel.describePorts(),
 	)
 
 	// Prepare a kubernetes client for the client-go library
@@ -206,43 +347,78 @@ func (tunnel *Tunnel) ForwardPortE(t testing.TestingT) error {
 		}
 	}
 
+	// If a local port is 0, get an available port before continuing. We do this here instead of relying on the
+	// underlying portforwarder library, because the portforwarder library does not expose the selected local port in a
+	// machine readable manner, and because reconnections must reuse the same local ports.
+	locked := false
+	for i := range tunnel.ports {
+		if tunnel.ports[i].Local != 0 {
+			continue
+		}
+		if !locked {
+			// Synchronize on the global lock to avoid race conditions with concurrently selecting the same available
+			// port, since there is a brief moment between `GetAvailablePort` and `portforwader.ForwardPorts` where the
+			// selected port is available for selection again. Tunnels with fixed local ports do not need to wait.
+			globalMutex.Lock()
+			defer globalMutex.Unlock()
+			locked = true
+		}
+		tunnel.logger.Logf(t, "Requested local port for remote port %d is 0. Selecting an open port on host system", tunnel.ports[i].Remote)
+		localPort, err := GetAvailablePortE(t)
+		if err != nil {
+			tunnel.logger.Logf(t, "Error getting available port: %s", err)
+			return err
+		}
+		tunnel.logger.Logf(t, "Selected port %d", localPort)
+		tunnel.mutex.Lock()
+		tunnel.ports[i].Local = localPort
+		tunnel.mutex.Unlock()
+	}
+
+	connection, err := tunnel.connectE(t, config, clientset)
+	if err != nil {
+		return err
+	}
+	close(tunnel.readyChan)
+
+	monitorDone := make(chan struct{})
+	tunnel.mutex.Lock()
+	tunnel.monitorDone = monitorDone
+	tunnel.mutex.Unlock()
+	go func() {
+		defer close(monitorDone)
+		tunnel.keepConnected(t, config, clientset, connection)
+	}()
+
+	if tt, ok := t.(cleaner); ok {
+		tt.Cleanup(tunnel.Close)
+	}
+	return nil
+}
+
+// tunnelConnection is a port forwarding connection to a single pod.
+type tunnelConnection struct {
+	podName string
+	// stopChan stops the connection when closed.
+	stopChan chan struct{}
+	// errChan receives the result of the connection once it ends.
+	errChan chan error
+}
+
+// connectE selects a pod of the resource and opens a port forwarding connection to it, returning once the connection is
+// ready.
+func (tunnel *Tunnel) connectE(t testing.TestingT, config *rest.Config, clientset *kubernetes.Clientset) (*tunnelConnection, error) {
 	// Find the pod to port forward to
 	podName, err := tunnel.getAttachablePodForResourceE(t)
 	if err != nil {
 		tunnel.logger.Logf(t, "Error finding available pod: %s", err)
-		return err
+		return nil, err
 	}
 	tunnel.logger.Logf(t, "Selected pod %s to open port forward to", podName)
 
-	var targetPort = tunnel.remotePort
-
-	// in case of services, find target port on pod based on service definition
-	if tunnel.resourceType == ResourceTypeService {
-		service := GetService(t, tunnel.kubectlOptions, tunnel.resourceName)
-		var portFound = false
-		for _, portSpec := range service.Spec.Ports {
-			if portSpec.Port == int32(targetPort) {
-				if portSpec.TargetPort.Type == intstr.String {
-					pod, err := GetPodE(t, tunnel.kubectlOptions, podName)
-					if err != nil {
-						return err
-					}
-					targetPort, err = getPodPortByName(pod, portSpec.TargetPort.String())
-					if err != nil {
-						tunnel.logger.Logf(t, "Error selecting port by name: %s", err)
-						return err
-					}
-					portFound = true
-					break
-				}
-				targetPort = portSpec.TargetPort.IntValue()
-				portFound = true
-				break
-			}
-		}
-		if !portFound {
-			return errors.New(fmt.Sprintf("Target port %d not found in service %s definition.", targetPort, tunnel.resourceName))
-		}
+	ports, err := tunnel.getPortMappingsE(t, podName)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build a url to the portforward endpoint
@@ -262,52 +438,161 @@ func (tunnel *Tunnel) ForwardPortE(t testing.TestingT) error {
 	transport, upgrader, err := spdy.RoundTripperFor(config)
 	if err != nil {
 		tunnel.logger.Logf(t, "Error creating http client: %s", err)
-		return err
+		return nil, err
 	}
 	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", portForwardCreateURL)
 
-	// If the localport is 0, get an available port before continuing. We do this here instead of relying on the
-	// underlying portforwarder library, because the portforwarder library does not expose the selected local port in a
-	// machine readable manner.
-	// Synchronize on the global lock to avoid race conditions with concurrently selecting the same available port,
-	// since there is a brief moment between `GetAvailablePort` and `portforwader.ForwardPorts` where the selected port
-	// is available for selection again.
-	if tunnel.localPort == 0 {
-		tunnel.logger.Logf(t, "Requested local port is 0. Selecting an open port on host system")
-		tunnel.localPort, err = GetAvailablePortE(t)
-		if err != nil {
-			tunnel.logger.Logf(t, "Error getting available port: %s", err)
-			return err
-		}
-		tunnel.logger.Logf(t, "Selected port %d", tunnel.localPort)
-		globalMutex.Lock()
-		defer globalMutex.Unlock()
-	}
-
 	// Construct a new PortForwarder struct that manages the instructed port forward tunnel
-	ports := []string{fmt.Sprintf("%d:%d", tunnel.localPort, targetPort)}
-	portforwarder, err := portforward.New(dialer, ports, tunnel.stopChan, tunnel.readyChan, tunnel.out, tunnel.out)
+	connection := &tunnelConnection{podName: podName, stopChan: make(chan struct{}), errChan: make(chan error, 1)}
+	readyChan := make(chan struct{})
+	portforwarder, err := portforward.New(dialer, ports, connection.stopChan, readyChan, tunnel.out, tunnel.out)
 	if err != nil {
 		tunnel.logger.Logf(t, "Error creating port forwarding tunnel: %s", err)
-		return err
+		return nil, err
 	}
 
 	// Open the tunnel in a goroutine so that it is available in the background. Report errors to the main goroutine via
 	// a new channel.
-	errChan := make(chan error)
 	go func() {
-		errChan <- portforwarder.ForwardPorts()
+		connection.errChan <- portforwarder.ForwardPorts()
 	}()
 
 	// Wait for an error or the tunnel to be ready
 	select {
-	case err = <-errChan:
+	case err = <-connection.errChan:
 		tunnel.logger.Logf(t, "Error starting port forwarding tunnel: %s", err)
-		return err
+		return nil, err
 	case <-portforwarder.Ready:
 		tunnel.logger.Logf(t, "Successfully created port forwarding tunnel")
-		return nil
+		tunnel.mutex.Lock()
+		tunnel.podName = podName
+		tunnel.mutex.Unlock()
+		return connection, nil
+	}
+}
+
+// keepConnected waits until the tunnel is closed, and then stops the given connection. With reconnect, it opens a new
+// connection to another pod whenever the pod of the current connection goes away or the connection is lost.
+func (tunnel *Tunnel) keepConnected(t testing.TestingT, config *rest.Config, clientset *kubernetes.Clientset, connection *tunnelConnection) {
+	for {
+		ctx, cancelWatch := context.WithCancel(context.Background())
+		podGone := make(<-chan struct{})
+		if tunnel.reconnect {
+			podGone = tunnel.watchPodGone(t, ctx, connection.podName)
+		}
+
+		select {
+		case <-tunnel.stopChan:
+			cancelWatch()
+			close(connection.stopChan)
+			<-connection.errChan
+			return
+		case err := <-connection.errChan:
+			cancelWatch()
+			tunnel.logger.Logf(t, "Lost port forwarding connection to pod %s: %v", connection.podName, err)
+			if !tunnel.reconnect {
+				return
+			}
+		case <-podGone:
+			cancelWatch()
+			tunnel.logger.Logf(t, "Pod %s is going away, reconnecting the tunnel", connection.podName)
+			close(connection.stopChan)
+			<-connection.errChan
+		}
+
+		for {
+			var err error
+			connection, err = tunnel.connectE(t, config, clientset)
+			if err == nil {
+				break
+			}
+			tunnel.logger.Logf(t, "Error reconnecting the tunnel, retrying: %s", err)
+			select {
+			case <-tunnel.stopChan:
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// watchPodGone returns a channel that is closed once the given pod is deleted or starts terminating, until the given
+// context is done.
+func (tunnel *Tunnel) watchPodGone(t testing.TestingT, ctx context.Context, podName string) <-chan struct{} {
+	podGone := make(chan struct{})
+	go func() {
+		for ctx.Err() == nil {
+			watcher, err := watchPods(t, tunnel.kubectlOptions, nameSelector(podName))(ctx)
+			if err != nil {
+				sleepContext(ctx, time.Second)
+				continue
+			}
+			for event := range watcher.ResultChan() {
+				pod, isPod := event.Object.(*corev1.Pod)
+				if event.Type == watch.Deleted || (isPod && pod.DeletionTimestamp != nil) {
+					watcher.Stop()
+					close(podGone)
+					return
+				}
+			}
+			watcher.Stop()
+		}
+	}()
+	return podGone
+}
+
+// getPortMappingsE returns the local:remote port mappings to forward to the given pod. For services, the ports of the
+// service are mapped to the target ports of the pod.
+func (tunnel *Tunnel) getPortMappingsE(t testing.TestingT, podName string) ([]string, error) {
+	mappings := []string{}
+	for _, port := range tunnel.Ports() {
+		targetPort := port.Remote
+		if tunnel.resourceType == ResourceTypeService {
+			var err error
+			targetPort, err = tunnel.getServiceTargetPortE(t, podName, port.Remote)
+			if err != nil {
+				return nil, err
+			}
+		}
+		mappings = append(mappings, fmt.Sprintf("%d:%d", port.Local, targetPort))
+	}
+	return mappings, nil
+}
+
+// getServiceTargetPortE returns the port of the given pod that the given port of the service targets.
+func (tunnel *Tunnel) getServiceTargetPortE(t testing.TestingT, podName string, servicePort int) (int, error) {
+	service, err := GetServiceE(t, tunnel.kubectlOptions, tunnel.resourceName)
+	if err != nil {
+		return 0, err
+	}
+	for _, portSpec := range service.Spec.Ports {
+		if portSpec.Port != int32(servicePort) {
+			continue
+		}
+		if portSpec.TargetPort.Type == intstr.String {
+			pod, err := GetPodE(t, tunnel.kubectlOptions, podName)
+			if err != nil {
+				return 0, err
+			}
+			targetPort, err := getPodPortByName(pod, portSpec.TargetPort.String())
+			if err != nil {
+				tunnel.logger.Logf(t, "Error selecting port by name: %s", err)
+				return 0, err
+			}
+			return targetPort, nil
+		}
+		return portSpec.TargetPort.IntValue(), nil
+	}
+	return 0, fmt.Errorf("Target port %d not found in service %s definition.", servicePort, tunnel.resourceName)
+}
+
+// describePorts returns the ports the tunnel forwards, as local:remote pairs, for log messages.
+func (tunnel *Tunnel) describePorts() string {
+	pairs := []string{}
+	for _, port := range tunnel.Ports() {
+		pairs = append(pairs, fmt.Sprintf("%d:%d", port.Local, port.Remote))
 	}
+	return strings.Join(pairs, ", ")
 }
 
 // GetAvailablePort retrieves an available port on the host machine. This delegates the port selection to the golang net
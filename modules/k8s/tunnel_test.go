@@ -18,6 +18,8 @@ import (
 
 	http_helper "github.com/gruntwork-io/terratest/modules/http-helper"
 	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTunnelOpensAPortForwardTunnelToPod(t *testing.T) {
@@ -47,6 +49,17 @@ func TestTunnelOpensAPortForwardTunnelToPod(t *testing.T) {
 		5*time.Second,
 		verifyNginxWelcomePage,
 	)
+
+	// A tunnel can only be opened once
+	require.ErrorAs(t, tunnel.ForwardPortE(t), &TunnelAlreadyOpened{})
+}
+
+func TestTunnelWithoutPorts(t *testing.T) {
+	t.Parallel()
+
+	tunnel := NewTunnelWithOptions(NewKubectlOptions("", "", "default"), ResourceTypePod, "nginx-pod", TunnelOptions{})
+	require.Equal(t, "", tunnel.Endpoint())
+	require.ErrorAs(t, tunnel.ForwardPortE(t), &TunnelHasNoPorts{})
 }
 
 func TestTunnelOpensAPortForwardTunnelToDeployment(t *testing.T) {
@@ -134,6 +147,79 @@ func TestTunnelOpensAPortForwardTunnelToService(t *testing.T) {
 	}
 }
 
+func TestTunnelForwardsSeveralPortsFromAvailableLocalPorts(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(EXAMPLE_POD_WITH_MULTIPLE_CONTAINERS_YAML_TEMPLATE, uniqueID, uniqueID)
+	defer KubectlDeleteFromString(t, options, configData)
+	KubectlApplyFromString(t, options, configData)
+	WaitUntilPodAvailable(t, options, "nginx-pod", 60, 1*time.Second)
+
+	// Open a tunnel to both containers of the pod from any available ports locally. The tunnel is closed when the test
+	// finishes.
+	tunnel := NewTunnelWithOptions(options, ResourceTypePod, "nginx-pod", TunnelOptions{
+		Ports: []TunnelPort{{Remote: 80}, {Remote: 8080}},
+	})
+	tunnel.ForwardPort(t)
+
+	require.NotZero(t, tunnel.LocalPort(80))
+	require.NotZero(t, tunnel.LocalPort(8080))
+	require.NotEqual(t, tunnel.LocalPort(80), tunnel.LocalPort(8080))
+	require.Equal(t, tunnel.Endpoint(), tunnel.EndpointForPort(80))
+	require.Empty(t, tunnel.EndpointForPort(443))
+
+	// Setup a TLS configuration to submit with the helper, a blank struct is acceptable
+	tlsConfig := tls.Config{}
+
+	for _, remotePort := range []int{80, 8080} {
+		http_helper.HttpGetWithRetryWithCustomValidation(
+			t,
+			fmt.Sprintf("http://%s", tunnel.EndpointForPort(remotePort)),
+			&tlsConfig,
+			60,
+			5*time.Second,
+			verifyNginxWelcomePage,
+		)
+	}
+}
+
+func TestTunnelReconnectsWhenThePodOfADeploymentIsReplaced(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleDeploymentYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+	WaitUntilDeploymentAvailable(t, options, "nginx-deployment", 60, 1*time.Second)
+
+	tunnel := NewTunnelWithOptions(options, ResourceTypeDeployment, "nginx-deployment", TunnelOptions{
+		Ports:     []TunnelPort{{Remote: 80}},
+		Reconnect: true,
+	})
+	defer tunnel.Close()
+	tunnel.ForwardPort(t)
+
+	// Setup a TLS configuration to submit with the helper, a blank struct is acceptable
+	tlsConfig := tls.Config{}
+	endpoint := fmt.Sprintf("http://%s", tunnel.Endpoint())
+	http_helper.HttpGetWithRetryWithCustomValidation(t, endpoint, &tlsConfig, 60, 5*time.Second, verifyNginxWelcomePage)
+
+	// Delete the pod the tunnel is connected to, so that the tunnel has to reconnect to another pod of the deployment on
+	// the same local port
+	originalPodName := tunnel.PodName()
+	RunKubectl(t, options, "delete", "pod", originalPodName, "--wait=false")
+	retry.DoWithRetry(t, "Wait for the tunnel to reconnect", 60, 1*time.Second, func() (string, error) {
+		if tunnel.PodName() == originalPodName {
+			return "", fmt.Errorf("tunnel is still connected to pod %s", originalPodName)
+		}
+		return "", nil
+	})
+	http_helper.HttpGetWithRetryWithCustomValidation(t, endpoint, &tlsConfig, 60, 5*time.Second, verifyNginxWelcomePage)
+}
+
 func verifyNginxWelcomePage(statusCode int, body string) bool {
 	if statusCode != 200 {
 		return false
@@ -0,0 +1,207 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// watchFunc starts watching the objects a wait is about.
+type watchFunc func(ctx context.Context) (watch.Interface, error)
+
+// waitUntilWatchedContextE runs the given check until it succeeds, like retry.DoWithRetryContextE, but instead of
+// sleeping between attempts, it runs the check again as soon as the watch started by startWatch reports a change, or
+// after sleepBetweenRetries if there is none, in case the check failed for a reason the watch does not see, such as a
+// throttled request. This spares the API server the requests of attempts that could not succeed, and spares the test
+// the sleep after a change. If the watch can not be started, e.g. because the user is not allowed to watch, it falls
+// back to polling every sleepBetweenRetries. A watch that ends, or reports an error, is started again, but no sooner
+// than sleepBetweenRetries after it was started, so that a watch that keeps failing does not hammer the API server.
+// Either way, it gives up once retries times sleepBetweenRetries have passed, with a MaxRetriesExceeded error, or as
+// soon as the given context is done, with a ContextDone error. Like the retry functions, it records the attempts in
+// retry.DefaultCollector, if set.
+func waitUntilWatchedContextE(
+	t testing.TestingT,
+	ctx context.Context,
+	actionDescription string,
+	retries int,
+	sleepBetweenRetries time.Duration,
+	startWatch watchFunc,
+	check func() (string, error),
+) (string, error) {
+	rec := retry.DefaultCollector.Start(t, actionDescription, retries)
+	message, err := waitUntilWatched(t, ctx, rec, actionDescription, retries, sleepBetweenRetries, startWatch, check)
+	rec.Finish(err)
+	return message, err
+}
+
+func waitUntilWatched(
+	t testing.TestingT,
+	ctx context.Context,
+	rec *retry.Recorder,
+	actionDescription string,
+	retries int,
+	sleepBetweenRetries time.Duration,
+	startWatch watchFunc,
+	check func() (string, error),
+) (string, error) {
+	log := logger.Default.With("module", "k8s", "description", actionDescription)
+
+	timeout := time.Duration(retries) * sleepBetweenRetries
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var watcher watch.Interface
+	defer func() {
+		if watcher != nil {
+			watcher.Stop()
+		}
+	}()
+
+	polling := false
+	var watchStarted time.Time
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		// Start the watch before the check, so that no change between the check and the watch goes unnoticed
+		if watcher == nil && !polling {
+			var err error
+			watchStarted = time.Now()
+			watcher, err = startWatch(timeoutCtx)
+			if err != nil {
+				log.Debugf(t, "Watching failed, falling back to polling every %s: %s", sleepBetweenRetries, err)
+				watcher = nil
+				polling = true
+			}
+		}
+
+		attemptLog := log.With("attempt", attempt)
+		attemptLog.Debugf(t, "%s", actionDescription)
+		attemptStart := time.Now()
+		message, err := check()
+		rec.Attempt(attemptStart, err)
+		if err == nil {
+			return message, nil
+		}
+		if _, isFatalErr := err.(retry.FatalError); isFatalErr {
+			attemptLog.Errorf(t, "Returning due to fatal error: %v", err)
+			return message, err
+		}
+		lastErr = err
+
+		if polling {
+			attemptLog.Debugf(t, "%s returned an error: %s. Sleeping for %s and will try again.", actionDescription, err.Error(), sleepBetweenRetries)
+			sleepContext(timeoutCtx, sleepBetweenRetries)
+		} else {
+			attemptLog.Debugf(t, "%s returned an error: %s. Waiting for a change, or at most %s, and will try again.", actionDescription, err.Error(), sleepBetweenRetries)
+			timer := time.NewTimer(sleepBetweenRetries)
+			select {
+			case event, isOpen := <-watcher.ResultChan():
+				// The API server ends watches after a while, in which case the watch is started again
+				if !isOpen || event.Type == watch.Error {
+					watcher.Stop()
+					watcher = nil
+					attemptLog.Debugf(t, "Watch ended, will watch again")
+					sleepContext(timeoutCtx, sleepBetweenRetries-time.Since(watchStarted))
+				}
+				drainEvents(watcher)
+			case <-timer.C:
+			case <-timeoutCtx.Done():
+			}
+			timer.Stop()
+		}
+
+		if ctx.Err() != nil {
+			log.Warnf(t, "Giving up on %s: %v", actionDescription, ctx.Err())
+			return "", retry.ContextDone{Description: actionDescription, Underlying: ctx.Err(), LastError: lastErr}
+		}
+		if timeoutCtx.Err() != nil {
+			log.Warnf(t, "Giving up on %s after %s and %d checks: %v", actionDescription, timeout, attempt, lastErr)
+			return "", retry.MaxRetriesExceeded{Description: actionDescription, MaxRetries: retries}
+		}
+	}
+}
+
+// drainEvents discards the events that are already queued on the given watch, so that a burst of changes leads to a
+// single check.
+func drainEvents(watcher watch.Interface) {
+	if watcher == nil {
+		return
+	}
+	for {
+		select {
+		case event, isOpen := <-watcher.ResultChan():
+			if !isOpen || event.Type == watch.Error {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// nameSelector returns list options that select the object with the given name, for watching a single object.
+func nameSelector(name string) metav1.ListOptions {
+	return metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()}
+}
+
+// mergedWatch combines several watches into one, which ends as soon as any of them ends.
+type mergedWatch struct {
+	watchers []watch.Interface
+	result   chan watch.Event
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// newMergedWatch returns a watch that reports the events of all the given watches.
+func newMergedWatch(watchers []watch.Interface) watch.Interface {
+	merged := &mergedWatch{watchers: watchers, result: make(chan watch.Event), stop: make(chan struct{})}
+
+	var wg sync.WaitGroup
+	for _, watcher := range watchers {
+		wg.Add(1)
+		go func(watcher watch.Interface) {
+			defer wg.Done()
+			defer merged.Stop()
+			for {
+				select {
+				case event, isOpen := <-watcher.ResultChan():
+					if !isOpen {
+						return
+					}
+					select {
+					case merged.result <- event:
+					case <-merged.stop:
+						return
+					}
+				case <-merged.stop:
+					return
+				}
+			}
+		}(watcher)
+	}
+	go func() {
+		wg.Wait()
+		close(merged.result)
+	}()
+	return merged
+}
+
+func (merged *mergedWatch) Stop() {
+	merged.stopOnce.Do(func() {
+		close(merged.stop)
+		for _, watcher := range merged.watchers {
+			watcher.Stop()
+		}
+	})
+}
+
+func (merged *mergedWatch) ResultChan() <-chan watch.Event {
+	return merged.result
+}
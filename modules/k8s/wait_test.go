@@ -0,0 +1,222 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/gruntwork-io/terratest/modules/retry"
+)
+
+var errNotYet = errors.New("not yet")
+
+// checkSucceedingAfter returns a check that fails until it is called for the given number of times.
+func checkSucceedingAfter(calls int, counter *int32) func() (string, error) {
+	return func() (string, error) {
+		if atomic.AddInt32(counter, 1) < int32(calls) {
+			return "", errNotYet
+		}
+		return "done", nil
+	}
+}
+
+func TestWaitUntilWatchedChecksAgainOnChange(t *testing.T) {
+	t.Parallel()
+
+	fakeWatch := watch.NewFake()
+	var checks int32
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		fakeWatch.Modify(&corev1.Pod{})
+	}()
+
+	start := time.Now()
+	// With polling, the second check would only happen after an hour
+	message, err := waitUntilWatchedContextE(
+		t,
+		context.Background(),
+		"Wait for change",
+		2,
+		time.Hour,
+		func(ctx context.Context) (watch.Interface, error) { return fakeWatch, nil },
+		checkSucceedingAfter(2, &checks),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "done", message)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&checks))
+	assert.Less(t, time.Since(start), time.Minute)
+}
+
+func TestWaitUntilWatchedChecksAgainWithoutChange(t *testing.T) {
+	t.Parallel()
+
+	var checks int32
+	start := time.Now()
+	// The first check fails for a reason the watch does not see, e.g. a throttled request, and the watch stays quiet
+	message, err := waitUntilWatchedContextE(
+		t,
+		context.Background(),
+		"Wait for change",
+		1000,
+		50*time.Millisecond,
+		func(ctx context.Context) (watch.Interface, error) { return watch.NewFake(), nil },
+		checkSucceedingAfter(2, &checks),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "done", message)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&checks))
+	// Well before the timeout of 50s
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestWaitUntilWatchedRestartsClosedWatch(t *testing.T) {
+	t.Parallel()
+
+	var watches, checks int32
+	start := time.Now()
+	message, err := waitUntilWatchedContextE(
+		t,
+		context.Background(),
+		"Wait for change",
+		100,
+		50*time.Millisecond,
+		func(ctx context.Context) (watch.Interface, error) {
+			fakeWatch := watch.NewFake()
+			if atomic.AddInt32(&watches, 1) == 1 {
+				fakeWatch.Stop()
+			}
+			return fakeWatch, nil
+		},
+		checkSucceedingAfter(2, &checks),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "done", message)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&watches))
+	// The watch is started again no sooner than the sleep between retries
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestWaitUntilWatchedBacksOffWatchesThatKeepEnding(t *testing.T) {
+	t.Parallel()
+
+	var watches, checks int32
+	_, err := waitUntilWatchedContextE(
+		t,
+		context.Background(),
+		"Wait for change",
+		5,
+		20*time.Millisecond,
+		func(ctx context.Context) (watch.Interface, error) {
+			atomic.AddInt32(&watches, 1)
+			fakeWatch := watch.NewFake()
+			fakeWatch.Stop()
+			return fakeWatch, nil
+		},
+		checkSucceedingAfter(1000, &checks),
+	)
+	require.Error(t, err)
+	assert.Equal(t, "'Wait for change' unsuccessful after 5 retries", err.Error())
+	// Without backing off, this would check and watch again thousands of times
+	assert.LessOrEqual(t, atomic.LoadInt32(&watches), int32(6))
+	assert.LessOrEqual(t, atomic.LoadInt32(&checks), int32(6))
+}
+
+func TestWaitUntilWatchedFallsBackToPolling(t *testing.T) {
+	t.Parallel()
+
+	var checks int32
+	message, err := waitUntilWatchedContextE(
+		t,
+		context.Background(),
+		"Wait for change",
+		10,
+		10*time.Millisecond,
+		func(ctx context.Context) (watch.Interface, error) { return nil, errors.New("forbidden") },
+		checkSucceedingAfter(3, &checks),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "done", message)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&checks))
+}
+
+func TestWaitUntilWatchedGivesUp(t *testing.T) {
+	t.Parallel()
+
+	var checks int32
+	_, err := waitUntilWatchedContextE(
+		t,
+		context.Background(),
+		"Wait for change",
+		3,
+		10*time.Millisecond,
+		func(ctx context.Context) (watch.Interface, error) { return watch.NewFake(), nil },
+		checkSucceedingAfter(100, &checks),
+	)
+	require.Error(t, err)
+	assert.IsType(t, retry.MaxRetriesExceeded{}, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = waitUntilWatchedContextE(
+		t,
+		ctx,
+		"Wait for change",
+		3,
+		time.Hour,
+		func(ctx context.Context) (watch.Interface, error) { return watch.NewFake(), nil },
+		checkSucceedingAfter(100, &checks),
+	)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestMergedWatchReportsAllEventsAndEndsWithAnyWatch(t *testing.T) {
+	t.Parallel()
+
+	first, second := watch.NewFake(), watch.NewFake()
+	merged := newMergedWatch([]watch.Interface{first, second})
+
+	go second.Modify(&corev1.Pod{})
+	event := <-merged.ResultChan()
+	assert.Equal(t, watch.Modified, event.Type)
+
+	first.Stop()
+	_, isOpen := <-merged.ResultChan()
+	assert.False(t, isOpen)
+	assert.True(t, second.IsStopped())
+}
+
+// Not parallel, as this test swaps out the package level DefaultCollector.
+func TestWaitUntilWatchedRecordsAttempts(t *testing.T) {
+	collector := retry.NewCollector()
+	retry.DefaultCollector = collector
+	defer func() { retry.DefaultCollector = nil }()
+
+	var checks int32
+	_, err := waitUntilWatchedContextE(
+		t,
+		context.Background(),
+		"Wait for change",
+		10,
+		10*time.Millisecond,
+		func(ctx context.Context) (watch.Interface, error) { return nil, errors.New("forbidden") },
+		checkSucceedingAfter(3, &checks),
+	)
+	require.NoError(t, err)
+
+	records := collector.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, "Wait for change", records[0].Description)
+	assert.Equal(t, 10, records[0].MaxRetries)
+	assert.Equal(t, retry.OutcomeSucceeded, records[0].Outcome)
+	require.Len(t, records[0].Attempts, 3)
+	assert.Equal(t, errNotYet.Error(), records[0].Attempts[0].Error)
+	assert.Equal(t, "", records[0].Attempts[2].Error)
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// Level is the severity of a log message. The levels have the same values as those of log/slog, so they convert to
+// slog.Level directly.
+type Level int
+
+const (
+	// LevelDebug is for chatter that is only useful when debugging, such as the attempts of retried actions.
+	LevelDebug Level = -4
+	// LevelInfo is the level of Logf, and of all messages that do not set one.
+	LevelInfo Level = 0
+	// LevelWarn is for unexpected conditions that do not fail a test by themselves.
+	LevelWarn Level = 4
+	// LevelError is for failures, such as commands that exit with an error.
+	LevelError Level = 8
+)
+
+func (level Level) String() string {
+	switch {
+	case level < LevelInfo:
+		return "DEBUG"
+	case level < LevelWarn:
+		return "INFO"
+	case level < LevelError:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// Field is a key-value pair logged along with a message, e.g. the module, command, resource or attempt the message is
+// about.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Entry is a single log message, along with its level and fields.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+// StructuredLogger is a TestLogger that handles the level and fields of messages itself, e.g. to write them as JSON.
+// See NewSlogLogger for an implementation that hands the messages to a log/slog handler.
+type StructuredLogger interface {
+	TestLogger
+	LogEntry(t testing.TestingT, entry Entry)
+}
+
+// toFields converts alternating keys and values to fields.
+func toFields(keysAndValues []interface{}) []Field {
+	fields := []Field{}
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		if i+1 == len(keysAndValues) {
+			fields = append(fields, Field{Key: key, Value: "!MISSING"})
+			break
+		}
+		fields = append(fields, Field{Key: key, Value: keysAndValues[i+1]})
+	}
+	return fields
+}
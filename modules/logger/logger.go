@@ -42,33 +42,132 @@ var (
 	TestingT = New(testingT{})
 )
 
+// TestLogger is the backend a Logger writes its messages to. TestLoggers that also implement StructuredLogger receive
+// the level and fields of each message; all others receive just the message, so that the output of the built-in
+// loggers (Default, Terratest, TestingT and Discard) stays the same regardless of levels and fields.
 type TestLogger interface {
 	Logf(t testing.TestingT, format string, args ...interface{})
 }
 
+// Logger logs messages at a level, along with key-value fields, to a TestLogger. Loggers are immutable: With and
+// WithMinLevel return a new Logger, so that a logger can be shared between parallel tests.
 type Logger struct {
-	l TestLogger
+	l        TestLogger
+	fields   []Field
+	minLevel Level
 }
 
+// New creates a Logger that logs all messages, at any level, to the given TestLogger.
 func New(l TestLogger) *Logger {
 	return &Logger{
-		l,
+		l:        l,
+		minLevel: LevelDebug,
 	}
 }
 
+// With returns a Logger that adds the given key-value pairs as fields to every message, e.g.
+// `logger.Default.With("module", "terraform", "command", "apply")`. Keys must be strings; a trailing key without a
+// value is logged with the value "!MISSING".
+func (l *Logger) With(keysAndValues ...interface{}) *Logger {
+	derived := l.orDefault().clone()
+	derived.fields = append(derived.fields, toFields(keysAndValues)...)
+	return derived
+}
+
+// WithMinLevel returns a Logger that drops all messages below the given level, e.g. LevelInfo to hide debug messages
+// such as the attempts of retried actions.
+func (l *Logger) WithMinLevel(level Level) *Logger {
+	derived := l.orDefault().clone()
+	derived.minLevel = level
+	return derived
+}
+
+// Enabled returns true if messages at the given level are logged.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.orDefault().minLevel
+}
+
+// Logf logs the given format and arguments at LevelInfo.
 func (l *Logger) Logf(t testing.TestingT, format string, args ...interface{}) {
 	if tt, ok := t.(helper); ok {
 		tt.Helper()
 	}
+	l.log(t, LevelInfo, format, args...)
+}
 
-	// methods can be called on (typed) nil pointers. In this case, use the Default function to log. This enables the
-	// caller to do `var l *Logger` and then use the logger already.
-	if l == nil || l.l == nil {
-		Default.Logf(t, format, args...)
+// Debugf logs the given format and arguments at LevelDebug.
+func (l *Logger) Debugf(t testing.TestingT, format string, args ...interface{}) {
+	if tt, ok := t.(helper); ok {
+		tt.Helper()
+	}
+	l.log(t, LevelDebug, format, args...)
+}
+
+// Infof logs the given format and arguments at LevelInfo. It is the same as Logf.
+func (l *Logger) Infof(t testing.TestingT, format string, args ...interface{}) {
+	if tt, ok := t.(helper); ok {
+		tt.Helper()
+	}
+	l.log(t, LevelInfo, format, args...)
+}
+
+// Warnf logs the given format and arguments at LevelWarn.
+func (l *Logger) Warnf(t testing.TestingT, format string, args ...interface{}) {
+	if tt, ok := t.(helper); ok {
+		tt.Helper()
+	}
+	l.log(t, LevelWarn, format, args...)
+}
+
+// Errorf logs the given format and arguments at LevelError.
+func (l *Logger) Errorf(t testing.TestingT, format string, args ...interface{}) {
+	if tt, ok := t.(helper); ok {
+		tt.Helper()
+	}
+	l.log(t, LevelError, format, args...)
+}
+
+// log hands the message to the TestLogger of the logger. All the logging methods call it directly, so that the depth
+// of the call stack, which terratestLogger relies on to find the caller, is the same for all of them.
+func (l *Logger) log(t testing.TestingT, level Level, format string, args ...interface{}) {
+	if tt, ok := t.(helper); ok {
+		tt.Helper()
+	}
+
+	l = l.orDefault()
+	if level < l.minLevel {
 		return
 	}
 
-	l.l.Logf(t, format, args...)
+	message := Redact(fmt.Sprintf(format, args...))
+	if structured, ok := l.l.(StructuredLogger); ok {
+		structured.LogEntry(t, Entry{
+			Time:    time.Now(),
+			Level:   level,
+			Message: message,
+			Fields:  redactFields(l.fields),
+		})
+		return
+	}
+
+	l.l.Logf(t, "%s", message)
+}
+
+// orDefault returns the logger itself, or Default if it has no TestLogger. Methods can be called on (typed) nil
+// pointers, which enables the caller to do `var l *Logger` and then use the logger already.
+func (l *Logger) orDefault() *Logger {
+	if l == nil || l.l == nil {
+		return Default
+	}
+	return l
+}
+
+func (l *Logger) clone() *Logger {
+	return &Logger{
+		l:        l.l,
+		fields:   append([]Field{}, l.fields...),
+		minLevel: l.minLevel,
+	}
 }
 
 // helper is used to mark this library as a "helper", and thus not appearing in the line numbers. testing.T implements
@@ -88,7 +187,7 @@ func (_ testingT) Logf(t testing.TestingT, format string, args ...interface{}) {
 	tt, ok := t.(*gotesting.T)
 	if !ok {
 		// fallback
-		DoLog(t, 2, os.Stdout, fmt.Sprintf(format, args...))
+		DoLog(t, 4, os.Stdout, fmt.Sprintf(format, args...))
 		return
 	}
 
@@ -100,7 +199,7 @@ func (_ testingT) Logf(t testing.TestingT, format string, args ...interface{}) {
 type terratestLogger struct{}
 
 func (_ terratestLogger) Logf(t testing.TestingT, format string, args ...interface{}) {
-	DoLog(t, 3, os.Stdout, fmt.Sprintf(format, args...))
+	DoLog(t, 4, os.Stdout, fmt.Sprintf(format, args...))
 }
 
 // Deprecated: use Logger instead, as it provides more flexibility on logging.
@@ -147,12 +246,12 @@ func Log(t testing.TestingT, args ...interface{}) {
 var mutexStdout sync.Mutex
 
 // DoLog logs the given arguments to the given writer, along with a timestamp and information about what test and file is
-// doing the logging.
+// doing the logging. Secrets are redacted (see RegisterSecret).
 func DoLog(t testing.TestingT, callDepth int, writer io.Writer, args ...interface{}) {
 	date := time.Now()
 	prefix := fmt.Sprintf("%s %s %s:", t.Name(), date.Format(time.RFC3339), CallerPrefix(callDepth+1))
 	allArgs := append([]interface{}{prefix}, args...)
-	fmt.Fprintln(writer, allArgs...)
+	fmt.Fprint(writer, Redact(fmt.Sprintln(allArgs...)))
 }
 
 // CallerPrefix returns the file and line number information about the methods that called this method, based on the current
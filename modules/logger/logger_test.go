@@ -106,3 +106,86 @@ func TestLockedLog(t *testing.T) {
 	}
 
 }
+
+type structuredLogger struct {
+	entries []Entry
+}
+
+func (s *structuredLogger) Logf(t tftesting.TestingT, format string, args ...interface{}) {
+	s.entries = append(s.entries, Entry{Level: LevelInfo, Message: fmt.Sprintf(format, args...)})
+}
+
+func (s *structuredLogger) LogEntry(t tftesting.TestingT, entry Entry) {
+	s.entries = append(s.entries, entry)
+}
+
+func TestLevelsAndFields(t *testing.T) {
+	t.Parallel()
+
+	s := &structuredLogger{}
+	l := New(s).With("module", "terraform")
+	l.Debugf(t, "debug %d", 1)
+	l.With("command", "apply", "attempt").Infof(t, "info")
+	l.Warnf(t, "warn")
+	l.Errorf(t, "error")
+
+	require.Len(t, s.entries, 4)
+	assert.Equal(t, LevelDebug, s.entries[0].Level)
+	assert.Equal(t, "debug 1", s.entries[0].Message)
+	assert.Equal(t, []Field{{"module", "terraform"}}, s.entries[0].Fields)
+	assert.Equal(t, LevelInfo, s.entries[1].Level)
+	assert.Equal(t, []Field{{"module", "terraform"}, {"command", "apply"}, {"attempt", "!MISSING"}}, s.entries[1].Fields)
+	assert.Equal(t, LevelWarn, s.entries[2].Level)
+	assert.Equal(t, LevelError, s.entries[3].Level)
+	// With does not change the logger it derives from
+	assert.Equal(t, []Field{{"module", "terraform"}}, s.entries[3].Fields)
+}
+
+func TestWithMinLevel(t *testing.T) {
+	t.Parallel()
+
+	c := &customLogger{}
+	l := New(c).WithMinLevel(LevelWarn)
+	l.Debugf(t, "debug")
+	l.Logf(t, "info")
+	l.Warnf(t, "warn")
+	l.With("module", "retry").Errorf(t, "error")
+
+	assert.False(t, l.Enabled(LevelInfo))
+	assert.True(t, l.Enabled(LevelError))
+	// Plain loggers receive just the message
+	assert.Equal(t, []string{"warn", "error"}, c.logs)
+}
+
+func TestLevelString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "DEBUG", LevelDebug.String())
+	assert.Equal(t, "INFO", LevelInfo.String())
+	assert.Equal(t, "WARN", LevelWarn.String())
+	assert.Equal(t, "ERROR", LevelError.String())
+}
+
+func TestTerratestLoggerReportsCaller(t *testing.T) {
+	// should not call t.Parallel() since we are modifying os.Stdout
+	stdout := os.Stdout
+	t.Cleanup(func() {
+		os.Stdout = stdout
+	})
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	Terratest.Logf(t, "logf")
+	Terratest.With("module", "test").Warnf(t, "warnf")
+	var l *Logger
+	l.Errorf(t, "nil logger")
+	w.Close()
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 3)
+	for _, line := range lines {
+		assert.Regexp(t, fmt.Sprintf("^%s .+? logger_test.go:[0-9]+: ", t.Name()), line)
+	}
+}
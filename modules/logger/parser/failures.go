@@ -0,0 +1,390 @@
+// Package logger/parser contains methods to parse and restructure log output from go testing and terratest
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	junitparser "github.com/jstemmer/go-junit-report/parser"
+	"github.com/sirupsen/logrus"
+)
+
+// FailureTailLines is the number of lines before the `--- FAIL` line of a test that are extracted when nothing more
+// specific (a failed assertion, a terraform error or a panic) is found in its log.
+const FailureTailLines = 20
+
+// maxExcerptLines bounds the length of a single excerpt, e.g. of a goroutine stack or a terraform diagnostic.
+const maxExcerptLines = 50
+
+// ExcerptKind describes what an excerpt of the log of a failed test shows.
+type ExcerptKind string
+
+const (
+	// ExcerptAssertion is a failed testify assertion, from its `Error Trace:` to the end of its message.
+	ExcerptAssertion ExcerptKind = "assertion"
+	// ExcerptTerraformError is the last `Error:` diagnostic that terraform reported.
+	ExcerptTerraformError ExcerptKind = "terraform_error"
+	// ExcerptPanic is a panic along with its goroutine stack.
+	ExcerptPanic ExcerptKind = "panic"
+	// ExcerptTail is the end of the log of a test, right before its `--- FAIL` line.
+	ExcerptTail ExcerptKind = "tail"
+	// ExcerptTimeout is why a test did not finish when go test timed out, along with the goroutine stack of the test.
+	ExcerptTimeout ExcerptKind = "timeout"
+)
+
+// Excerpt is a part of the log of a failed test that shows what went wrong.
+type Excerpt struct {
+	Kind  ExcerptKind `json:"kind"`
+	Lines []string    `json:"lines"`
+}
+
+// Failure holds the excerpts extracted from the log of a failed test.
+type Failure struct {
+	Package string `json:"package"`
+	Test    string `json:"test"`
+	// Message is a single line summary of the failure, e.g. the error message of a failed assertion.
+	Message  string    `json:"message"`
+	Excerpts []Excerpt `json:"excerpts"`
+}
+
+// String renders all the excerpts of the failure as plain text.
+func (failure Failure) String() string {
+	parts := []string{}
+	for _, excerpt := range failure.Excerpts {
+		parts = append(parts, strings.Join(excerpt.Lines, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+var (
+	// A line logged by terratest's logger, e.g. `TestFoo 2023-09-21T10:00:00Z logger.go:66: message`
+	regexTerratestLogPrefix = regexp.MustCompile(`^\S+ \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2}) \S+:\d+: ?`)
+	// The line the testing package writes before the message of t.Error, e.g. `    apply_test.go:26:`
+	regexTestErrorHeader = regexp.MustCompile(`^(\s*)\S+\.go:\d+:\s*$`)
+	regexAssertionStart  = regexp.MustCompile(`^\s*Error Trace:`)
+	regexAssertionError  = regexp.MustCompile(`^\s*Error:\s*(.*)$`)
+	regexTerraformError  = regexp.MustCompile(`^(?:[│|]\s*)?Error: (.+)$`)
+)
+
+// ExtractFailures extracts the excerpts that show what went wrong from the logs of all the failed tests in the given
+// report. Panics are looked up in the log of the test itself and in the summary log, which is where the plain text
+// parser puts them. Tests that were in progress when go test timed out count as failed (see DetectTimeouts).
+func ExtractFailures(report *junitparser.Report, logs TestLogs) []Failure {
+	return extractFailures(report, logs, DetectTimeouts(report, logs))
+}
+
+// extractFailures extracts the failures of the failed tests in the given report, and of the tests that were in
+// progress when the given timeouts happened.
+func extractFailures(report *junitparser.Report, logs TestLogs, timeouts []Timeout) []Failure {
+	summaryLog, _ := logs.Log("summary")
+	summaryPanics := extractPanics(strings.Split(summaryLog, "\n"))
+
+	timedOut := map[string]Excerpt{}
+	for _, timeout := range timeouts {
+		for _, test := range timeout.InProgress {
+			timedOut[testKey(timeout.Package, test.Test)] = timeoutExcerpt(timeout, test)
+		}
+	}
+
+	failures := []Failure{}
+	for _, pkg := range report.Packages {
+		for _, test := range pkg.Tests {
+			timeout, isTimedOut := timedOut[testKey(pkg.Name, test.Name)]
+			if test.Result != junitparser.FAIL && !isTimedOut {
+				continue
+			}
+			log, err := logs.Log(test.Name)
+			if err != nil {
+				log = strings.Join(test.Output, "\n")
+			}
+			failure := extractFailure(test.Name, log, summaryPanics)
+			failure.Package = pkg.Name
+			if isTimedOut {
+				failure.Excerpts = append([]Excerpt{timeout}, failure.Excerpts...)
+				failure.Message = failureMessage(failure.Excerpts)
+			}
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+// extractFailure extracts the excerpts from the log of a single failed test. Of the given panics, those whose stack
+// runs through the test are included.
+func extractFailure(testName string, log string, panics [][]string) Failure {
+	lines := strings.Split(strings.TrimRight(log, "\n"), "\n")
+	failure := Failure{Test: testName, Excerpts: []Excerpt{}}
+
+	for _, assertion := range extractAssertions(lines) {
+		failure.Excerpts = append(failure.Excerpts, Excerpt{Kind: ExcerptAssertion, Lines: assertion})
+	}
+	if terraformError := extractLastTerraformError(lines); terraformError != nil {
+		failure.Excerpts = append(failure.Excerpts, Excerpt{Kind: ExcerptTerraformError, Lines: terraformError})
+	}
+
+	testPanics := extractPanics(lines)
+	if len(testPanics) == 0 {
+		for _, panicLines := range panics {
+			if panicRunsThroughTest(panicLines, testName) {
+				testPanics = append(testPanics, panicLines)
+			}
+		}
+	}
+	for _, panicLines := range testPanics {
+		// The goroutines of all running tests are dumped on a timeout, so these are reported as ExcerptTimeout instead
+		if !regexTimeoutPanic.MatchString(panicLines[0]) {
+			failure.Excerpts = append(failure.Excerpts, Excerpt{Kind: ExcerptPanic, Lines: panicLines})
+		}
+	}
+
+	if len(failure.Excerpts) == 0 {
+		if tail := extractTail(lines, testName, FailureTailLines); len(tail) > 0 {
+			failure.Excerpts = append(failure.Excerpts, Excerpt{Kind: ExcerptTail, Lines: tail})
+		}
+	}
+
+	failure.Message = failureMessage(failure.Excerpts)
+	return failure
+}
+
+// extractAssertions returns the messages of all the failed testify assertions in the given lines. Such a message looks
+// like this, where the lines of the message are indented deeper than the header with the location of the assertion:
+//
+//	apply_test.go:26:
+//	    	Error Trace:	apply_test.go:26
+//	    	Error:      	Expected value not to be nil.
+//	    	Test:       	TestApply
+func extractAssertions(lines []string) [][]string {
+	assertions := [][]string{}
+	for i := 0; i < len(lines); i++ {
+		if !regexAssertionStart.MatchString(lines[i]) {
+			continue
+		}
+
+		start := i
+		indent := len(getIndent(lines[i])) - 1
+		if i > 0 {
+			if match := regexTestErrorHeader.FindStringSubmatch(lines[i-1]); match != nil {
+				start = i - 1
+				indent = len(match[1])
+			}
+		}
+
+		end := i + 1
+		for end < len(lines) && end-start < maxExcerptLines && len(getIndent(lines[end])) > indent && strings.TrimSpace(lines[end]) != "" {
+			end++
+		}
+		assertions = append(assertions, trimIndent(lines[start:end]))
+		i = end - 1
+	}
+	return assertions
+}
+
+// extractLastTerraformError returns the last `Error:` diagnostic that terraform reported in the given lines, or nil if
+// there is none. Diagnostics are either framed by box drawing characters, in which case the frame delimits them, or
+// plain when terraform runs with -no-color, in which case they run until the next go test status line.
+func extractLastTerraformError(lines []string) []string {
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if regexTerraformError.MatchString(stripTerratestLogPrefix(lines[i])) {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return nil
+	}
+
+	framed := strings.HasPrefix(stripTerratestLogPrefix(lines[start]), "│")
+	diagnostic := []string{}
+	for i := start; i < len(lines) && len(diagnostic) < maxExcerptLines; i++ {
+		line := stripTerratestLogPrefix(lines[i])
+		if i > start {
+			if framed && !strings.HasPrefix(line, "│") {
+				break
+			}
+			if !framed && (isStatusLine(line) || isResultLine(line) || regexTerraformError.MatchString(line)) {
+				break
+			}
+		}
+		diagnostic = append(diagnostic, line)
+	}
+	return trimTrailingBlankLines(diagnostic)
+}
+
+// extractPanics returns all the panics in the given lines, each along with its goroutine stack.
+func extractPanics(lines []string) [][]string {
+	panics := [][]string{}
+	for i := 0; i < len(lines); i++ {
+		if !isPanicLine(lines[i]) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && end-i < maxExcerptLines && !isPanicLine(lines[end]) && !isSummaryLine(lines[end]) && !isResultLine(lines[end]) && !strings.HasPrefix(lines[end], "exit status") {
+			end++
+		}
+		panics = append(panics, trimTrailingBlankLines(lines[i:end]))
+		i = end - 1
+	}
+	return panics
+}
+
+// timeoutExcerpt returns the excerpt that shows why the given test did not finish when go test timed out.
+func timeoutExcerpt(timeout Timeout, test InProgressTest) Excerpt {
+	lines := []string{timeoutMessage(timeout, test)}
+	if len(test.Goroutine) > 0 {
+		lines = append(append(lines, ""), test.Goroutine...)
+	}
+	return Excerpt{Kind: ExcerptTimeout, Lines: lines}
+}
+
+// panicRunsThroughTest returns true if the goroutine stack of the given panic contains the function of the given test.
+// The functions of subtests are anonymous, so the function of their top level test (or a closure in it) is looked up
+// instead.
+func panicRunsThroughTest(panicLines []string, testName string) bool {
+	function := "." + strings.Split(testName, "/")[0]
+	for _, line := range panicLines {
+		if strings.Contains(line, function+"(") || strings.Contains(line, function+".func") {
+			return true
+		}
+	}
+	return false
+}
+
+// extractTail returns up to n lines before the `--- FAIL` line of the given test, skipping go test status lines. If
+// the log has no such line (e.g., because the test was interrupted), the last n lines before a timeout panic, or else
+// of the log, are returned.
+func extractTail(lines []string, testName string, n int) []string {
+	end := len(lines)
+	for i, line := range lines {
+		if (isResultLine(line) && getTestNameFromResultLine(line) == testName) || regexTimeoutPanic.MatchString(line) {
+			end = i
+			break
+		}
+	}
+
+	tail := []string{}
+	for i := end - 1; i >= 0 && len(tail) < n; i-- {
+		if isStatusLine(lines[i]) || isResultLine(lines[i]) {
+			continue
+		}
+		tail = append([]string{lines[i]}, tail...)
+	}
+	return trimTrailingBlankLines(tail)
+}
+
+// failureMessage returns a single line summary of the failure shown by the given excerpts.
+func failureMessage(excerpts []Excerpt) string {
+	for _, excerpt := range excerpts {
+		switch excerpt.Kind {
+		case ExcerptAssertion:
+			for _, line := range excerpt.Lines {
+				if match := regexAssertionError.FindStringSubmatch(line); match != nil {
+					return strings.TrimSpace(match[1])
+				}
+			}
+		case ExcerptTerraformError:
+			return strings.TrimSpace(strings.TrimPrefix(excerpt.Lines[0], "│"))
+		case ExcerptPanic:
+			return strings.TrimSpace(excerpt.Lines[0])
+		case ExcerptTail:
+			return strings.TrimSpace(stripTerratestLogPrefix(excerpt.Lines[len(excerpt.Lines)-1]))
+		case ExcerptTimeout:
+			return excerpt.Lines[0]
+		}
+	}
+	return "Failed"
+}
+
+// stripTerratestLogPrefix removes the test name, timestamp and location that terratest's logger prefixes lines with.
+func stripTerratestLogPrefix(line string) string {
+	return regexTerratestLogPrefix.ReplaceAllString(line, "")
+}
+
+// trimIndent removes the indent of the first line from all the given lines.
+func trimIndent(lines []string) []string {
+	indent := getIndent(lines[0])
+	trimmed := make([]string, len(lines))
+	for i, line := range lines {
+		trimmed[i] = strings.TrimPrefix(line, indent)
+	}
+	return trimmed
+}
+
+func trimTrailingBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// storeFailures writes the excerpts of the given failures as markdown to failures.md and as JSON to failures.json in
+// the output directory. Nothing is written if no test failed.
+func storeFailures(logger *logrus.Logger, outputDir string, failures []Failure) {
+	if len(failures) == 0 {
+		return
+	}
+
+	storeFile(logger, filepath.Join(outputDir, "failures.md"), func(writer io.Writer) error {
+		return writeFailuresMarkdown(writer, failures)
+	})
+	storeFile(logger, filepath.Join(outputDir, "failures.json"), func(writer io.Writer) error {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(failures)
+	})
+}
+
+// storeFile creates the file with the given name and writes its content with the given function.
+func storeFile(logger *logrus.Logger, filename string, write func(io.Writer) error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		logger.Errorf("Error making file %s: %s", filename, err)
+		return
+	}
+	defer f.Close()
+
+	if err := write(f); err != nil {
+		logger.Errorf("Error writing file %s: %s", filename, err)
+	}
+}
+
+func writeFailuresMarkdown(writer io.Writer, failures []Failure) error {
+	var out strings.Builder
+	out.WriteString("# Failures\n")
+	for _, failure := range failures {
+		fmt.Fprintf(&out, "\n## `%s`\n\n", failure.Test)
+		fmt.Fprintf(&out, "Package `%s`: %s\n", failure.Package, failure.Message)
+		for _, excerpt := range failure.Excerpts {
+			fmt.Fprintf(&out, "\n%s:\n\n````\n%s\n````\n", excerpt.title(), strings.Join(excerpt.Lines, "\n"))
+		}
+	}
+	_, err := io.WriteString(writer, out.String())
+	return err
+}
+
+// title returns the heading of the excerpt in the failures.md report, e.g. `Failed assertion`.
+func (excerpt Excerpt) title() string {
+	switch excerpt.Kind {
+	case ExcerptAssertion:
+		return "Failed assertion"
+	case ExcerptTerraformError:
+		return "Last terraform error"
+	case ExcerptPanic:
+		return "Panic"
+	case ExcerptTail:
+		if len(excerpt.Lines) == 1 {
+			return "Last line before the failure"
+		}
+		return fmt.Sprintf("Last %d lines before the failure", len(excerpt.Lines))
+	case ExcerptTimeout:
+		return "Timeout"
+	}
+	return string(excerpt.Kind)
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractFailureAssertion(t *testing.T) {
+	t.Parallel()
+
+	log := strings.Join([]string{
+		"=== RUN   TestApply",
+		"TestApply 2023-09-21T10:00:00Z logger.go:66: Running command terraform with args [output -json]",
+		"    apply_test.go:26: ",
+		"        \tError Trace:\tapply_test.go:26",
+		"        \tError:      \tNot equal: ",
+		"        \t            \texpected: \"hello\"",
+		"        \t            \tactual  : \"world\"",
+		"        \tTest:       \tTestApply",
+		"--- FAIL: TestApply (12.34s)",
+	}, "\n")
+
+	failure := extractFailure("TestApply", log, nil)
+	assert.Equal(t, "Not equal:", failure.Message)
+	assert.Equal(t, []Excerpt{{
+		Kind: ExcerptAssertion,
+		Lines: []string{
+			"apply_test.go:26: ",
+			"    \tError Trace:\tapply_test.go:26",
+			"    \tError:      \tNot equal: ",
+			"    \t            \texpected: \"hello\"",
+			"    \t            \tactual  : \"world\"",
+			"    \tTest:       \tTestApply",
+		},
+	}}, failure.Excerpts)
+}
+
+func TestExtractFailureFramedTerraformError(t *testing.T) {
+	t.Parallel()
+
+	log := strings.Join([]string{
+		"=== RUN   TestApply",
+		"TestApply 2023-09-21T10:00:00Z logger.go:66: ╷",
+		"TestApply 2023-09-21T10:00:00Z logger.go:66: │ Error: Unsupported argument",
+		"TestApply 2023-09-21T10:00:00Z logger.go:66: ╵",
+		"TestApply 2023-09-21T10:00:01Z logger.go:66: ╷",
+		"TestApply 2023-09-21T10:00:01Z logger.go:66: │ Error: Invalid reference",
+		"TestApply 2023-09-21T10:00:01Z logger.go:66: │ ",
+		"TestApply 2023-09-21T10:00:01Z logger.go:66: │   on main.tf line 3, in resource \"null_resource\" \"test\":",
+		"TestApply 2023-09-21T10:00:01Z logger.go:66: │    3:   triggers = foo",
+		"TestApply 2023-09-21T10:00:01Z logger.go:66: ╵",
+		"TestApply 2023-09-21T10:00:01Z retry.go:144: 'terraform [apply]' failed with the error 'exit status 1'",
+		"--- FAIL: TestApply (2.00s)",
+	}, "\n")
+
+	failure := extractFailure("TestApply", log, nil)
+	assert.Equal(t, "Error: Invalid reference", failure.Message)
+	assert.Equal(t, []Excerpt{{
+		Kind: ExcerptTerraformError,
+		Lines: []string{
+			"│ Error: Invalid reference",
+			"│ ",
+			"│   on main.tf line 3, in resource \"null_resource\" \"test\":",
+			"│    3:   triggers = foo",
+		},
+	}}, failure.Excerpts)
+}
+
+func TestExtractFailurePlainTerraformError(t *testing.T) {
+	t.Parallel()
+
+	log := strings.Join([]string{
+		"=== RUN   TestApply",
+		"TestApply 2023-09-21T10:00:00Z logger.go:66: Error: Invalid reference",
+		"TestApply 2023-09-21T10:00:00Z logger.go:66: ",
+		"TestApply 2023-09-21T10:00:00Z logger.go:66:   on main.tf line 3:",
+		"TestApply 2023-09-21T10:00:00Z logger.go:66: ",
+		"--- FAIL: TestApply (2.00s)",
+	}, "\n")
+
+	failure := extractFailure("TestApply", log, nil)
+	assert.Equal(t, []Excerpt{{
+		Kind:  ExcerptTerraformError,
+		Lines: []string{"Error: Invalid reference", "", "  on main.tf line 3:"},
+	}}, failure.Excerpts)
+}
+
+func TestExtractFailurePanicFromSummary(t *testing.T) {
+	t.Parallel()
+
+	panicLines := []string{
+		"panic: runtime error: invalid memory address or nil pointer dereference [recovered]",
+		"",
+		"goroutine 7 [running]:",
+		"github.com/gruntwork-io/terratest/test.TestApply.func1(0xc0000c5300)",
+		"\t/go/src/github.com/gruntwork-io/terratest/test/apply_test.go:30 +0x1c4",
+	}
+	summaryLog := strings.Join(append(append([]string{"--- FAIL: TestApply/Subtest (0.00s)"}, panicLines...), "exit status 2"), "\n")
+	panics := extractPanics(strings.Split(summaryLog, "\n"))
+	assert.Equal(t, [][]string{panicLines}, panics)
+
+	failure := extractFailure("TestApply/Subtest", "=== RUN   TestApply/Subtest", panics)
+	assert.Equal(t, "panic: runtime error: invalid memory address or nil pointer dereference [recovered]", failure.Message)
+	assert.Equal(t, []Excerpt{{Kind: ExcerptPanic, Lines: panicLines}}, failure.Excerpts)
+
+	failure = extractFailure("TestOther", "=== RUN   TestOther", panics)
+	assert.Equal(t, []Excerpt{}, failure.Excerpts)
+	assert.Equal(t, "Failed", failure.Message)
+}
+
+func TestExtractFailureTail(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"=== RUN   TestApply"}
+	for i := 0; i < FailureTailLines; i++ {
+		lines = append(lines, "TestApply 2023-09-21T10:00:00Z logger.go:66: waiting")
+	}
+	lines = append(lines,
+		"=== CONT  TestApply",
+		"    apply_test.go:26: instance never became healthy",
+		"--- FAIL: TestApply (600.00s)",
+		"TestApply 2023-09-21T10:00:00Z logger.go:66: after the failure",
+	)
+
+	failure := extractFailure("TestApply", strings.Join(lines, "\n"), nil)
+	assert.Equal(t, "apply_test.go:26: instance never became healthy", failure.Message)
+	assert.Len(t, failure.Excerpts, 1)
+	assert.Equal(t, ExcerptTail, failure.Excerpts[0].Kind)
+	assert.Len(t, failure.Excerpts[0].Lines, FailureTailLines)
+	assert.Equal(t, "    apply_test.go:26: instance never became healthy", failure.Excerpts[0].Lines[FailureTailLines-1])
+}
+
+func TestExtractFailuresOnlyFailedTests(t *testing.T) {
+	t.Parallel()
+
+	failures := ExtractFailures(exampleReport(), exampleLogs)
+	assert.Equal(t, []Failure{{
+		Package: "github.com/gruntwork-io/terratest/test",
+		Test:    "TestFailing",
+		Message: "example_test.go:26: expected <b>hello</b>",
+		Excerpts: []Excerpt{{
+			Kind:  ExcerptTail,
+			Lines: []string{"    example_test.go:26: expected <b>hello</b>"},
+		}},
+	}}, failures)
+}
@@ -0,0 +1,117 @@
+// Package logger/parser contains methods to parse and restructure log output from go testing and terratest
+package parser
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// DefaultFollowPollInterval is how often a followed log is checked for new data once its end is reached.
+const DefaultFollowPollInterval = 250 * time.Millisecond
+
+// followReader reads a log that is still being written to, like `tail -f`.
+type followReader struct {
+	ctx          context.Context
+	reader       io.Reader
+	pollInterval time.Duration
+	idleTimeout  time.Duration
+	lastData     time.Time
+}
+
+// NewFollowReader returns a reader that follows the given reader (e.g., a file that `go test` output is redirected to)
+// like `tail -f`: when it reaches the end, it waits for more data to be appended instead of returning io.EOF. It only
+// returns io.EOF once the given context is done, or once no data has been appended for the idle timeout, if that is
+// greater than 0. Pass the returned reader to SpawnParsers to break out the logs of the tests while they run.
+func NewFollowReader(ctx context.Context, reader io.Reader, pollInterval time.Duration, idleTimeout time.Duration) io.Reader {
+	if pollInterval <= 0 {
+		pollInterval = DefaultFollowPollInterval
+	}
+	return &followReader{
+		ctx:          ctx,
+		reader:       reader,
+		pollInterval: pollInterval,
+		idleTimeout:  idleTimeout,
+		lastData:     time.Now(),
+	}
+}
+
+func (follow *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := follow.reader.Read(p)
+		if n > 0 {
+			follow.lastData = time.Now()
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if follow.idleTimeout > 0 && time.Since(follow.lastData) >= follow.idleTimeout {
+			return 0, io.EOF
+		}
+
+		select {
+		case <-follow.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(follow.pollInterval):
+		}
+	}
+}
+
+// contextReader reads from a reader until the reader ends or a context is done, whichever comes first.
+type contextReader struct {
+	ctx     context.Context
+	chunks  chan []byte
+	err     error
+	pending []byte
+}
+
+// NewContextReader returns a reader that reads from the given reader (e.g., stdin) until it returns io.EOF, or until
+// the given context is done, even while a read of the given reader blocks. The given reader is read from a background
+// goroutine, which keeps blocking in its read after the context is done, so only use this for readers that live as
+// long as the process, such as stdin.
+func NewContextReader(ctx context.Context, reader io.Reader) io.Reader {
+	contextReader := &contextReader{ctx: ctx, chunks: make(chan []byte)}
+	go func() {
+		defer close(contextReader.chunks)
+		for {
+			buffer := make([]byte, 32*1024)
+			n, err := reader.Read(buffer)
+			if n > 0 {
+				select {
+				case contextReader.chunks <- buffer[:n]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if err != io.EOF {
+					contextReader.err = err
+				}
+				return
+			}
+		}
+	}()
+	return contextReader
+}
+
+func (reader *contextReader) Read(p []byte) (int, error) {
+	if len(reader.pending) == 0 {
+		select {
+		case chunk, ok := <-reader.chunks:
+			if !ok {
+				if reader.err != nil {
+					return 0, reader.err
+				}
+				return 0, io.EOF
+			}
+			reader.pending = chunk
+		case <-reader.ctx.Done():
+			return 0, io.EOF
+		}
+	}
+
+	n := copy(p, reader.pending)
+	reader.pending = reader.pending[n:]
+	return n, nil
+}
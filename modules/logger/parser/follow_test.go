@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowReaderReadsAppendedData(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "test.log")
+	writer, err := os.Create(filename)
+	require.NoError(t, err)
+	defer writer.Close()
+	file, err := os.Open(filename)
+	require.NoError(t, err)
+	defer file.Close()
+
+	go func() {
+		for _, line := range []string{"=== RUN   TestFoo\n", "--- PASS: TestFoo (0.00s)\n"} {
+			time.Sleep(50 * time.Millisecond)
+			writer.WriteString(line)
+		}
+	}()
+
+	data, err := io.ReadAll(NewFollowReader(context.Background(), file, 10*time.Millisecond, 500*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, "=== RUN   TestFoo\n--- PASS: TestFoo (0.00s)\n", string(data))
+}
+
+func TestFollowReaderStopsWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "test.log"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	data, err := io.ReadAll(NewFollowReader(ctx, file, 10*time.Millisecond, 0))
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestContextReaderStopsWhenContextIsDoneWhileReadBlocks(t *testing.T) {
+	t.Parallel()
+
+	pipeReader, pipeWriter := io.Pipe()
+	defer pipeWriter.Close()
+	go pipeWriter.Write([]byte("=== RUN   TestFoo\n"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	data, err := io.ReadAll(NewContextReader(ctx, pipeReader))
+	require.NoError(t, err)
+	assert.Equal(t, "=== RUN   TestFoo\n", string(data))
+}
+
+func TestContextReaderReadsUntilEOF(t *testing.T) {
+	t.Parallel()
+
+	data, err := io.ReadAll(NewContextReader(context.Background(), strings.NewReader("--- PASS: TestFoo (0.00s)\n")))
+	require.NoError(t, err)
+	assert.Equal(t, "--- PASS: TestFoo (0.00s)\n", string(data))
+}
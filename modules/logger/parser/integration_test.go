@@ -88,3 +88,18 @@ func TestIntegrationNewGoExample(t *testing.T) {
 	t.Parallel()
 	testExample(t, "new_go_failing")
 }
+
+func TestIntegrationJSONExample(t *testing.T) {
+	t.Parallel()
+	testExample(t, "json")
+}
+
+func TestIntegrationTimeoutExample(t *testing.T) {
+	t.Parallel()
+	testExample(t, "timeout")
+}
+
+func TestIntegrationJSONTimeoutExample(t *testing.T) {
+	t.Parallel()
+	testExample(t, "json_timeout")
+}
@@ -0,0 +1,278 @@
+// Package logger/parser contains methods to parse and restructure log output from go testing and terratest
+package parser
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	junitparser "github.com/jstemmer/go-junit-report/parser"
+	"github.com/sirupsen/logrus"
+)
+
+// TestEvent is a single event emitted by `go test -json`. See `go doc test2json` for the meaning of each field.
+type TestEvent struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64 // seconds
+	Output  string
+}
+
+// isJSONInput peeks at the given reader and returns true if its first non whitespace character opens a JSON object,
+// which is the case for the output of `go test -json`.
+func isJSONInput(reader *bufio.Reader) bool {
+	for size := 64; ; size *= 2 {
+		data, err := reader.Peek(size)
+		trimmed := strings.TrimLeft(string(data), " \t\r\n")
+		if trimmed != "" {
+			return strings.HasPrefix(trimmed, "{")
+		}
+		if err != nil {
+			return false
+		}
+	}
+}
+
+// jsonTestOutputParser breaks out the events of `go test -json` by test. Unlike the plain text output of `go test -v`,
+// every event carries the name of the test it belongs to, so the output of parallel tests is attributed exactly.
+type jsonTestOutputParser struct {
+	logger    *logrus.Logger
+	logWriter LogWriter
+
+	// partialOutput holds output, keyed by test name, that was not terminated by a newline yet.
+	partialOutput map[string]string
+	// panicking is true once a panic was seen, until the package finishes. All output of the package is then rolled
+	// up into the summary, as is done for plain text output.
+	panicking bool
+
+	report       *junitparser.Report
+	packages     map[string]*junitparser.Package
+	packageNames []string // in the order the packages started
+	tests        map[string]*junitparser.Test
+}
+
+func newJSONTestOutputParser(logger *logrus.Logger, outputDir string) *jsonTestOutputParser {
+	return &jsonTestOutputParser{
+		logger: logger,
+		logWriter: LogWriter{
+			lookup:    make(map[string]*os.File),
+			outputDir: outputDir,
+		},
+		partialOutput: map[string]string{},
+		report:        &junitparser.Report{Packages: []junitparser.Package{}},
+		packages:      map[string]*junitparser.Package{},
+		tests:         map[string]*junitparser.Test{},
+	}
+}
+
+// parseAndStoreJSONTestOutput reads the events of `go test -json` from the given reader, and stores the output of each
+// test into a file under the outputDir named by test name and the result lines into `summary.log`, just like
+// parseAndStoreTestOutput does for plain text output. It returns the results of the tests as a junit report.
+func parseAndStoreJSONTestOutput(logger *logrus.Logger, read io.Reader, outputDir string) *junitparser.Report {
+	parser := newJSONTestOutputParser(logger, outputDir)
+	defer parser.logWriter.closeFiles(logger)
+
+	scanner := bufio.NewScanner(read)
+	// Lines of output can be arbitrarily long (e.g., a terraform plan in a single log entry)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		parser.handleLine(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Fatalf("Error reading from Reader: %s", err)
+	}
+
+	parser.flush()
+	return parser.junitReport()
+}
+
+// handleLine parses a single line of `go test -json` output and handles the event in it. Lines that are not JSON
+// (e.g., output of a build that failed before the tests ran) are rolled up into the summary.
+func (parser *jsonTestOutputParser) handleLine(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	var event TestEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		parser.logger.Warnf("Found line that is not a go test -json event: %s", line)
+		parser.logWriter.writeLog(parser.logger, "summary", line)
+		return
+	}
+	parser.handleEvent(event)
+}
+
+// handleEvent handles a single `go test -json` event.
+func (parser *jsonTestOutputParser) handleEvent(event TestEvent) {
+	switch event.Action {
+	case "output":
+		parser.handleOutput(event)
+
+	case "run":
+		parser.getOrCreateTest(event)
+
+	case "pass", "fail", "skip":
+		if event.Test == "" {
+			parser.finishPackage(event)
+			return
+		}
+		test := parser.getOrCreateTest(event)
+		test.Result = toJunitResult(event.Action)
+		test.Duration = toDuration(event.Elapsed)
+		test.Time = int(test.Duration / time.Millisecond)
+	}
+}
+
+// handleOutput splits the output of the given event into lines and handles each complete line.
+func (parser *jsonTestOutputParser) handleOutput(event TestEvent) {
+	key := testKey(event.Package, event.Test)
+	output := parser.partialOutput[key] + event.Output
+	lines := strings.Split(output, "\n")
+	parser.partialOutput[key] = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		parser.handleOutputLine(event.Package, event.Test, line)
+	}
+}
+
+// flush handles all output that was not terminated by a newline.
+func (parser *jsonTestOutputParser) flush() {
+	for key, partial := range parser.partialOutput {
+		if partial != "" {
+			packageName, testName, _ := strings.Cut(key, " ")
+			parser.handleOutputLine(packageName, testName, partial)
+		}
+	}
+	parser.partialOutput = map[string]string{}
+}
+
+// handleOutputLine writes a single line of output to the log of the test it belongs to, and the result and summary
+// lines to the summary log.
+func (parser *jsonTestOutputParser) handleOutputLine(packageName string, testName string, line string) {
+	line = strings.TrimSuffix(line, "\r")
+
+	if isPanicLine(line) {
+		warnOnTimeout(parser.logger, line)
+		parser.panicking = true
+	}
+
+	if testName == "" {
+		// Output that does not belong to a test: the package result, coverage or a panic outside of a test.
+		if isSummaryLine(line) || parser.panicking {
+			parser.logWriter.writeLog(parser.logger, "summary", line)
+		} else {
+			parser.logger.Debugf("Ignoring package level output: %s", line)
+		}
+		return
+	}
+
+	parser.logWriter.writeLog(parser.logger, testName, line)
+
+	switch {
+	case isResultLine(line):
+		parser.logWriter.writeLog(parser.logger, "summary", line)
+		// As for plain text output, roll up the results of subtests into the logs of their parents
+		for _, parent := range parentTestNames(testName) {
+			parser.logWriter.writeLog(parser.logger, parent, line)
+		}
+	case parser.panicking:
+		parser.logWriter.writeLog(parser.logger, "summary", line)
+	case !isStatusLine(line):
+		if test, hasTest := parser.tests[testKey(packageName, testName)]; hasTest && isIndentedTestOutput(line) {
+			test.Output = append(test.Output, strings.TrimLeft(line, " \t"))
+		}
+	}
+}
+
+// isIndentedTestOutput returns true for output written by the testing package on behalf of a test (e.g., by t.Log or a
+// failed testify assertion), which go test indents, as opposed to output written directly to stdout by terratest.
+func isIndentedTestOutput(line string) bool {
+	return len(getIndent(line)) > 0
+}
+
+// parentTestNames returns the names of all the parents of the given (sub)test, starting with the top level test.
+// Example:
+//
+//	in:  TestSnafu/Situation/Normal
+//	out: [TestSnafu TestSnafu/Situation]
+func parentTestNames(testName string) []string {
+	parts := strings.Split(testName, "/")
+	parents := []string{}
+	for i := 1; i < len(parts); i++ {
+		parents = append(parents, strings.Join(parts[:i], "/"))
+	}
+	return parents
+}
+
+// getOrCreatePackage returns the junit package with the given name, creating it if it does not exist yet.
+func (parser *jsonTestOutputParser) getOrCreatePackage(name string) *junitparser.Package {
+	pkg, hasPackage := parser.packages[name]
+	if !hasPackage {
+		pkg = &junitparser.Package{Name: name, Tests: []*junitparser.Test{}}
+		parser.packages[name] = pkg
+		parser.packageNames = append(parser.packageNames, name)
+	}
+	return pkg
+}
+
+// getOrCreateTest returns the junit test for the test the given event belongs to, creating it if it does not exist yet.
+func (parser *jsonTestOutputParser) getOrCreateTest(event TestEvent) *junitparser.Test {
+	key := testKey(event.Package, event.Test)
+	test, hasTest := parser.tests[key]
+	if !hasTest {
+		test = &junitparser.Test{Name: event.Test, Output: []string{}}
+		parser.tests[key] = test
+		pkg := parser.getOrCreatePackage(event.Package)
+		pkg.Tests = append(pkg.Tests, test)
+	}
+	return test
+}
+
+// testKey identifies a test across packages, as several packages may contain tests with the same name.
+func testKey(packageName string, testName string) string {
+	return packageName + " " + testName
+}
+
+// finishPackage records the result of a package in the report.
+func (parser *jsonTestOutputParser) finishPackage(event TestEvent) {
+	pkg := parser.getOrCreatePackage(event.Package)
+	pkg.Duration = toDuration(event.Elapsed)
+	pkg.Time = int(pkg.Duration / time.Millisecond)
+	parser.report.Packages = append(parser.report.Packages, *pkg)
+	parser.panicking = false
+}
+
+// junitReport returns the junit report of all the packages seen so far. Packages that did not finish (e.g., because
+// the output was truncated) are included as well.
+func (parser *jsonTestOutputParser) junitReport() *junitparser.Report {
+	report := &junitparser.Report{Packages: append([]junitparser.Package{}, parser.report.Packages...)}
+	finished := map[string]bool{}
+	for _, pkg := range report.Packages {
+		finished[pkg.Name] = true
+	}
+	for _, name := range parser.packageNames {
+		if !finished[name] {
+			report.Packages = append(report.Packages, *parser.packages[name])
+		}
+	}
+	return report
+}
+
+func toJunitResult(action string) junitparser.Result {
+	switch action {
+	case "fail":
+		return junitparser.FAIL
+	case "skip":
+		return junitparser.SKIP
+	default:
+		return junitparser.PASS
+	}
+}
+
+func toDuration(elapsedSeconds float64) time.Duration {
+	return time.Duration(elapsedSeconds * float64(time.Second))
+}
@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsJSONInput(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		in   string
+		out  bool
+	}{
+		{
+			"JSON",
+			`{"Action":"start","Package":"github.com/gruntwork-io/terratest/test"}`,
+			true,
+		},
+		{
+			"LeadingWhitespace",
+			"\n\n   " + `{"Action":"start"}`,
+			true,
+		},
+		{
+			"LeadingWhitespaceLongerThanPeek",
+			strings.Repeat("\n", 1000) + `{"Action":"start"}`,
+			true,
+		},
+		{
+			"PlainText",
+			"=== RUN   TestSnafu",
+			false,
+		},
+		{
+			"EmptyString",
+			"",
+			false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(
+				t,
+				isJSONInput(bufio.NewReader(strings.NewReader(testCase.in))),
+				testCase.out,
+			)
+		})
+	}
+}
+
+func TestParentTestNames(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		in   string
+		out  []string
+	}{
+		{
+			"TopLevel",
+			"TestSnafu",
+			[]string{},
+		},
+		{
+			"Subtest",
+			"TestSnafu/Situation",
+			[]string{"TestSnafu"},
+		},
+		{
+			"NestedSubtest",
+			"TestSnafu/Situation/Normal",
+			[]string{"TestSnafu", "TestSnafu/Situation"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(
+				t,
+				parentTestNames(testCase.in),
+				testCase.out,
+			)
+		})
+	}
+}
@@ -13,10 +13,21 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// SpawnParsers will spawn the log parser and junit report parsers off of a single reader.
-func SpawnParsers(logger *logrus.Logger, reader io.Reader, outputDir string) {
+// SpawnParsers will spawn the log parser and junit report parsers off of a single reader. The reader may either contain
+// the plain text output of `go test -v` or the events of `go test -json`, which is detected automatically. Once the
+// logs are broken out by test, a report of the test results is written in each of the given formats (see
+// ReportFormats), or in the DefaultFormats if none are given.
+func SpawnParsers(logger *logrus.Logger, read io.Reader, outputDir string, formats ...string) {
+	reader := bufio.NewReader(read)
+	if isJSONInput(reader) {
+		report := parseAndStoreJSONTestOutput(logger, reader, outputDir)
+		storeReports(logger, outputDir, report, formats)
+		return
+	}
+
 	forkedReader, forkedWriter := io.Pipe()
 	teedReader := io.TeeReader(reader, forkedWriter)
+	var report *junitparser.Report
 	var waitForParsers sync.WaitGroup
 	waitForParsers.Add(2)
 	go func() {
@@ -27,20 +38,25 @@ func SpawnParsers(logger *logrus.Logger, reader io.Reader, outputDir string) {
 	}()
 	go func() {
 		defer waitForParsers.Done()
-		report, err := junitparser.Parse(forkedReader, "")
-		if err == nil {
-			storeJunitReport(logger, outputDir, report)
-		} else {
+		var err error
+		report, err = junitparser.Parse(forkedReader, "")
+		if err != nil {
 			logger.Errorf("Error parsing test output into junit report: %s", err)
+			report = nil
 		}
 	}()
 	waitForParsers.Wait()
+
+	// The reports are written only after the logs are broken out, as some of them include the logs
+	if report != nil {
+		storeReports(logger, outputDir, report, formats)
+	}
 }
 
 // RegEx for parsing test status lines. Pulled from jstemmer/go-junit-report
 var (
 	regexResult  = regexp.MustCompile(`--- (PASS|FAIL|SKIP): (.+) \((\d+\.\d+)(?: ?seconds|s)\)`)
-	regexStatus  = regexp.MustCompile(`=== (RUN|PAUSE|CONT)\s+(.+)`)
+	regexStatus  = regexp.MustCompile(`=== (RUN|PAUSE|CONT|NAME)\s+(.+)`)
 	regexSummary = regexp.MustCompile(`(^FAIL$)|(^(ok|FAIL)\s+([^ ]+)\s+(?:(\d+\.\d+)s|\(cached\)|(\[\w+ failed]))(?:\s+coverage:\s+(\d+\.\d+)%\sof\sstatements(?:\sin\s.+)?)?$)`)
 	regexPanic   = regexp.MustCompile(`^panic:`)
 )
@@ -96,6 +112,14 @@ func isPanicLine(text string) bool {
 	return regexPanic.MatchString(text)
 }
 
+// warnOnTimeout warns right away if the given panic line is the timeout of go test, as the tests that are in progress
+// are only reported once the whole log is parsed, which may take a while when following it.
+func warnOnTimeout(logger *logrus.Logger, panicLine string) {
+	if regexTimeoutPanic.MatchString(panicLine) {
+		logger.Warnf("go test timed out: %s", panicLine)
+	}
+}
+
 // parseAndStoreTestOutput will take test log entries from terratest and aggregate the output by test. Takes advantage
 // of the fact that terratest logs are prefixed by the test name. This will store the broken out logs into files under
 // the outputDir, named by test name.
@@ -164,6 +188,7 @@ func parseAndStoreTestOutput(
 
 			case isPanicLine(data):
 				// When panic, we want all subsequent nonstandard test lines to roll up to the summary
+				warnOnTimeout(logger, data)
 				previousTestName = "summary"
 				logWriter.writeLog(logger, "summary", data)
 
@@ -175,6 +175,11 @@ func TestGetTestNameFromStatusLine(t *testing.T) {
 			"=== CONT  TestGetTestNameFromStatusLine",
 			"TestGetTestNameFromStatusLine",
 		},
+		{
+			"WhenName",
+			"=== NAME  TestGetTestNameFromStatusLine",
+			"TestGetTestNameFromStatusLine",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -222,6 +227,11 @@ func TestIsStatusLine(t *testing.T) {
 			"=== CONT  TestGetTestNameFromStatusLine",
 			true,
 		},
+		{
+			"WhenName",
+			"=== NAME  TestGetTestNameFromStatusLine",
+			true,
+		},
 		{
 			"NonStatusLine",
 			"--- FAIL: TestIsStatusLine",
@@ -0,0 +1,269 @@
+// Package logger/parser contains methods to parse and restructure log output from go testing and terratest
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	junitformatter "github.com/jstemmer/go-junit-report/formatter"
+	junitparser "github.com/jstemmer/go-junit-report/parser"
+	"github.com/sirupsen/logrus"
+)
+
+// Names of the report formats that ship with the parser.
+const (
+	FormatJUnit    = "junit"
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+	FormatCTRF     = "ctrf"
+)
+
+// DefaultFormats are the report formats written when none are requested explicitly.
+var DefaultFormats = []string{FormatJUnit}
+
+// ReportWriter writes a report of the results of a test run in a specific format. Implement it and register it with
+// RegisterReportWriter to add a new report format.
+type ReportWriter interface {
+	// FileName returns the name of the file, relative to the output directory, that the report is written to.
+	FileName() string
+
+	// Write writes the report of the given test results. The logs give access to the broken out log of each test.
+	Write(writer io.Writer, report *junitparser.Report, logs TestLogs) error
+}
+
+// TestLogs gives access to the broken out logs of the tests in a report.
+type TestLogs interface {
+	// Log returns the log of the test with the given name.
+	Log(testName string) (string, error)
+}
+
+var (
+	reportWriters = map[string]ReportWriter{
+		FormatJUnit:    JUnitReportWriter{},
+		FormatHTML:     HTMLReportWriter{},
+		FormatMarkdown: MarkdownReportWriter{},
+		FormatCTRF:     CTRFReportWriter{},
+	}
+	reportWritersMutex sync.RWMutex
+)
+
+// RegisterReportWriter makes the given report writer available under the given format name, replacing any writer that
+// was registered under that name before.
+func RegisterReportWriter(format string, writer ReportWriter) {
+	reportWritersMutex.Lock()
+	defer reportWritersMutex.Unlock()
+
+	reportWriters[format] = writer
+}
+
+// GetReportWriter returns the report writer registered under the given format name.
+func GetReportWriter(format string) (ReportWriter, error) {
+	reportWritersMutex.RLock()
+	defer reportWritersMutex.RUnlock()
+
+	writer, hasWriter := reportWriters[format]
+	if !hasWriter {
+		return nil, UnknownReportFormat{Format: format}
+	}
+	return writer, nil
+}
+
+// ReportFormats returns the sorted names of all the registered report formats.
+func ReportFormats() []string {
+	reportWritersMutex.RLock()
+	defer reportWritersMutex.RUnlock()
+
+	formats := []string{}
+	for format := range reportWriters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// UnknownReportFormat is an error that occurs when a report format is requested that was not registered.
+type UnknownReportFormat struct {
+	Format string
+}
+
+func (err UnknownReportFormat) Error() string {
+	return fmt.Sprintf("Unknown report format %q. Must be one of: %s", err.Format, strings.Join(ReportFormats(), ", "))
+}
+
+// dirTestLogs reads the logs of the tests from the output directory the parser broke them out to.
+type dirTestLogs struct {
+	outputDir string
+}
+
+func (logs dirTestLogs) Log(testName string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(logs.outputDir, testName+".log"))
+	return string(data), err
+}
+
+// storeReports writes the given report in each of the given formats to the output directory, along with the excerpts
+// of the logs of the failed tests and the tests that were in progress when go test timed out.
+func storeReports(logger *logrus.Logger, outputDir string, report *junitparser.Report, formats []string) {
+	if len(formats) == 0 {
+		formats = DefaultFormats
+	}
+
+	ensureDirectoryExists(logger, outputDir)
+	logs := dirTestLogs{outputDir: outputDir}
+	timeouts := DetectTimeouts(report, logs)
+	markTimedOutTests(report, timeouts)
+	storeTimeouts(logger, outputDir, timeouts)
+	failures := extractFailures(report, logs, timeouts)
+	for _, format := range formats {
+		writer, err := GetReportWriter(format)
+		if err != nil {
+			logger.Errorf("Error writing report: %s", err)
+			continue
+		}
+		storeReport(logger, outputDir, report, testLogsWithFailures{TestLogs: logs, failures: failures}, format, writer)
+	}
+	storeFailures(logger, outputDir, failures)
+}
+
+// testLogsWithFailures are test logs along with the failures already extracted from them, so that the report writers
+// that need the failures do not extract them again.
+type testLogsWithFailures struct {
+	TestLogs
+	failures []Failure
+}
+
+// failuresOf returns the failures of the given report, as already extracted from the given logs if they are
+// testLogsWithFailures.
+func failuresOf(report *junitparser.Report, logs TestLogs) []Failure {
+	if withFailures, ok := logs.(testLogsWithFailures); ok {
+		return withFailures.failures
+	}
+	return ExtractFailures(report, logs)
+}
+
+// storeReport writes the given report with the given writer to the output directory.
+func storeReport(logger *logrus.Logger, outputDir string, report *junitparser.Report, logs TestLogs, format string, writer ReportWriter) {
+	filename := filepath.Join(outputDir, writer.FileName())
+	f, err := os.Create(filename)
+	if err != nil {
+		logger.Errorf("Error making file %s for %s report", filename, format)
+		return
+	}
+	defer f.Close()
+
+	if err := writer.Write(f, report, logs); err != nil {
+		logger.Errorf("Error formatting %s report: %s", format, err)
+	}
+}
+
+// JUnitReportWriter writes the test results as junit XML to report.xml, to be consumed by a CI engine. The message of
+// the failure of each failed test is a single line summary of what went wrong, and its body holds the excerpts of its
+// log that show it (see ExtractFailures).
+type JUnitReportWriter struct{}
+
+func (JUnitReportWriter) FileName() string {
+	return "report.xml"
+}
+
+func (JUnitReportWriter) Write(writer io.Writer, report *junitparser.Report, logs TestLogs) error {
+	var junitXML bytes.Buffer
+	if err := junitformatter.JUnitReportXML(report, false, "", &junitXML); err != nil {
+		return err
+	}
+
+	failures := failuresOf(report, logs)
+	if len(failures) == 0 {
+		_, err := junitXML.WriteTo(writer)
+		return err
+	}
+
+	// The formatter has no way to set the failure message, so patch it into the XML it produced
+	suites := junitformatter.JUnitTestSuites{}
+	if err := xml.Unmarshal(junitXML.Bytes(), &suites); err != nil {
+		return err
+	}
+	failuresByTest := map[string]Failure{}
+	for _, failure := range failures {
+		failuresByTest[testKey(failure.Package, failure.Test)] = failure
+	}
+	for _, suite := range suites.Suites {
+		for _, testCase := range suite.TestCases {
+			failure, hasFailure := failuresByTest[testKey(suite.Name, testCase.Name)]
+			if testCase.Failure == nil || !hasFailure {
+				continue
+			}
+			testCase.Failure.Message = failure.Message
+			if excerpts := failure.String(); excerpts != "" {
+				testCase.Failure.Contents = excerpts
+			}
+		}
+	}
+
+	patchedXML, err := xml.MarshalIndent(suites, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(writer, "%s%s\n", xml.Header, patchedXML)
+	return err
+}
+
+// reportSummary aggregates the results of all the tests in a report.
+type reportSummary struct {
+	Tests    int
+	Passed   int
+	Failed   int
+	Skipped  int
+	Duration time.Duration
+}
+
+func summarize(report *junitparser.Report) reportSummary {
+	summary := reportSummary{}
+	for _, pkg := range report.Packages {
+		summary.Duration += pkg.Duration
+		for _, test := range pkg.Tests {
+			summary.Tests++
+			switch test.Result {
+			case junitparser.PASS:
+				summary.Passed++
+			case junitparser.FAIL:
+				summary.Failed++
+			case junitparser.SKIP:
+				summary.Skipped++
+			}
+		}
+	}
+	return summary
+}
+
+// resultName returns the human readable name of the given test result.
+func resultName(result junitparser.Result) string {
+	switch result {
+	case junitparser.FAIL:
+		return "FAIL"
+	case junitparser.SKIP:
+		return "SKIP"
+	default:
+		return "PASS"
+	}
+}
+
+// formatDuration formats the given duration in seconds, the way go test does in its result lines.
+func formatDuration(duration time.Duration) string {
+	return fmt.Sprintf("%.2fs", duration.Seconds())
+}
+
+// lastLines returns the last n lines of the given text.
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
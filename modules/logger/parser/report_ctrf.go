@@ -0,0 +1,101 @@
+// Package logger/parser contains methods to parse and restructure log output from go testing and terratest
+package parser
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	junitparser "github.com/jstemmer/go-junit-report/parser"
+)
+
+// CTRFReportWriter writes the test results as JSON in the Common Test Report Format (see https://ctrf.io) to
+// report.ctrf.json, to be consumed by dashboards.
+type CTRFReportWriter struct{}
+
+func (CTRFReportWriter) FileName() string {
+	return "report.ctrf.json"
+}
+
+func (CTRFReportWriter) Write(writer io.Writer, report *junitparser.Report, logs TestLogs) error {
+	summary := summarize(report)
+	// The output of go test does not contain the time the tests ran, so assume they just finished
+	stop := time.Now()
+	start := stop.Add(-summary.Duration)
+
+	results := ctrfResults{
+		Tool: ctrfTool{Name: "terratest"},
+		Summary: ctrfSummary{
+			Tests:   summary.Tests,
+			Passed:  summary.Passed,
+			Failed:  summary.Failed,
+			Skipped: summary.Skipped,
+			Start:   start.UnixMilli(),
+			Stop:    stop.UnixMilli(),
+		},
+		Tests: []ctrfTest{},
+	}
+	for _, pkg := range report.Packages {
+		for _, test := range pkg.Tests {
+			ctrf := ctrfTest{
+				Name:     test.Name,
+				Status:   ctrfStatus(test.Result),
+				Duration: test.Duration.Milliseconds(),
+				Suite:    pkg.Name,
+			}
+			if test.Result != junitparser.PASS {
+				ctrf.Message = strings.Join(test.Output, "\n")
+			}
+			results.Tests = append(results.Tests, ctrf)
+		}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(ctrfReport{Results: results})
+}
+
+func ctrfStatus(result junitparser.Result) string {
+	switch result {
+	case junitparser.FAIL:
+		return "failed"
+	case junitparser.SKIP:
+		return "skipped"
+	default:
+		return "passed"
+	}
+}
+
+type ctrfReport struct {
+	Results ctrfResults `json:"results"`
+}
+
+type ctrfResults struct {
+	Tool    ctrfTool    `json:"tool"`
+	Summary ctrfSummary `json:"summary"`
+	Tests   []ctrfTest  `json:"tests"`
+}
+
+type ctrfTool struct {
+	Name string `json:"name"`
+}
+
+type ctrfSummary struct {
+	Tests   int   `json:"tests"`
+	Passed  int   `json:"passed"`
+	Failed  int   `json:"failed"`
+	Pending int   `json:"pending"`
+	Skipped int   `json:"skipped"`
+	Other   int   `json:"other"`
+	Start   int64 `json:"start"`
+	Stop    int64 `json:"stop"`
+}
+
+type ctrfTest struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Duration int64  `json:"duration"` // milliseconds
+	Suite    string `json:"suite,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
@@ -0,0 +1,96 @@
+// Package logger/parser contains methods to parse and restructure log output from go testing and terratest
+package parser
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	junitparser "github.com/jstemmer/go-junit-report/parser"
+)
+
+// HTMLReportWriter writes the test results as a self contained HTML page to report.html, with the log of each test in
+// a collapsible section. The sections of failed tests are expanded and highlighted.
+type HTMLReportWriter struct{}
+
+func (HTMLReportWriter) FileName() string {
+	return "report.html"
+}
+
+func (HTMLReportWriter) Write(writer io.Writer, report *junitparser.Report, logs TestLogs) error {
+	page := htmlReport{Summary: summarize(report)}
+	for _, pkg := range report.Packages {
+		htmlPkg := htmlPackage{Name: pkg.Name, Duration: formatDuration(pkg.Duration)}
+		for _, test := range pkg.Tests {
+			log, err := logs.Log(test.Name)
+			if err != nil {
+				// Not every test has a log of its own, e.g. when the output was truncated
+				log = strings.Join(test.Output, "\n")
+			}
+			htmlPkg.Tests = append(htmlPkg.Tests, htmlTest{
+				Name:     test.Name,
+				Result:   resultName(test.Result),
+				Duration: formatDuration(test.Duration),
+				Log:      log,
+			})
+		}
+		page.Packages = append(page.Packages, htmlPkg)
+	}
+	return htmlReportTemplate.Execute(writer, page)
+}
+
+type htmlReport struct {
+	Summary  reportSummary
+	Packages []htmlPackage
+}
+
+type htmlPackage struct {
+	Name     string
+	Duration string
+	Tests    []htmlTest
+}
+
+type htmlTest struct {
+	Name     string
+	Result   string
+	Duration string
+	Log      string
+}
+
+var htmlReportTemplate = template.Must(template.New("report.html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Test report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table.summary td, table.summary th { padding: 0.2em 1em; text-align: left; }
+details { border: 1px solid #ccc; border-radius: 4px; margin: 0.3em 0; padding: 0.3em 0.6em; }
+details.FAIL { border-color: #d73a49; background: #ffeef0; }
+details.SKIP { color: #6a737d; }
+summary { cursor: pointer; }
+.result { display: inline-block; width: 3em; font-weight: bold; }
+.PASS .result { color: #22863a; }
+.FAIL .result { color: #d73a49; }
+.duration { color: #6a737d; }
+pre { background: #f6f8fa; overflow-x: auto; padding: 0.5em; }
+</style>
+</head>
+<body>
+<h1>Test report</h1>
+<table class="summary">
+<tr><th>Tests</th><th>Passed</th><th>Failed</th><th>Skipped</th><th>Duration</th></tr>
+<tr><td>{{.Summary.Tests}}</td><td>{{.Summary.Passed}}</td><td>{{.Summary.Failed}}</td><td>{{.Summary.Skipped}}</td><td>{{printf "%.2fs" .Summary.Duration.Seconds}}</td></tr>
+</table>
+{{- range .Packages}}
+<h2>{{.Name}} <span class="duration">({{.Duration}})</span></h2>
+{{- range .Tests}}
+<details class="{{.Result}}"{{if eq .Result "FAIL"}} open{{end}}>
+<summary><span class="result">{{.Result}}</span> {{.Name}} <span class="duration">({{.Duration}})</span></summary>
+<pre>{{.Log}}</pre>
+</details>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
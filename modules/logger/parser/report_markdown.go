@@ -0,0 +1,63 @@
+// Package logger/parser contains methods to parse and restructure log output from go testing and terratest
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	junitparser "github.com/jstemmer/go-junit-report/parser"
+)
+
+// MarkdownLogLines is the number of lines at the end of the log of a failed test that the markdown report includes.
+const MarkdownLogLines = 50
+
+// MarkdownReportWriter writes a summary of the test results as GitHub flavored markdown to report.md, suitable for
+// posting as a pull request comment or as a GitHub Actions job summary (by appending it to $GITHUB_STEP_SUMMARY). It
+// lists the results of all tests, and the end of the log of each failed test in a collapsible section.
+type MarkdownReportWriter struct{}
+
+func (MarkdownReportWriter) FileName() string {
+	return "report.md"
+}
+
+func (MarkdownReportWriter) Write(writer io.Writer, report *junitparser.Report, logs TestLogs) error {
+	summary := summarize(report)
+	status := "✅"
+	if summary.Failed > 0 {
+		status = "❌"
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "## %s Test results\n\n", status)
+	out.WriteString("| Tests | Passed | Failed | Skipped | Duration |\n")
+	out.WriteString("| ---: | ---: | ---: | ---: | ---: |\n")
+	fmt.Fprintf(&out, "| %d | %d | %d | %d | %s |\n", summary.Tests, summary.Passed, summary.Failed, summary.Skipped, formatDuration(summary.Duration))
+
+	failed := []*junitparser.Test{}
+	out.WriteString("\n| Result | Test | Package | Duration |\n")
+	out.WriteString("| --- | --- | --- | ---: |\n")
+	for _, pkg := range report.Packages {
+		for _, test := range pkg.Tests {
+			fmt.Fprintf(&out, "| %s | `%s` | `%s` | %s |\n", resultName(test.Result), test.Name, pkg.Name, formatDuration(test.Duration))
+			if test.Result == junitparser.FAIL {
+				failed = append(failed, test)
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		out.WriteString("\n### Failed tests\n")
+		for _, test := range failed {
+			log, err := logs.Log(test.Name)
+			if err != nil {
+				log = strings.Join(test.Output, "\n")
+			}
+			fmt.Fprintf(&out, "\n<details>\n<summary><code>%s</code> (%s)</summary>\n\n", test.Name, formatDuration(test.Duration))
+			fmt.Fprintf(&out, "````\n%s\n````\n\n</details>\n", lastLines(log, MarkdownLogLines))
+		}
+	}
+
+	_, err := io.WriteString(writer, out.String())
+	return err
+}
@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	junitparser "github.com/jstemmer/go-junit-report/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTestLogs map[string]string
+
+func (logs fakeTestLogs) Log(testName string) (string, error) {
+	log, hasLog := logs[testName]
+	if !hasLog {
+		return "", errors.New("no log for " + testName)
+	}
+	return log, nil
+}
+
+func exampleReport() *junitparser.Report {
+	return &junitparser.Report{
+		Packages: []junitparser.Package{
+			{
+				Name:     "github.com/gruntwork-io/terratest/test",
+				Duration: 3 * time.Second,
+				Tests: []*junitparser.Test{
+					{Name: "TestPassing", Result: junitparser.PASS, Duration: 1500 * time.Millisecond},
+					{Name: "TestFailing", Result: junitparser.FAIL, Duration: 1200 * time.Millisecond, Output: []string{"example_test.go:26: expected <b>hello</b>"}},
+					{Name: "TestSkipped", Result: junitparser.SKIP, Output: []string{"requires credentials"}},
+				},
+			},
+		},
+	}
+}
+
+var exampleLogs = fakeTestLogs{
+	"TestPassing": "=== RUN   TestPassing\n--- PASS: TestPassing (1.50s)\n",
+	"TestFailing": "=== RUN   TestFailing\n    example_test.go:26: expected <b>hello</b>\n--- FAIL: TestFailing (1.20s)\n",
+}
+
+func TestHTMLReportWriter(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	require.NoError(t, HTMLReportWriter{}.Write(&out, exampleReport(), exampleLogs))
+	html := out.String()
+
+	assert.Contains(t, html, "<td>3</td><td>1</td><td>1</td><td>1</td><td>3.00s</td>")
+	assert.Contains(t, html, `<details class="FAIL" open>`)
+	assert.Contains(t, html, `<details class="PASS">`)
+	assert.Contains(t, html, "TestFailing <span class=\"duration\">(1.20s)</span>")
+	// Logs are escaped
+	assert.Contains(t, html, "expected &lt;b&gt;hello&lt;/b&gt;")
+	assert.NotContains(t, html, "<b>hello</b>")
+	// Tests without a log of their own fall back to their output
+	assert.Contains(t, html, "<pre>requires credentials</pre>")
+}
+
+func TestMarkdownReportWriter(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	require.NoError(t, MarkdownReportWriter{}.Write(&out, exampleReport(), exampleLogs))
+	markdown := out.String()
+
+	assert.True(t, strings.HasPrefix(markdown, "## ❌ Test results\n"))
+	assert.Contains(t, markdown, "| 3 | 1 | 1 | 1 | 3.00s |")
+	assert.Contains(t, markdown, "| FAIL | `TestFailing` | `github.com/gruntwork-io/terratest/test` | 1.20s |")
+	assert.Contains(t, markdown, "<summary><code>TestFailing</code> (1.20s)</summary>")
+	assert.Contains(t, markdown, "--- FAIL: TestFailing (1.20s)\n````")
+	assert.NotContains(t, markdown, "<summary><code>TestPassing</code>")
+}
+
+func TestMarkdownReportWriterTruncatesLogs(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{}
+	for i := 0; i < 2*MarkdownLogLines; i++ {
+		lines = append(lines, "line")
+	}
+	lines = append(lines, "--- FAIL: TestFailing (1.20s)")
+	logs := fakeTestLogs{"TestFailing": strings.Join(lines, "\n")}
+
+	var out bytes.Buffer
+	require.NoError(t, MarkdownReportWriter{}.Write(&out, exampleReport(), logs))
+	assert.Equal(t, MarkdownLogLines-1, strings.Count(out.String(), "line\n"))
+}
+
+func TestCTRFReportWriter(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	require.NoError(t, CTRFReportWriter{}.Write(&out, exampleReport(), exampleLogs))
+
+	var report ctrfReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, "terratest", report.Results.Tool.Name)
+	assert.Equal(t, 3, report.Results.Summary.Tests)
+	assert.Equal(t, 1, report.Results.Summary.Passed)
+	assert.Equal(t, 1, report.Results.Summary.Failed)
+	assert.Equal(t, 1, report.Results.Summary.Skipped)
+	assert.Equal(t, int64(3000), report.Results.Summary.Stop-report.Results.Summary.Start)
+	assert.Equal(t, []ctrfTest{
+		{Name: "TestPassing", Status: "passed", Duration: 1500, Suite: "github.com/gruntwork-io/terratest/test"},
+		{Name: "TestFailing", Status: "failed", Duration: 1200, Suite: "github.com/gruntwork-io/terratest/test", Message: "example_test.go:26: expected <b>hello</b>"},
+		{Name: "TestSkipped", Status: "skipped", Duration: 0, Suite: "github.com/gruntwork-io/terratest/test", Message: "requires credentials"},
+	}, report.Results.Tests)
+}
+
+func TestGetReportWriterUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := GetReportWriter("pdf")
+	assert.Equal(t, UnknownReportFormat{Format: "pdf"}, err)
+	assert.Contains(t, err.Error(), "ctrf, html, junit, markdown")
+}
+
+func TestSpawnParsersWritesAllFormats(t *testing.T) {
+	t.Parallel()
+
+	output := t.TempDir()
+	SpawnParsers(NewTestLogger(t), openFile(t, "./fixtures/json_example.log"), output, ReportFormats()...)
+
+	for _, fileName := range []string{"report.xml", "report.html", "report.md", "report.ctrf.json"} {
+		info, err := os.Stat(filepath.Join(output, fileName))
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size())
+	}
+
+	html, err := os.ReadFile(filepath.Join(output, "report.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "TestFailing 2023-09-21T10:00:00Z logger.go:66: Running terraform apply")
+}
@@ -7,8 +7,6 @@ import (
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/go-commons/files"
-	junitformatter "github.com/jstemmer/go-junit-report/formatter"
-	junitparser "github.com/jstemmer/go-junit-report/parser"
 	"github.com/sirupsen/logrus"
 )
 
@@ -91,21 +89,3 @@ func ensureDirectoryExists(logger *logrus.Logger, dirName string) error {
 	}
 	return nil
 }
-
-// storeJunitReport takes a parsed Junit report and stores it as report.xml in the output directory
-func storeJunitReport(logger *logrus.Logger, outputDir string, report *junitparser.Report) {
-	ensureDirectoryExists(logger, outputDir)
-	filename := filepath.Join(outputDir, "report.xml")
-	f, err := os.Create(filename)
-	if err != nil {
-		logger.Errorf("Error making file %s for junit report", filename)
-		return
-	}
-	defer f.Close()
-
-	err = junitformatter.JUnitReportXML(report, false, "", f)
-	if err != nil {
-		logger.Errorf("Error formatting junit xml report: %s", err)
-		return
-	}
-}
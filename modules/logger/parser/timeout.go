@@ -0,0 +1,249 @@
+// Package logger/parser contains methods to parse and restructure log output from go testing and terratest
+package parser
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	junitparser "github.com/jstemmer/go-junit-report/parser"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	regexTimeoutPanic    = regexp.MustCompile(`^panic: test timed out after (\S+)`)
+	regexRunningTests    = regexp.MustCompile(`^\s*running tests:\s*$`)
+	regexRunningTest     = regexp.MustCompile(`^\s+(\S+) \(([^)]+)\)$`)
+	regexGoroutineHeader = regexp.MustCompile(`^goroutine \d+ \[`)
+	regexPackageFailed   = regexp.MustCompile(`^FAIL\s+(\S+)\s`)
+)
+
+// Timeout describes a test binary that was killed by the timeout of go test (`panic: test timed out after 10m0s`),
+// along with the tests that had not finished by then. go test does not report a result for these tests, so they are
+// marked as failed in the reports.
+type Timeout struct {
+	// Package is the package of the test binary. If the log ends before go test reports its result, this is the package
+	// of the tests that were in progress instead.
+	Package string `json:"package"`
+	// After is the timeout that was hit, e.g. `10m0s`.
+	After      string           `json:"after"`
+	InProgress []InProgressTest `json:"in_progress"`
+}
+
+// InProgressTest is a test that had started, but not finished, when go test hit its timeout.
+type InProgressTest struct {
+	Test string `json:"test"`
+	// RunningFor is how long the test had been running, as listed by go test 1.20 and later. It is empty for tests that
+	// were not running, such as parallel tests waiting for their turn or subtests whose result go test only reports
+	// along with the result of their parent, and for all tests with older versions of go.
+	RunningFor string `json:"running_for,omitempty"`
+	// Goroutine is the stack of the goroutine of the test at the time of the timeout, which shows where it was stuck.
+	// The functions of subtests are anonymous, so for subtests this is the first goroutine that runs a closure of their
+	// top level test.
+	Goroutine []string `json:"goroutine,omitempty"`
+}
+
+// DetectTimeouts finds the timeout panics of go test in the summary log, and the tests of the given report that were in
+// progress when each of them happened: the tests that go test lists as running, and those that started but have no
+// result line.
+func DetectTimeouts(report *junitparser.Report, logs TestLogs) []Timeout {
+	summaryLog, _ := logs.Log("summary")
+	lines := strings.Split(summaryLog, "\n")
+
+	finished := map[string]bool{}
+	for _, line := range lines {
+		if isResultLine(line) {
+			finished[getTestNameFromResultLine(line)] = true
+		}
+	}
+
+	timeouts := []Timeout{}
+	for i := 0; i < len(lines); i++ {
+		match := regexTimeoutPanic.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && !isSummaryLine(lines[end]) && !isPanicLine(lines[end]) {
+			end++
+		}
+		timeout := Timeout{After: match[1], InProgress: []InProgressTest{}}
+		if end < len(lines) {
+			if packageMatch := regexPackageFailed.FindStringSubmatch(lines[end]); packageMatch != nil {
+				timeout.Package = packageMatch[1]
+			}
+		}
+
+		running := extractRunningTests(lines[i+1 : end])
+		if timeout.Package == "" {
+			// The log was truncated before the result of the package
+			timeout.Package = packageOfInProgressTests(report, running, finished)
+		}
+		goroutines := extractGoroutines(lines[i+1 : end])
+		for _, test := range unfinishedTests(report, timeout.Package, running, finished) {
+			// Subtests that are not running have no goroutine left, and would be mistaken for a running sibling
+			if test.RunningFor != "" || len(running) == 0 || !strings.Contains(test.Test, "/") {
+				test.Goroutine = findTestGoroutine(goroutines, test.Test)
+			}
+			timeout.InProgress = append(timeout.InProgress, test)
+		}
+		timeouts = append(timeouts, timeout)
+		i = end - 1
+	}
+	return timeouts
+}
+
+// extractRunningTests returns the tests that go test lists as running after a timeout panic, along with how long they
+// had been running:
+//
+//	panic: test timed out after 10m0s
+//		running tests:
+//			TestApply (10m0s)
+func extractRunningTests(lines []string) []InProgressTest {
+	running := []InProgressTest{}
+	if len(lines) == 0 || !regexRunningTests.MatchString(lines[0]) {
+		return running
+	}
+	for _, line := range lines[1:] {
+		match := regexRunningTest.FindStringSubmatch(line)
+		if match == nil {
+			break
+		}
+		running = append(running, InProgressTest{Test: match[1], RunningFor: match[2]})
+	}
+	return running
+}
+
+// extractGoroutines returns the stacks of all the goroutines dumped after a panic, each starting with its
+// `goroutine 1 [running]:` header.
+func extractGoroutines(lines []string) [][]string {
+	goroutines := [][]string{}
+	for i := 0; i < len(lines); i++ {
+		if !regexGoroutineHeader.MatchString(lines[i]) {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+			end++
+		}
+		goroutines = append(goroutines, lines[i:end])
+		i = end - 1
+	}
+	return goroutines
+}
+
+// unfinishedTests returns the given running tests, followed by the tests of the given package in the report that are
+// neither running nor finished.
+func unfinishedTests(report *junitparser.Report, packageName string, running []InProgressTest, finished map[string]bool) []InProgressTest {
+	tests := append([]InProgressTest{}, running...)
+	seen := map[string]bool{}
+	for _, test := range running {
+		seen[test.Test] = true
+	}
+	for _, pkg := range report.Packages {
+		if pkg.Name != packageName {
+			continue
+		}
+		for _, test := range pkg.Tests {
+			if !seen[test.Name] && !finished[test.Name] {
+				seen[test.Name] = true
+				tests = append(tests, InProgressTest{Test: test.Name})
+			}
+		}
+	}
+	return tests
+}
+
+// packageOfInProgressTests returns the package in the report of the first of the given running tests, or, if there are
+// none, of the last package with tests that are not finished, which is the one that ran when the log ended. It returns
+// an empty string if there is no such package.
+func packageOfInProgressTests(report *junitparser.Report, running []InProgressTest, finished map[string]bool) string {
+	for _, test := range running {
+		for _, pkg := range report.Packages {
+			for _, reportTest := range pkg.Tests {
+				if reportTest.Name == test.Test {
+					return pkg.Name
+				}
+			}
+		}
+	}
+	for i := len(report.Packages) - 1; i >= 0; i-- {
+		for _, test := range report.Packages[i].Tests {
+			if !finished[test.Name] {
+				return report.Packages[i].Name
+			}
+		}
+	}
+	return ""
+}
+
+// findTestGoroutine returns the stack of the goroutine that runs the function of the given test, or nil if there is
+// none.
+func findTestGoroutine(goroutines [][]string, testName string) []string {
+	topLevelTest := strings.Split(testName, "/")[0]
+	function := "." + topLevelTest + "("
+	if topLevelTest != testName {
+		function = "." + topLevelTest + ".func"
+	}
+	for _, goroutine := range goroutines {
+		for _, line := range goroutine {
+			if strings.Contains(line, function) {
+				return goroutine
+			}
+		}
+	}
+	return nil
+}
+
+// markTimedOutTests marks the tests that were in progress when go test hit a timeout as failed, as go test reports no
+// result for them: the plain text junit parser marks them as failed with the output of whichever test logged last, and
+// `go test -json` reports no event, so they would pass.
+func markTimedOutTests(report *junitparser.Report, timeouts []Timeout) {
+	for _, timeout := range timeouts {
+		for _, inProgress := range timeout.InProgress {
+			for i := range report.Packages {
+				if report.Packages[i].Name != timeout.Package {
+					continue
+				}
+				for _, test := range report.Packages[i].Tests {
+					if test.Name == inProgress.Test {
+						test.Result = junitparser.FAIL
+						test.Output = []string{timeoutMessage(timeout, inProgress)}
+					}
+				}
+			}
+		}
+	}
+}
+
+// timeoutMessage returns a single line summary of why the given test did not finish.
+func timeoutMessage(timeout Timeout, test InProgressTest) string {
+	if test.RunningFor == "" {
+		return "No result when go test timed out after " + timeout.After
+	}
+	return "Still running after " + test.RunningFor + " when go test timed out after " + timeout.After
+}
+
+// storeTimeouts warns about the tests that were in progress when go test hit a timeout, and writes them as JSON to
+// timeouts.json in the output directory. Nothing is written if no timeout was hit.
+func storeTimeouts(logger *logrus.Logger, outputDir string, timeouts []Timeout) {
+	if len(timeouts) == 0 {
+		return
+	}
+
+	for _, timeout := range timeouts {
+		testNames := []string{}
+		for _, test := range timeout.InProgress {
+			testNames = append(testNames, test.Test)
+		}
+		logger.Warnf("Package %s timed out after %s with tests in progress: %s", timeout.Package, timeout.After, strings.Join(testNames, ", "))
+	}
+	storeFile(logger, filepath.Join(outputDir, "timeouts.json"), func(writer io.Writer) error {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(timeouts)
+	})
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	junitparser "github.com/jstemmer/go-junit-report/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectTimeoutsWithoutRunningTests(t *testing.T) {
+	t.Parallel()
+
+	// Before go 1.20, go test did not list the running tests after the timeout panic
+	report := &junitparser.Report{Packages: []junitparser.Package{{
+		Name: "github.com/gruntwork-io/terratest/test",
+		Tests: []*junitparser.Test{
+			{Name: "TestPassing", Result: junitparser.PASS},
+			{Name: "TestApply", Result: junitparser.PASS},
+		},
+	}}}
+	logs := fakeTestLogs{"summary": strings.Join([]string{
+		"--- PASS: TestPassing (0.00s)",
+		"panic: test timed out after 10m0s",
+		"",
+		"goroutine 7 [sleep]:",
+		"time.Sleep(0x3b9aca00)",
+		"github.com/gruntwork-io/terratest/test.TestApply(0xc0000c5300)",
+		"\t/go/src/github.com/gruntwork-io/terratest/test/apply_test.go:30 +0x1c4",
+		"",
+		"goroutine 1 [chan receive]:",
+		"testing.(*T).Run(0xc0000c5200)",
+		"FAIL\tgithub.com/gruntwork-io/terratest/test\t600.012s",
+	}, "\n")}
+
+	timeouts := DetectTimeouts(report, logs)
+	assert.Equal(t, []Timeout{{
+		Package: "github.com/gruntwork-io/terratest/test",
+		After:   "10m0s",
+		InProgress: []InProgressTest{{
+			Test: "TestApply",
+			Goroutine: []string{
+				"goroutine 7 [sleep]:",
+				"time.Sleep(0x3b9aca00)",
+				"github.com/gruntwork-io/terratest/test.TestApply(0xc0000c5300)",
+				"\t/go/src/github.com/gruntwork-io/terratest/test/apply_test.go:30 +0x1c4",
+			},
+		}},
+	}}, timeouts)
+
+	markTimedOutTests(report, timeouts)
+	assert.Equal(t, junitparser.PASS, report.Packages[0].Tests[0].Result)
+	assert.Equal(t, junitparser.FAIL, report.Packages[0].Tests[1].Result)
+	assert.Equal(t, []string{"No result when go test timed out after 10m0s"}, report.Packages[0].Tests[1].Output)
+}
+
+func TestDetectTimeoutsTruncatedLog(t *testing.T) {
+	t.Parallel()
+
+	// The log ends before go test reports the result of the package, e.g. because the job running it was killed
+	report := &junitparser.Report{Packages: []junitparser.Package{
+		{
+			Name:  "github.com/gruntwork-io/terratest/other",
+			Tests: []*junitparser.Test{{Name: "TestOther", Result: junitparser.PASS}},
+		},
+		{
+			Name: "github.com/gruntwork-io/terratest/test",
+			Tests: []*junitparser.Test{
+				{Name: "TestPassing", Result: junitparser.PASS},
+				{Name: "TestApply", Result: junitparser.PASS},
+				{Name: "TestDestroy", Result: junitparser.PASS},
+			},
+		},
+	}}
+	logs := fakeTestLogs{"summary": strings.Join([]string{
+		"--- PASS: TestOther (0.00s)",
+		"--- PASS: TestPassing (0.00s)",
+		"panic: test timed out after 10m0s",
+		"\trunning tests:",
+		"\t\tTestApply (10m0s)",
+		"",
+	}, "\n")}
+
+	timeouts := DetectTimeouts(report, logs)
+	assert.Len(t, timeouts, 1)
+	assert.Equal(t, "github.com/gruntwork-io/terratest/test", timeouts[0].Package)
+	assert.Equal(t, []InProgressTest{
+		{Test: "TestApply", RunningFor: "10m0s"},
+		{Test: "TestDestroy"},
+	}, timeouts[0].InProgress)
+
+	markTimedOutTests(report, timeouts)
+	assert.Equal(t, junitparser.PASS, report.Packages[0].Tests[0].Result)
+	assert.Equal(t, junitparser.PASS, report.Packages[1].Tests[0].Result)
+	assert.Equal(t, junitparser.FAIL, report.Packages[1].Tests[1].Result)
+	assert.Equal(t, junitparser.FAIL, report.Packages[1].Tests[2].Result)
+}
+
+func TestDetectTimeoutsNoTimeout(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []Timeout{}, DetectTimeouts(exampleReport(), exampleLogs))
+}
+
+func TestExtractRunningTests(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"\trunning tests:",
+		"\t\tTestApply (10m0s)",
+		"\t\tTestApply/Subtest (9m58s)",
+		"",
+		"goroutine 7 [running]:",
+	}
+	assert.Equal(t, []InProgressTest{
+		{Test: "TestApply", RunningFor: "10m0s"},
+		{Test: "TestApply/Subtest", RunningFor: "9m58s"},
+	}, extractRunningTests(lines))
+	assert.Equal(t, []InProgressTest{}, extractRunningTests(lines[3:]))
+}
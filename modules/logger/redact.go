@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces secrets in log output.
+const Redacted = "[REDACTED]"
+
+// MinSecretLength is the length below which registered secret values are ignored, as redacting every occurrence of a
+// value like "1" or "yes" would make the logs unreadable.
+const MinSecretLength = 4
+
+// SecretPatterns are the patterns of well known secrets that every new Redactor redacts. If a pattern has a capture
+// group named "secret", only that group is redacted, so that e.g. the `Bearer` of a bearer token stays in the log.
+var SecretPatterns = []*regexp.Regexp{
+	// AWS access key IDs
+	regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA|A3T[A-Z0-9])[A-Z0-9]{16}\b`),
+	// AWS secret access keys, which can only be told apart from other base64 strings by the name they are assigned to
+	regexp.MustCompile(`(?i)(?:aws_?secret_?access_?key|secret_?access_?key)["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9/+=]{40})`),
+	// Private keys in PEM format, or the first line of one if the key is logged line by line (see StreamRedactor)
+	regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----(?:[\s\S]*?-----END [A-Z0-9 ]*PRIVATE KEY-----|[\s\S]*)`),
+	// Bearer tokens, e.g. in an Authorization header
+	regexp.MustCompile(`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9\-._~+/]+=*)`),
+}
+
+// DefaultRedactor redacts the secrets in all the messages logged by a Logger or by Logf, Log and DoLog. Register
+// secrets with RegisterSecret and RegisterSecretPattern.
+var DefaultRedactor = NewRedactor()
+
+// RegisterSecret makes the DefaultRedactor redact the given values from all log output, e.g. passwords passed to a
+// command on the command line.
+func RegisterSecret(values ...string) {
+	DefaultRedactor.AddSecret(values...)
+}
+
+// RegisterSecretPattern makes the DefaultRedactor redact all matches of the given patterns from all log output.
+func RegisterSecretPattern(patterns ...*regexp.Regexp) {
+	DefaultRedactor.AddPattern(patterns...)
+}
+
+// Redact returns the given text with all the secrets known to the DefaultRedactor replaced by Redacted.
+func Redact(text string) string {
+	return DefaultRedactor.Redact(text)
+}
+
+// Redactor replaces secret values and matches of secret patterns in text. It is safe for concurrent use.
+type Redactor struct {
+	mutex    sync.RWMutex
+	secrets  map[string]bool
+	replacer *strings.Replacer
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor creates a Redactor that redacts the SecretPatterns.
+func NewRedactor() *Redactor {
+	return &Redactor{
+		secrets:  map[string]bool{},
+		patterns: append([]*regexp.Regexp{}, SecretPatterns...),
+	}
+}
+
+// AddSecret makes the redactor redact the given values. Values shorter than MinSecretLength are ignored.
+func (redactor *Redactor) AddSecret(values ...string) {
+	redactor.mutex.Lock()
+	defer redactor.mutex.Unlock()
+
+	added := false
+	for _, value := range values {
+		if len(value) >= MinSecretLength && !redactor.secrets[value] {
+			redactor.secrets[value] = true
+			added = true
+		}
+	}
+	if !added {
+		return
+	}
+
+	// Replace longer secrets first, so that a secret that contains another one is redacted as a whole
+	secrets := make([]string, 0, len(redactor.secrets))
+	for secret := range redactor.secrets {
+		secrets = append(secrets, secret)
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		if len(secrets[i]) != len(secrets[j]) {
+			return len(secrets[i]) > len(secrets[j])
+		}
+		return secrets[i] < secrets[j]
+	})
+	oldNew := make([]string, 0, 2*len(secrets))
+	for _, secret := range secrets {
+		oldNew = append(oldNew, secret, Redacted)
+	}
+	redactor.replacer = strings.NewReplacer(oldNew...)
+}
+
+// AddPattern makes the redactor redact all matches of the given patterns. If a pattern has a capture group named
+// "secret", only that group is redacted.
+func (redactor *Redactor) AddPattern(patterns ...*regexp.Regexp) {
+	redactor.mutex.Lock()
+	defer redactor.mutex.Unlock()
+
+	redactor.patterns = append(redactor.patterns, patterns...)
+}
+
+// Redact returns the given text with all secret values and matches of secret patterns replaced by Redacted.
+func (redactor *Redactor) Redact(text string) string {
+	if redactor == nil {
+		return text
+	}
+
+	redactor.mutex.RLock()
+	defer redactor.mutex.RUnlock()
+
+	if redactor.replacer != nil {
+		text = redactor.replacer.Replace(text)
+	}
+	for _, pattern := range redactor.patterns {
+		text = redactPattern(pattern, text)
+	}
+	return text
+}
+
+// redactPattern replaces the matches of the given pattern in the given text, or only their "secret" group if the
+// pattern has one.
+func redactPattern(pattern *regexp.Regexp, text string) string {
+	matches := pattern.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+
+	group := pattern.SubexpIndex("secret")
+	var out strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if group > 0 && match[2*group] >= 0 {
+			start, end = match[2*group], match[2*group+1]
+		}
+		out.WriteString(text[last:start])
+		out.WriteString(Redacted)
+		last = end
+	}
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+var (
+	regexPrivateKeyBegin = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----`)
+	regexPrivateKeyEnd   = regexp.MustCompile(`-----END [A-Z0-9 ]*PRIVATE KEY-----`)
+)
+
+// StreamRedactor redacts a stream of lines, such as the output of a command, which is logged line by line. On top of
+// what the DefaultRedactor redacts, it redacts all the lines of a private key in PEM format, which a single line can
+// not be matched against. A StreamRedactor must only be used for a single stream, and not concurrently.
+type StreamRedactor struct {
+	inPrivateKey bool
+}
+
+// RedactLine returns the given line of the stream with all secrets replaced by Redacted.
+func (stream *StreamRedactor) RedactLine(line string) string {
+	if stream.inPrivateKey {
+		if location := regexPrivateKeyEnd.FindStringIndex(line); location != nil {
+			stream.inPrivateKey = false
+			return Redact(Redacted + line[location[1]:])
+		}
+		return Redacted
+	}
+
+	if regexPrivateKeyBegin.MatchString(line) && !regexPrivateKeyEnd.MatchString(line) {
+		stream.inPrivateKey = true
+	}
+	return Redact(line)
+}
+
+// redactFields returns the given fields with the secrets in their string values redacted.
+func redactFields(fields []Field) []Field {
+	redacted := make([]Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = field
+		if value, isString := field.Value.(string); isString {
+			redacted[i].Value = Redact(value)
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// TestNameKey is the key of the attribute that holds the name of the test in the records NewSlogLogger hands to its
+// handler.
+const TestNameKey = "test"
+
+// NewSlogLogger creates a Logger that hands every message, along with its level and fields, to the given log/slog
+// handler. This routes terratest logs into the logging setup of the caller, e.g. JSON to a file and text to the
+// console:
+//
+//	file, _ := os.Create("terratest.json")
+//	logger.Default = logger.NewSlogLogger(slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelInfo}))
+//
+// The name of the test is added to each record as the attribute TestNameKey. The handler decides which levels it
+// logs; use WithMinLevel to also drop messages before they are formatted.
+func NewSlogLogger(handler slog.Handler) *Logger {
+	return New(slogLogger{handler: handler})
+}
+
+type slogLogger struct {
+	handler slog.Handler
+}
+
+func (l slogLogger) Logf(t testing.TestingT, format string, args ...interface{}) {
+	l.LogEntry(t, Entry{Time: time.Now(), Level: LevelInfo, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l slogLogger) LogEntry(t testing.TestingT, entry Entry) {
+	ctx := context.Background()
+	level := slog.Level(entry.Level)
+	if !l.handler.Enabled(ctx, level) {
+		return
+	}
+
+	record := slog.NewRecord(entry.Time, level, entry.Message, 0)
+	if t != nil {
+		record.AddAttrs(slog.String(TestNameKey, t.Name()))
+	}
+	for _, field := range entry.Fields {
+		record.AddAttrs(slog.Any(field.Key, field.Value))
+	}
+	// Errors of the handler are dropped, as is the case with slog.Logger
+	_ = l.handler.Handle(ctx, record)
+}
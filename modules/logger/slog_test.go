@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogLogger(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	l := NewSlogLogger(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	l.With("module", "retry", "attempt", 2).Debugf(t, "dropped by the handler")
+	l.With("module", "shell", "command", "terraform").Errorf(t, "command %s failed", "terraform")
+	l.Logf(t, "plain")
+
+	decoder := json.NewDecoder(&buffer)
+	var record map[string]interface{}
+	require.NoError(t, decoder.Decode(&record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "command terraform failed", record["msg"])
+	assert.Equal(t, t.Name(), record[TestNameKey])
+	assert.Equal(t, "shell", record["module"])
+	assert.Equal(t, "terraform", record["command"])
+
+	record = map[string]interface{}{}
+	require.NoError(t, decoder.Decode(&record))
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "plain", record["msg"])
+
+	assert.False(t, decoder.More())
+}
@@ -50,6 +50,24 @@ func DoWithTimeoutE(t testing.TestingT, actionDescription string, timeout time.D
 	}
 }
 
+// deadliner is implemented by Go's testing.T, which reports the deadline set by the go test -timeout flag.
+type deadliner interface {
+	Deadline() (deadline time.Time, ok bool)
+}
+
+// ContextFromTestDeadline returns a context that is cancelled gracePeriod before the deadline of the given test, as
+// reported by t.Deadline(). This allows every wait in a test to give up early enough for cleanup (e.g., terraform
+// destroy) to run before go test kills the process. If the test has no deadline, or t does not report one, the
+// returned context has no deadline either. The caller must call the returned cancel function when done.
+func ContextFromTestDeadline(t testing.TestingT, gracePeriod time.Duration) (context.Context, context.CancelFunc) {
+	if tt, ok := t.(deadliner); ok {
+		if deadline, hasDeadline := tt.Deadline(); hasDeadline {
+			return context.WithDeadline(context.Background(), deadline.Add(-gracePeriod))
+		}
+	}
+	return context.WithCancel(context.Background())
+}
+
 // DoWithRetry runs the specified action. If it returns a string, return that string. If it returns a FatalError, return that error
 // immediately. If it returns any other type of error, sleep for sleepBetweenRetries and try again, up to a maximum of
 // maxRetries retries. If maxRetries is exceeded, fail the test.
@@ -84,24 +102,87 @@ func DoWithRetryInterface(t testing.TestingT, actionDescription string, maxRetri
 // immediately. If it returns any other type of error, sleep for sleepBetweenRetries and try again, up to a maximum of
 // maxRetries retries. If maxRetries is exceeded, return a MaxRetriesExceeded error.
 func DoWithRetryInterfaceE(t testing.TestingT, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() (interface{}, error)) (interface{}, error) {
+	return DoWithRetryInterfaceContextE(t, context.Background(), actionDescription, maxRetries, sleepBetweenRetries, action)
+}
+
+// DoWithRetryContext runs the specified action like DoWithRetry, but stops retrying as soon as the given context is
+// done. If the context is done or maxRetries is exceeded, fail the test.
+func DoWithRetryContext(t testing.TestingT, ctx context.Context, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) string {
+	out, err := DoWithRetryContextE(t, ctx, actionDescription, maxRetries, sleepBetweenRetries, action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// DoWithRetryContextE runs the specified action like DoWithRetryE, but stops retrying as soon as the given context is
+// done. If the context is done before the action succeeds, return a ContextDone error.
+func DoWithRetryContextE(t testing.TestingT, ctx context.Context, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) (string, error) {
+	out, err := DoWithRetryInterfaceContextE(t, ctx, actionDescription, maxRetries, sleepBetweenRetries, func() (interface{}, error) { return action() })
+	// out is nil if the context was done before the action ran even once
+	outStr, _ := out.(string)
+	return outStr, err
+}
+
+// DoWithRetryInterfaceContext runs the specified action like DoWithRetryInterface, but stops retrying as soon as the
+// given context is done. If the context is done or maxRetries is exceeded, fail the test.
+func DoWithRetryInterfaceContext(t testing.TestingT, ctx context.Context, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() (interface{}, error)) interface{} {
+	out, err := DoWithRetryInterfaceContextE(t, ctx, actionDescription, maxRetries, sleepBetweenRetries, action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// DoWithRetryInterfaceContextE runs the specified action. If it returns a value, return that value. If it returns a
+// FatalError, return that error immediately. If it returns any other type of error, sleep for sleepBetweenRetries and
+// try again, up to a maximum of maxRetries retries. If maxRetries is exceeded, return a MaxRetriesExceeded error. If
+// the given context is done before an attempt or while sleeping between attempts, return a ContextDone error right
+// away.
+//
+// If DefaultCollector is set, every attempt is recorded in it.
+func DoWithRetryInterfaceContextE(t testing.TestingT, ctx context.Context, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() (interface{}, error)) (interface{}, error) {
+	rec := DefaultCollector.Start(t, actionDescription, maxRetries)
+	output, err := doWithRetry(t, ctx, rec, actionDescription, maxRetries, sleepBetweenRetries, action)
+	rec.Finish(err)
+	return output, err
+}
+
+func doWithRetry(t testing.TestingT, ctx context.Context, rec *Recorder, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() (interface{}, error)) (interface{}, error) {
 	var output interface{}
 	var err error
 
+	log := logger.Default.With("module", "retry", "description", actionDescription)
 	for i := 0; i <= maxRetries; i++ {
-		logger.Default.Logf(t, "%s", actionDescription)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return output, ContextDone{Description: actionDescription, Underlying: ctxErr, LastError: err}
+		}
 
+		attemptLog := log.With("attempt", i+1)
+		attemptLog.Debugf(t, "%s", actionDescription)
+
+		attemptStart := time.Now()
 		output, err = action()
+		rec.Attempt(attemptStart, err)
 		if err == nil {
 			return output, nil
 		}
 
 		if _, isFatalErr := err.(FatalError); isFatalErr {
-			logger.Default.Logf(t, "Returning due to fatal error: %v", err)
+			attemptLog.Errorf(t, "Returning due to fatal error: %v", err)
 			return output, err
 		}
 
-		logger.Default.Logf(t, "%s returned an error: %s. Sleeping for %s and will try again.", actionDescription, err.Error(), sleepBetweenRetries)
-		time.Sleep(sleepBetweenRetries)
+		attemptLog.Debugf(t, "%s returned an error: %s. Sleeping for %s and will try again.", actionDescription, err.Error(), sleepBetweenRetries)
+
+		timer := time.NewTimer(sleepBetweenRetries)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			attemptLog.Warnf(t, "Giving up on %s: %v", actionDescription, ctx.Err())
+			return output, ContextDone{Description: actionDescription, Underlying: ctx.Err(), LastError: err}
+		}
 	}
 
 	return output, MaxRetriesExceeded{Description: actionDescription, MaxRetries: maxRetries}
@@ -124,6 +205,20 @@ func DoWithRetryableErrors(t testing.TestingT, actionDescription string, retryab
 // sleepBetweenRetries, and retry the specified action, up to a maximum of maxRetries retries. If there is no match,
 // return that error immediately, wrapped in a FatalError. If maxRetries is exceeded, return a MaxRetriesExceeded error.
 func DoWithRetryableErrorsE(t testing.TestingT, actionDescription string, retryableErrors map[string]string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) (string, error) {
+	return DoWithRetryableErrorsContextE(t, context.Background(), actionDescription, retryableErrors, maxRetries, sleepBetweenRetries, action)
+}
+
+// DoWithRetryableErrorsContext runs the specified action like DoWithRetryableErrors, but stops retrying as soon as the
+// given context is done. If the context is done or maxRetries is exceeded, fail the test.
+func DoWithRetryableErrorsContext(t testing.TestingT, ctx context.Context, actionDescription string, retryableErrors map[string]string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) string {
+	out, err := DoWithRetryableErrorsContextE(t, ctx, actionDescription, retryableErrors, maxRetries, sleepBetweenRetries, action)
+	require.NoError(t, err)
+	return out
+}
+
+// DoWithRetryableErrorsContextE runs the specified action like DoWithRetryableErrorsE, but stops retrying as soon as
+// the given context is done. If the context is done before the action succeeds, return a ContextDone error.
+func DoWithRetryableErrorsContextE(t testing.TestingT, ctx context.Context, actionDescription string, retryableErrors map[string]string, maxRetries int, sleepBetweenRetries time.Duration, action func() (string, error)) (string, error) {
 	retryableErrorsRegexp := map[*regexp.Regexp]string{}
 	for errorStr, errorMessage := range retryableErrors {
 		errorRegex, err := regexp.Compile(errorStr)
@@ -133,7 +228,7 @@ func DoWithRetryableErrorsE(t testing.TestingT, actionDescription string, retrya
 		retryableErrorsRegexp[errorRegex] = errorMessage
 	}
 
-	return DoWithRetryE(t, actionDescription, maxRetries, sleepBetweenRetries, func() (string, error) {
+	return DoWithRetryContextE(t, ctx, actionDescription, maxRetries, sleepBetweenRetries, func() (string, error) {
 		output, err := action()
 		if err == nil {
 			return output, nil
@@ -141,7 +236,7 @@ func DoWithRetryableErrorsE(t testing.TestingT, actionDescription string, retrya
 
 		for errorRegexp, errorMessage := range retryableErrorsRegexp {
 			if errorRegexp.MatchString(output) || errorRegexp.MatchString(err.Error()) {
-				logger.Default.Logf(t, "'%s' failed with the error '%s' but this error was expected and warrants a retry. Further details: %s\n", actionDescription, err.Error(), errorMessage)
+				logger.Default.With("module", "retry", "description", actionDescription).Debugf(t, "'%s' failed with the error '%s' but this error was expected and warrants a retry. Further details: %s\n", actionDescription, err.Error(), errorMessage)
 				return output, err
 			}
 		}
@@ -166,18 +261,19 @@ func DoInBackgroundUntilStopped(t testing.TestingT, actionDescription string, sl
 	stop := make(chan bool)
 
 	go func() {
+		log := logger.Default.With("module", "retry", "description", actionDescription)
 		for {
-			logger.Default.Logf(t, "Executing action '%s'", actionDescription)
+			log.Debugf(t, "Executing action '%s'", actionDescription)
 
 			action()
 
-			logger.Default.Logf(t, "Sleeping for %s before repeating action '%s'", sleepBetweenRepeats, actionDescription)
+			log.Debugf(t, "Sleeping for %s before repeating action '%s'", sleepBetweenRepeats, actionDescription)
 
 			select {
 			case <-time.After(sleepBetweenRepeats):
 				// Nothing to do, just allow the loop to continue
 			case <-stop:
-				logger.Default.Logf(t, "Received stop signal for action '%s'.", actionDescription)
+				log.Debugf(t, "Received stop signal for action '%s'.", actionDescription)
 				return
 			}
 		}
@@ -208,6 +304,28 @@ func (err MaxRetriesExceeded) Error() string {
 	return fmt.Sprintf("'%s' unsuccessful after %d retries", err.Description, err.MaxRetries)
 }
 
+// ContextDone is an error that occurs when the context passed to a retry function is cancelled or its deadline passes
+// before the action succeeds.
+type ContextDone struct {
+	Description string
+	// Underlying is the error returned by the context, e.g. context.DeadlineExceeded or context.Canceled.
+	Underlying error
+	// LastError is the error returned by the last attempt of the action, if any.
+	LastError error
+}
+
+func (err ContextDone) Error() string {
+	if err.LastError != nil {
+		return fmt.Sprintf("'%s' did not succeed before the context was done (%v); last error: %v", err.Description, err.Underlying, err.LastError)
+	}
+	return fmt.Sprintf("'%s' did not succeed before the context was done (%v)", err.Description, err.Underlying)
+}
+
+// Unwrap returns the context error so that errors.Is(err, context.DeadlineExceeded) works on a ContextDone.
+func (err ContextDone) Unwrap() error {
+	return err.Underlying
+}
+
 // FatalError is a marker interface for errors that should not be retried.
 type FatalError struct {
 	Underlying error
@@ -1,11 +1,13 @@
 package retry
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDoWithRetry(t *testing.T) {
@@ -262,3 +264,64 @@ type ErrorCounter int
 func (count ErrorCounter) Error() string {
 	return fmt.Sprintf("%d", int(count))
 }
+
+func TestDoWithRetryContext(t *testing.T) {
+	t.Parallel()
+
+	expectedOutput := "expected"
+	expectedError := fmt.Errorf("expected error")
+
+	t.Run("Return value when context is not done", func(t *testing.T) {
+		t.Parallel()
+
+		actualOutput, err := DoWithRetryContextE(t, context.Background(), t.Name(), 10, 1*time.Millisecond, func() (string, error) { return expectedOutput, nil })
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOutput, actualOutput)
+	})
+
+	t.Run("Do not run the action when context is already cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		calls := 0
+		_, err := DoWithRetryContextE(t, ctx, t.Name(), 10, 1*time.Millisecond, func() (string, error) {
+			calls++
+			return expectedOutput, nil
+		})
+		assert.Equal(t, 0, calls)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Stop sleeping between retries when deadline passes", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		actualOutput, err := DoWithRetryContextE(t, ctx, t.Name(), 10, 1*time.Hour, func() (string, error) { return expectedOutput, expectedError })
+		assert.Less(t, time.Since(start), 10*time.Second)
+		assert.Equal(t, expectedOutput, actualOutput)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		var contextDone ContextDone
+		require.ErrorAs(t, err, &contextDone)
+		assert.Equal(t, expectedError, contextDone.LastError)
+	})
+}
+
+func TestContextFromTestDeadline(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := ContextFromTestDeadline(t, time.Minute)
+	defer cancel()
+
+	testDeadline, hasTestDeadline := t.Deadline()
+	ctxDeadline, hasCtxDeadline := ctx.Deadline()
+	assert.Equal(t, hasTestDeadline, hasCtxDeadline)
+	if hasTestDeadline {
+		assert.Equal(t, testDeadline.Add(-time.Minute), ctxDeadline)
+	}
+}
@@ -0,0 +1,243 @@
+package retry
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// DefaultCollector, if set, records the attempts of every retried action (DoWithRetry, DoWithRetryableErrors and
+// their variants). It is nil by default, which disables recording. Set it once, e.g. in TestMain, and export the
+// collected data at the end of the test run with SaveJSON:
+//
+//	func TestMain(m *testing.M) {
+//		retry.DefaultCollector = retry.NewCollector()
+//		code := m.Run()
+//		retry.DefaultCollector.SaveJSON("retry-telemetry.json")
+//		os.Exit(code)
+//	}
+var DefaultCollector *Collector
+
+// Outcome describes how a retried action ended.
+type Outcome string
+
+const (
+	// OutcomeSucceeded means the action eventually returned without an error.
+	OutcomeSucceeded Outcome = "succeeded"
+	// OutcomeFatalError means the action returned a FatalError and was not retried any further.
+	OutcomeFatalError Outcome = "fatal_error"
+	// OutcomeMaxRetriesExceeded means the action kept failing until the maximum number of retries was reached.
+	OutcomeMaxRetriesExceeded Outcome = "max_retries_exceeded"
+	// OutcomeContextDone means the context passed to the retry function was done before the action succeeded.
+	OutcomeContextDone Outcome = "context_done"
+)
+
+// Attempt is a single execution of a retried action.
+type Attempt struct {
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration_ns"`
+	// Error is the error message returned by the action, or empty if the attempt succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// Record holds everything that happened during one invocation of a retry function.
+type Record struct {
+	TestName    string        `json:"test_name"`
+	Description string        `json:"description"`
+	MaxRetries  int           `json:"max_retries"`
+	Start       time.Time     `json:"start"`
+	Duration    time.Duration `json:"duration_ns"`
+	Outcome     Outcome       `json:"outcome"`
+	Attempts    []Attempt     `json:"attempts"`
+}
+
+// Summary aggregates all the records that share the same description.
+type Summary struct {
+	Description string `json:"description"`
+	// Invocations is the number of times an action with this description was retried.
+	Invocations int `json:"invocations"`
+	// TotalAttempts is the sum of the attempts over all invocations.
+	TotalAttempts int `json:"total_attempts"`
+	// MaxAttempts is the largest number of attempts a single invocation needed.
+	MaxAttempts int `json:"max_attempts"`
+	// Flaky is the number of invocations that succeeded, but only after at least one failed attempt.
+	Flaky int `json:"flaky"`
+	// Failed is the number of invocations that did not succeed.
+	Failed int `json:"failed"`
+	// Errors counts how often each error message was seen across all attempts.
+	Errors map[string]int `json:"errors,omitempty"`
+}
+
+// Collector stores the records of retried actions. It is safe for concurrent use, so it can be shared by parallel
+// tests.
+type Collector struct {
+	mutex   sync.Mutex
+	records []Record
+}
+
+// NewCollector creates an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Records returns a copy of all the records collected so far.
+func (collector *Collector) Records() []Record {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	records := make([]Record, len(collector.records))
+	copy(records, collector.records)
+	return records
+}
+
+// Summarize aggregates the collected records by description. The result is sorted so that the actions that needed
+// the most retries come first.
+func (collector *Collector) Summarize() []Summary {
+	summaries := map[string]*Summary{}
+	for _, record := range collector.Records() {
+		summary, ok := summaries[record.Description]
+		if !ok {
+			summary = &Summary{Description: record.Description, Errors: map[string]int{}}
+			summaries[record.Description] = summary
+		}
+
+		numAttempts := len(record.Attempts)
+		summary.Invocations++
+		summary.TotalAttempts += numAttempts
+		if numAttempts > summary.MaxAttempts {
+			summary.MaxAttempts = numAttempts
+		}
+		if record.Outcome != OutcomeSucceeded {
+			summary.Failed++
+		} else if numAttempts > 1 {
+			summary.Flaky++
+		}
+		for _, attempt := range record.Attempts {
+			if attempt.Error != "" {
+				summary.Errors[attempt.Error]++
+			}
+		}
+	}
+
+	out := make([]Summary, 0, len(summaries))
+	for _, summary := range summaries {
+		out = append(out, *summary)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		retriesI := out[i].TotalAttempts - out[i].Invocations
+		retriesJ := out[j].TotalAttempts - out[j].Invocations
+		if retriesI != retriesJ {
+			return retriesI > retriesJ
+		}
+		return out[i].Description < out[j].Description
+	})
+	return out
+}
+
+// telemetryReport is the JSON document written by WriteJSON.
+type telemetryReport struct {
+	Summary []Summary `json:"summary"`
+	Records []Record  `json:"records"`
+}
+
+// WriteJSON writes the summary and all collected records as a JSON document to the given writer.
+func (collector *Collector) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(telemetryReport{Summary: collector.Summarize(), Records: collector.Records()})
+}
+
+// SaveJSON writes the summary and all collected records as a JSON document to the file at the given path, creating
+// the parent directories if needed.
+func (collector *Collector) SaveJSON(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return collector.WriteJSON(file)
+}
+
+func (collector *Collector) add(record Record) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	collector.records = append(collector.records, record)
+}
+
+// Recorder builds up the Record of a single invocation of a retried action. A nil Recorder, which is what a nil
+// Collector hands out, records nothing.
+type Recorder struct {
+	collector *Collector
+	record    Record
+}
+
+// Start begins the record of an invocation of a retried action. The retry functions of this package do this
+// themselves; call it, along with Attempt and Finish, to record actions that are retried some other way, such as the
+// waits of the k8s module.
+func (collector *Collector) Start(t testing.TestingT, description string, maxRetries int) *Recorder {
+	if collector == nil {
+		return nil
+	}
+	return &Recorder{
+		collector: collector,
+		record: Record{
+			TestName:    t.Name(),
+			Description: description,
+			MaxRetries:  maxRetries,
+			Start:       time.Now(),
+		},
+	}
+}
+
+// Attempt records an attempt that started at the given time and just returned the given error.
+func (rec *Recorder) Attempt(start time.Time, err error) {
+	if rec == nil {
+		return
+	}
+
+	attempt := Attempt{Start: start, Duration: time.Since(start)}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	rec.record.Attempts = append(rec.record.Attempts, attempt)
+}
+
+// Finish completes the record with the outcome of the given error, which is the one the retried action ended with,
+// and adds it to the collector.
+func (rec *Recorder) Finish(err error) {
+	if rec == nil {
+		return
+	}
+
+	rec.record.Duration = time.Since(rec.record.Start)
+	rec.record.Outcome = outcomeOf(err)
+	rec.collector.add(rec.record)
+}
+
+func outcomeOf(err error) Outcome {
+	var contextDone ContextDone
+	var maxRetriesExceeded MaxRetriesExceeded
+	switch {
+	case err == nil:
+		return OutcomeSucceeded
+	case errors.As(err, &contextDone):
+		return OutcomeContextDone
+	case errors.As(err, &maxRetriesExceeded):
+		return OutcomeMaxRetriesExceeded
+	default:
+		return OutcomeFatalError
+	}
+}
@@ -0,0 +1,82 @@
+package retry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Not parallel, as this test swaps out the package level DefaultCollector.
+func TestDefaultCollectorRecordsAttempts(t *testing.T) {
+	collector := NewCollector()
+	DefaultCollector = collector
+	defer func() { DefaultCollector = nil }()
+
+	count := 0
+	_, err := DoWithRetryE(t, "flaky action", 5, 1*time.Millisecond, func() (string, error) {
+		count++
+		if count < 3 {
+			return "", fmt.Errorf("attempt %d failed", count)
+		}
+		return "ok", nil
+	})
+	require.NoError(t, err)
+
+	_, err = DoWithRetryE(t, "failing action", 1, 1*time.Millisecond, func() (string, error) {
+		return "", fmt.Errorf("always fails")
+	})
+	require.Error(t, err)
+
+	_, err = DoWithRetryE(t, "fatal action", 10, 1*time.Millisecond, func() (string, error) {
+		return "", FatalError{Underlying: fmt.Errorf("boom")}
+	})
+	require.Error(t, err)
+
+	records := collector.Records()
+	require.Len(t, records, 3)
+
+	assert.Equal(t, t.Name(), records[0].TestName)
+	assert.Equal(t, "flaky action", records[0].Description)
+	assert.Equal(t, OutcomeSucceeded, records[0].Outcome)
+	require.Len(t, records[0].Attempts, 3)
+	assert.Equal(t, "attempt 1 failed", records[0].Attempts[0].Error)
+	assert.Equal(t, "", records[0].Attempts[2].Error)
+
+	assert.Equal(t, OutcomeMaxRetriesExceeded, records[1].Outcome)
+	assert.Len(t, records[1].Attempts, 2)
+
+	assert.Equal(t, OutcomeFatalError, records[2].Outcome)
+	assert.Len(t, records[2].Attempts, 1)
+
+	summaries := collector.Summarize()
+	require.Len(t, summaries, 3)
+	assert.Equal(t, "flaky action", summaries[0].Description)
+	assert.Equal(t, 1, summaries[0].Flaky)
+	assert.Equal(t, 3, summaries[0].MaxAttempts)
+	assert.Equal(t, "failing action", summaries[1].Description)
+	assert.Equal(t, 1, summaries[1].Failed)
+	assert.Equal(t, map[string]int{"always fails": 2}, summaries[1].Errors)
+
+	var buffer bytes.Buffer
+	require.NoError(t, collector.WriteJSON(&buffer))
+
+	var report telemetryReport
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &report))
+	assert.Len(t, report.Records, 3)
+	assert.Len(t, report.Summary, 3)
+}
+
+func TestNilCollectorRecordsNothing(t *testing.T) {
+	t.Parallel()
+
+	var collector *Collector
+	rec := collector.Start(t, "action", 1)
+	rec.Attempt(time.Now(), nil)
+	rec.Finish(nil)
+	assert.Nil(t, rec)
+}
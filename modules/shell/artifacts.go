@@ -0,0 +1,288 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// ArtifactsDirEnvVar is the name of the environment variable that, if set, enables capturing the artifacts of every
+// test to the directory it points to, in a subdirectory per test (see TestArtifactsDir): the output of every command,
+// unless the command sets its own ArtifactsDir, as well as e.g. the dumps of k8s.DumpNamespaceOnFailure.
+const ArtifactsDirEnvVar = "TERRATEST_ARTIFACTS_DIR"
+
+// CommandArtifact is the metadata written, as JSON, next to the captured output of a command.
+type CommandArtifact struct {
+	Command    string            `json:"command"`
+	Args       []string          `json:"args"`
+	WorkingDir string            `json:"working_dir"`
+	Env        map[string]string `json:"env,omitempty"` // Only the variables set by the command, not the inherited ones
+	StartTime  time.Time         `json:"start_time"`
+	EndTime    time.Time         `json:"end_time"`
+	Duration   time.Duration     `json:"duration_ns"`
+	ExitCode   int               `json:"exit_code"`
+	Error      string            `json:"error,omitempty"`
+	Stdout     string            `json:"stdout_file"`
+	Stderr     string            `json:"stderr_file"`
+}
+
+var (
+	// testArtifacts holds the artifacts state of each running test, keyed by its testing.TestingT, so that a test that
+	// runs again in the same process, e.g. with -count=2, starts over with the same directory and numbering.
+	testArtifacts = map[interface{}]*testArtifactsState{}
+	// artifactDirOwners holds the name of the test that uses each artifacts directory, to give tests whose names are the
+	// same once sanitized different directories.
+	artifactDirOwners   = map[string]string{}
+	testArtifactsMutex  sync.Mutex
+	unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// testArtifactsState is the artifacts directory of a test and the number of commands it captured so far.
+type testArtifactsState struct {
+	dir   string
+	count int
+}
+
+// cleanupT is implemented by testing.T and testing.B, which run a function once the test has finished.
+type cleanupT interface {
+	Cleanup(func())
+}
+
+// TestArtifactsDir returns the directory to write the artifacts of the given test to: the subdirectory of the
+// directory ArtifactsDirEnvVar points to that is named after the test, or an empty string if ArtifactsDirEnvVar is not
+// set. Characters of the test name that are not safe in file names are replaced, and if two tests end up with the same
+// name, the second gets a -2 suffix. The directory is not created.
+func TestArtifactsDir(t testing.TestingT) string {
+	rootDir := os.Getenv(ArtifactsDirEnvVar)
+	if rootDir == "" {
+		return ""
+	}
+	return TestArtifactsDirIn(t, rootDir)
+}
+
+// TestArtifactsDirIn is like TestArtifactsDir, but returns the subdirectory named after the given test of the given
+// root directory, for artifacts that are written even if ArtifactsDirEnvVar is not set.
+func TestArtifactsDirIn(t testing.TestingT, rootDir string) string {
+	testArtifactsMutex.Lock()
+	defer testArtifactsMutex.Unlock()
+	return getTestArtifactsState(t, rootDir).dir
+}
+
+// nextTestArtifact returns the artifacts directory of the given test under the given root directory, along with the
+// number of the next command the test captures, starting at 1.
+func nextTestArtifact(t testing.TestingT, rootDir string) (string, int) {
+	testArtifactsMutex.Lock()
+	defer testArtifactsMutex.Unlock()
+
+	state := getTestArtifactsState(t, rootDir)
+	state.count++
+	return state.dir, state.count
+}
+
+// getTestArtifactsState returns the artifacts state of the given test under the given root directory, creating it if
+// needed. The caller must hold testArtifactsMutex.
+func getTestArtifactsState(t testing.TestingT, rootDir string) *testArtifactsState {
+	key := testArtifactsKey(t, rootDir)
+	state, hasState := testArtifacts[key]
+	if !hasState {
+		state = &testArtifactsState{dir: claimArtifactsDir(rootDir, t.Name())}
+		testArtifacts[key] = state
+		if cleanup, ok := t.(cleanupT); ok {
+			cleanup.Cleanup(func() {
+				testArtifactsMutex.Lock()
+				defer testArtifactsMutex.Unlock()
+				delete(testArtifacts, key)
+			})
+		}
+	}
+	return state
+}
+
+// testArtifactsKey returns the key of the given test in testArtifacts: the testing.TestingT itself, or its name if it
+// can not be used as a map key.
+func testArtifactsKey(t testing.TestingT, rootDir string) interface{} {
+	type key struct {
+		test    interface{}
+		rootDir string
+	}
+	if reflect.TypeOf(t).Comparable() {
+		return key{test: t, rootDir: rootDir}
+	}
+	return key{test: t.Name(), rootDir: rootDir}
+}
+
+// claimArtifactsDir returns the directory under the given root directory for the test with the given name: its
+// sanitized name, with a numeric suffix if another test already uses that directory.
+func claimArtifactsDir(rootDir string, testName string) string {
+	base := filepath.Join(rootDir, unsafeFileNameChars.ReplaceAllString(testName, "_"))
+	dir := base
+	for i := 2; ; i++ {
+		owner, claimed := artifactDirOwners[dir]
+		if !claimed || owner == testName {
+			artifactDirOwners[dir] = testName
+			return dir
+		}
+		dir = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// commandArtifacts writes the output and metadata of a single command run to the artifacts directory of the test.
+// The files are named <dir>/<test name>/<NNN>-<command>.{stdout.log,stderr.log,json}, where NNN counts the commands
+// run by the test, so that reruns of the same test produce the same file names. The directory of the test is named as
+// described in TestArtifactsDir. A nil commandArtifacts, which is what
+// newCommandArtifacts returns when capturing is disabled, captures nothing.
+type commandArtifacts struct {
+	t        testing.TestingT
+	command  Command
+	metadata CommandArtifact
+	dir      string
+	prefix   string
+	stdout   *os.File
+	stderr   *os.File
+}
+
+// artifactsDir returns the directory to capture the output of the given command to, or an empty string if capturing
+// is disabled.
+func artifactsDir(command Command) string {
+	if command.ArtifactsDir != "" {
+		return command.ArtifactsDir
+	}
+	return os.Getenv(ArtifactsDirEnvVar)
+}
+
+func newCommandArtifacts(t testing.TestingT, command Command) *commandArtifacts {
+	rootDir := artifactsDir(command)
+	if rootDir == "" {
+		return nil
+	}
+
+	testDir, count := nextTestArtifact(t, rootDir)
+	artifacts := &commandArtifacts{
+		t:       t,
+		command: command,
+		dir:     testDir,
+		prefix:  fmt.Sprintf("%03d-%s", count, unsafeFileNameChars.ReplaceAllString(filepath.Base(command.Command), "_")),
+		metadata: CommandArtifact{
+			Command:    command.Command,
+			Args:       redactAll(command.Args),
+			WorkingDir: command.WorkingDir,
+			Env:        redactValues(command.Env),
+			StartTime:  time.Now(),
+		},
+	}
+	artifacts.metadata.Stdout = artifacts.prefix + ".stdout.log"
+	artifacts.metadata.Stderr = artifacts.prefix + ".stderr.log"
+
+	if err := os.MkdirAll(artifacts.dir, os.ModePerm); err != nil {
+		artifacts.logError(err)
+		return nil
+	}
+
+	var err error
+	if artifacts.stdout, err = os.Create(filepath.Join(artifacts.dir, artifacts.metadata.Stdout)); err != nil {
+		artifacts.logError(err)
+		return nil
+	}
+	if artifacts.stderr, err = os.Create(filepath.Join(artifacts.dir, artifacts.metadata.Stderr)); err != nil {
+		artifacts.stdout.Close()
+		artifacts.logError(err)
+		return nil
+	}
+
+	return artifacts
+}
+
+// tee returns a writer that writes each line both to the given writer and to the artifact file of the given stream.
+func (artifacts *commandArtifacts) tee(stream string, writer io.StringWriter) io.StringWriter {
+	if artifacts == nil {
+		return writer
+	}
+
+	file := artifacts.stdout
+	if stream == StreamStderr {
+		file = artifacts.stderr
+	}
+	return &teeLineWriter{writer: writer, file: file, redactor: &logger.StreamRedactor{}}
+}
+
+// finish closes the output files and writes the metadata file of the command, which ended with the given error.
+func (artifacts *commandArtifacts) finish(err error) {
+	if artifacts == nil {
+		return
+	}
+
+	artifacts.stdout.Close()
+	artifacts.stderr.Close()
+
+	artifacts.metadata.EndTime = time.Now()
+	artifacts.metadata.Duration = artifacts.metadata.EndTime.Sub(artifacts.metadata.StartTime)
+	if err != nil {
+		artifacts.metadata.Error = logger.Redact(err.Error())
+		artifacts.metadata.ExitCode, _ = GetExitCodeForRunCommandError(err)
+		if artifacts.metadata.ExitCode == 0 {
+			// The command failed without an exit code, e.g. because it could not be started or was killed
+			artifacts.metadata.ExitCode = -1
+		}
+	}
+
+	metadata, jsonErr := json.MarshalIndent(artifacts.metadata, "", "  ")
+	if jsonErr != nil {
+		artifacts.logError(jsonErr)
+		return
+	}
+	if writeErr := os.WriteFile(filepath.Join(artifacts.dir, artifacts.prefix+".json"), metadata, 0644); writeErr != nil {
+		artifacts.logError(writeErr)
+	}
+}
+
+// logError logs a failure to capture the artifacts of a command. Such failures never fail the command itself.
+func (artifacts *commandArtifacts) logError(err error) {
+	artifacts.command.Logger.Logf(artifacts.t, "Failed to capture the output of command %s to %s: %v", artifacts.command.Command, artifacts.dir, err)
+}
+
+// teeLineWriter writes lines to the output of a command and to an artifact file. Like the log, the artifact file has
+// its secrets redacted; the output of the command is left as is.
+type teeLineWriter struct {
+	writer   io.StringWriter
+	file     *os.File
+	redactor *logger.StreamRedactor
+}
+
+func (tee *teeLineWriter) WriteString(s string) (int, error) {
+	// Errors writing the artifact are ignored on purpose: they must not fail the command
+	_, _ = tee.file.WriteString(tee.redactor.RedactLine(s) + "\n")
+	return tee.writer.WriteString(s)
+}
+
+// redactAll returns the given strings with their secrets redacted.
+func redactAll(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	redacted := make([]string, len(values))
+	for i, value := range values {
+		redacted[i] = logger.Redact(value)
+	}
+	return redacted
+}
+
+// redactValues returns the given map with the secrets in its values redacted.
+func redactValues(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(values))
+	for key, value := range values {
+		redacted[key] = logger.Redact(value)
+	}
+	return redacted
+}
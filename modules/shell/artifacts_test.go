@@ -0,0 +1,100 @@
+package shell
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+)
+
+func TestRunCommandCapturesArtifacts(t *testing.T) {
+	t.Parallel()
+
+	artifactsDir := t.TempDir()
+	command := Command{
+		Command:      "bash",
+		Args:         []string{"-c", `echo "out $GREETING"; echo err >&2; exit 2`},
+		Env:          map[string]string{"GREETING": "hello"},
+		WorkingDir:   artifactsDir,
+		ArtifactsDir: artifactsDir,
+		Logger:       logger.Discard,
+	}
+
+	_, err := RunCommandAndGetOutputE(t, command)
+	require.Error(t, err)
+	RunCommand(t, Command{Command: "true", ArtifactsDir: artifactsDir, Logger: logger.Discard})
+
+	testDir := filepath.Join(artifactsDir, "TestRunCommandCapturesArtifacts")
+
+	stdout, err := os.ReadFile(filepath.Join(testDir, "001-bash.stdout.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "out hello\n", string(stdout))
+
+	stderr, err := os.ReadFile(filepath.Join(testDir, "001-bash.stderr.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "err\n", string(stderr))
+
+	metadataJSON, err := os.ReadFile(filepath.Join(testDir, "001-bash.json"))
+	require.NoError(t, err)
+	var metadata CommandArtifact
+	require.NoError(t, json.Unmarshal(metadataJSON, &metadata))
+	assert.Equal(t, "bash", metadata.Command)
+	assert.Equal(t, command.Args, metadata.Args)
+	assert.Equal(t, command.Env, metadata.Env)
+	assert.Equal(t, artifactsDir, metadata.WorkingDir)
+	assert.Equal(t, 2, metadata.ExitCode)
+	assert.NotEmpty(t, metadata.Error)
+	assert.False(t, metadata.EndTime.Before(metadata.StartTime))
+
+	assert.FileExists(t, filepath.Join(testDir, "002-true.json"))
+}
+
+// namedT is a testing.T with another name, to run several tests with the same name in a single test.
+type namedT struct {
+	*testing.T
+	name string
+}
+
+func (t *namedT) Name() string {
+	return t.name
+}
+
+func TestRunCommandArtifactsNumberingIsPerTestRun(t *testing.T) {
+	t.Parallel()
+
+	artifactsDir := t.TempDir()
+	command := Command{Command: "true", ArtifactsDir: artifactsDir, Logger: logger.Discard}
+
+	// Run the same test twice, like go test -count=2 does: both runs number their commands from 001
+	for i := 0; i < 2; i++ {
+		RunCommand(&namedT{T: t, name: "TestRerun"}, command)
+	}
+	assert.FileExists(t, filepath.Join(artifactsDir, "TestRerun", "001-true.json"))
+	assert.NoFileExists(t, filepath.Join(artifactsDir, "TestRerun", "002-true.json"))
+
+	// The names of these tests are the same once sanitized
+	RunCommand(&namedT{T: t, name: "TestFoo/a:b"}, command)
+	RunCommand(&namedT{T: t, name: "TestFoo/a?b"}, command)
+	assert.FileExists(t, filepath.Join(artifactsDir, "TestFoo_a_b", "001-true.json"))
+	assert.FileExists(t, filepath.Join(artifactsDir, "TestFoo_a_b-2", "001-true.json"))
+}
+
+func TestTestArtifactsDirIsSharedWithCommands(t *testing.T) {
+	artifactsDir := t.TempDir()
+	t.Setenv(ArtifactsDirEnvVar, artifactsDir)
+
+	RunCommand(t, Command{Command: "true", Logger: logger.Discard})
+	assert.Equal(t, filepath.Join(artifactsDir, "TestTestArtifactsDirIsSharedWithCommands"), TestArtifactsDir(t))
+	assert.FileExists(t, filepath.Join(TestArtifactsDir(t), "001-true.json"))
+
+	t.Setenv(ArtifactsDirEnvVar, "")
+	assert.Empty(t, TestArtifactsDir(t))
+
+	otherDir := t.TempDir()
+	assert.Equal(t, filepath.Join(otherDir, "TestTestArtifactsDirIsSharedWithCommands"), TestArtifactsDirIn(t, otherDir))
+}
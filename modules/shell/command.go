@@ -2,6 +2,7 @@ package shell
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
@@ -26,8 +28,24 @@ type Command struct {
 	Logger *logger.Logger
 
 	Stdin io.Reader
+
+	// Timeout, if greater than zero, is the maximum amount of time the command may run before it is stopped.
+	Timeout time.Duration
+	// GracePeriod is how long to wait for a command that timed out or whose context was cancelled to exit after
+	// each stop signal before escalating to the next one: first SIGINT (which e.g. lets Terraform release its state
+	// locks), then SIGTERM and finally SIGKILL. The signals are sent to the whole process group of the command, so
+	// that any processes it spawned are stopped as well. Defaults to DefaultGracePeriod.
+	GracePeriod time.Duration
+	// ArtifactsDir, if set, is the directory to capture the output of the command to. The stdout, stderr and metadata
+	// (args, env, working dir, exit code and timings) of the command are written to files in a subdirectory named after
+	// the test. If not set, the directory in the TERRATEST_ARTIFACTS_DIR environment variable (see ArtifactsDirEnvVar)
+	// is used, and if that is not set either, no output is captured.
+	ArtifactsDir string
 }
 
+// DefaultGracePeriod is the GracePeriod used for commands that don't set one.
+const DefaultGracePeriod = 10 * time.Second
+
 // RunCommand runs a shell command and redirects its stdout and stderr to the stdout of the atomic script itself. If
 // there are any errors, fail the test.
 func RunCommand(t testing.TestingT, command Command) {
@@ -38,7 +56,7 @@ func RunCommand(t testing.TestingT, command Command) {
 // RunCommandE runs a shell command and redirects its stdout and stderr to the stdout of the atomic script itself. Any
 // returned error will be of type ErrWithCmdOutput, containing the output streams and the underlying error.
 func RunCommandE(t testing.TestingT, command Command) error {
-	output, err := runCommand(t, command)
+	output, err := runCommand(t, context.Background(), command)
 	if err != nil {
 		return &ErrWithCmdOutput{err, output}
 	}
@@ -57,7 +75,7 @@ func RunCommandAndGetOutput(t testing.TestingT, command Command) string {
 // that command will also be logged with Command.Log to make debugging easier. Any returned error will be of type
 // ErrWithCmdOutput, containing the output streams and the underlying error.
 func RunCommandAndGetOutputE(t testing.TestingT, command Command) (string, error) {
-	output, err := runCommand(t, command)
+	output, err := runCommand(t, context.Background(), command)
 	if err != nil {
 		return output.Combined(), &ErrWithCmdOutput{err, output}
 	}
@@ -78,7 +96,7 @@ func RunCommandAndGetStdOut(t testing.TestingT, command Command) string {
 // and stderr of that command will also be printed to the stdout and stderr of this Go program to make debugging easier.
 // Any returned error will be of type ErrWithCmdOutput, containing the output streams and the underlying error.
 func RunCommandAndGetStdOutE(t testing.TestingT, command Command) (string, error) {
-	output, err := runCommand(t, command)
+	output, err := runCommand(t, context.Background(), command)
 	if err != nil {
 		return output.Stdout(), &ErrWithCmdOutput{err, output}
 	}
@@ -99,7 +117,84 @@ func RunCommandAndGetStdOutErr(t testing.TestingT, command Command) (stdout stri
 // and stderr of that command will also be printed to the stdout and stderr of this Go program to make debugging easier.
 // Any returned error will be of type ErrWithCmdOutput, containing the output streams and the underlying error.
 func RunCommandAndGetStdOutErrE(t testing.TestingT, command Command) (stdout string, stderr string, err error) {
-	output, err := runCommand(t, command)
+	output, err := runCommand(t, context.Background(), command)
+	if err != nil {
+		return output.Stdout(), output.Stderr(), &ErrWithCmdOutput{err, output}
+	}
+
+	return output.Stdout(), output.Stderr(), nil
+}
+
+// RunCommandContext runs a shell command like RunCommand, but stops it as soon as the given context is done. If there
+// are any errors, fail the test.
+func RunCommandContext(t testing.TestingT, ctx context.Context, command Command) {
+	err := RunCommandContextE(t, ctx, command)
+	require.NoError(t, err)
+}
+
+// RunCommandContextE runs a shell command like RunCommandE, but stops it as soon as the given context is done. Any
+// returned error will be of type ErrWithCmdOutput, containing the output streams and the underlying error.
+func RunCommandContextE(t testing.TestingT, ctx context.Context, command Command) error {
+	output, err := runCommand(t, ctx, command)
+	if err != nil {
+		return &ErrWithCmdOutput{err, output}
+	}
+	return nil
+}
+
+// RunCommandAndGetOutputContext runs a shell command like RunCommandAndGetOutput, but stops it as soon as the given
+// context is done. If there are any errors, fail the test.
+func RunCommandAndGetOutputContext(t testing.TestingT, ctx context.Context, command Command) string {
+	out, err := RunCommandAndGetOutputContextE(t, ctx, command)
+	require.NoError(t, err)
+	return out
+}
+
+// RunCommandAndGetOutputContextE runs a shell command like RunCommandAndGetOutputE, but stops it as soon as the given
+// context is done. Any returned error will be of type ErrWithCmdOutput, containing the output streams and the
+// underlying error.
+func RunCommandAndGetOutputContextE(t testing.TestingT, ctx context.Context, command Command) (string, error) {
+	output, err := runCommand(t, ctx, command)
+	if err != nil {
+		return output.Combined(), &ErrWithCmdOutput{err, output}
+	}
+
+	return output.Combined(), nil
+}
+
+// RunCommandAndGetStdOutContext runs a shell command like RunCommandAndGetStdOut, but stops it as soon as the given
+// context is done. If there are any errors, fail the test.
+func RunCommandAndGetStdOutContext(t testing.TestingT, ctx context.Context, command Command) string {
+	output, err := RunCommandAndGetStdOutContextE(t, ctx, command)
+	require.NoError(t, err)
+	return output
+}
+
+// RunCommandAndGetStdOutContextE runs a shell command like RunCommandAndGetStdOutE, but stops it as soon as the given
+// context is done. Any returned error will be of type ErrWithCmdOutput, containing the output streams and the
+// underlying error.
+func RunCommandAndGetStdOutContextE(t testing.TestingT, ctx context.Context, command Command) (string, error) {
+	output, err := runCommand(t, ctx, command)
+	if err != nil {
+		return output.Stdout(), &ErrWithCmdOutput{err, output}
+	}
+
+	return output.Stdout(), nil
+}
+
+// RunCommandAndGetStdOutErrContext runs a shell command like RunCommandAndGetStdOutErr, but stops it as soon as the
+// given context is done. If there are any errors, fail the test.
+func RunCommandAndGetStdOutErrContext(t testing.TestingT, ctx context.Context, command Command) (stdout string, stderr string) {
+	stdout, stderr, err := RunCommandAndGetStdOutErrContextE(t, ctx, command)
+	require.NoError(t, err)
+	return stdout, stderr
+}
+
+// RunCommandAndGetStdOutErrContextE runs a shell command like RunCommandAndGetStdOutErrE, but stops it as soon as the
+// given context is done. Any returned error will be of type ErrWithCmdOutput, containing the output streams and the
+// underlying error.
+func RunCommandAndGetStdOutErrContextE(t testing.TestingT, ctx context.Context, command Command) (stdout string, stderr string, err error) {
+	output, err := runCommand(t, ctx, command)
 	if err != nil {
 		return output.Stdout(), output.Stderr(), &ErrWithCmdOutput{err, output}
 	}
@@ -116,11 +211,44 @@ func (e *ErrWithCmdOutput) Error() string {
 	return fmt.Sprintf("error while running command: %v; %s", e.Underlying, e.Output.Stderr())
 }
 
+// Unwrap returns the underlying error, so that errors.Is and errors.As can look through ErrWithCmdOutput.
+func (e *ErrWithCmdOutput) Unwrap() error {
+	return e.Underlying
+}
+
+// CommandStopped is an error that occurs when a command is stopped because its Timeout passed or its context was
+// done.
+type CommandStopped struct {
+	Command string
+	// Cause is the reason the command was stopped, e.g. context.DeadlineExceeded or context.Canceled.
+	Cause error
+	// Underlying is the error returned when waiting for the stopped command to exit.
+	Underlying error
+}
+
+func (e CommandStopped) Error() string {
+	return fmt.Sprintf("command %s was stopped (%v): %v", e.Command, e.Cause, e.Underlying)
+}
+
+// Unwrap returns both the cause and the underlying error, so that e.g. errors.Is(err, context.DeadlineExceeded)
+// works on a CommandStopped.
+func (e CommandStopped) Unwrap() []error {
+	return []error{e.Cause, e.Underlying}
+}
+
 // runCommand runs a shell command and stores each line from stdout and stderr in Output. Depending on the logger, the
 // stdout and stderr of that command will also be printed to the stdout and stderr of this Go program to make debugging
-// easier.
-func runCommand(t testing.TestingT, command Command) (*output, error) {
-	command.Logger.Logf(t, "Running command %s with args %s", command.Command, command.Args)
+// easier. If the command has a Timeout or the given context can be cancelled, the command is run in its own process
+// group, which is stopped when the timeout passes or the context is done.
+func runCommand(t testing.TestingT, ctx context.Context, command Command) (*output, error) {
+	log := commandLogger(command)
+	log.Logf(t, "Running command %s with args %s", command.Command, command.Args)
+
+	if command.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, command.Timeout)
+		defer cancel()
+	}
 
 	cmd := exec.Command(command.Command, command.Args...)
 	cmd.Dir = command.WorkingDir
@@ -131,6 +259,11 @@ func runCommand(t testing.TestingT, command Command) (*output, error) {
 	}
 	cmd.Env = formatEnvVars(command)
 
+	stoppable := ctx.Done() != nil
+	if stoppable {
+		setProcessGroup(cmd)
+	}
+
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, err
@@ -141,22 +274,81 @@ func runCommand(t testing.TestingT, command Command) (*output, error) {
 		return nil, err
 	}
 
+	artifacts := newCommandArtifacts(t, command)
 	err = cmd.Start()
 	if err != nil {
+		artifacts.finish(err)
 		return nil, err
 	}
 
-	output, err := readStdoutAndStderr(t, command.Logger, stdout, stderr)
+	exited := make(chan struct{})
+	stopped := make(chan bool, 1)
+	if stoppable {
+		go func() {
+			stopped <- stopOnDone(t, ctx, command, cmd.Process, exited)
+		}()
+	} else {
+		stopped <- false
+	}
+
+	output, err := readStdoutAndStderr(t, log, artifacts, stdout, stderr)
 	if err != nil {
+		close(exited)
+		artifacts.finish(err)
 		return output, err
 	}
 
-	return output, cmd.Wait()
+	err = cmd.Wait()
+	close(exited)
+	if <-stopped {
+		err = CommandStopped{Command: command.Command, Cause: ctx.Err(), Underlying: err}
+	}
+	if err != nil {
+		log.Errorf(t, "Command %s failed: %v", command.Command, err)
+	}
+	artifacts.finish(err)
+	return output, err
+}
+
+// commandLogger returns the logger of the given command, with fields that identify the command.
+func commandLogger(command Command) *logger.Logger {
+	return command.Logger.With("module", "shell", "command", command.Command)
+}
+
+// stopOnDone waits until either the given process exits or the context is done. In the latter case, it stops the
+// process group of the process by sending it SIGINT, SIGTERM and SIGKILL in turn, waiting up to the grace period of the
+// command for the process to exit after each signal. It returns true if it had to stop the process.
+func stopOnDone(t testing.TestingT, ctx context.Context, command Command, process *os.Process, exited <-chan struct{}) bool {
+	select {
+	case <-exited:
+		return false
+	case <-ctx.Done():
+	}
+
+	gracePeriod := command.GracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultGracePeriod
+	}
+
+	log := commandLogger(command)
+	for _, sig := range stopSignals {
+		log.Warnf(t, "Stopping command %s (%v): sending %s to process group %d.", command.Command, ctx.Err(), sig, process.Pid)
+		if err := signalProcessGroup(process, sig); err != nil {
+			log.Errorf(t, "Failed to send %s to process group %d: %v", sig, process.Pid, err)
+		}
+
+		select {
+		case <-exited:
+			return true
+		case <-time.After(gracePeriod):
+		}
+	}
+	return true
 }
 
 // This function captures stdout and stderr into the given variables while still printing it to the stdout and stderr
-// of this Go program
-func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, stdout, stderr io.ReadCloser) (*output, error) {
+// of this Go program, and to the artifact files of the command, if any.
+func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, artifacts *commandArtifacts, stdout, stderr io.ReadCloser) (*output, error) {
 	out := newOutput()
 	stdoutReader := bufio.NewReader(stdout)
 	stderrReader := bufio.NewReader(stderr)
@@ -167,11 +359,11 @@ func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, stdout, stderr
 	var stdoutErr, stderrErr error
 	go func() {
 		defer wg.Done()
-		stdoutErr = readData(t, log, stdoutReader, out.stdout)
+		stdoutErr = readData(t, log, stdoutReader, artifacts.tee(StreamStdout, out.stdout))
 	}()
 	go func() {
 		defer wg.Done()
-		stderrErr = readData(t, log, stderrReader, out.stderr)
+		stderrErr = readData(t, log, stderrReader, artifacts.tee(StreamStderr, out.stderr))
 	}()
 	wg.Wait()
 
@@ -188,6 +380,8 @@ func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, stdout, stderr
 func readData(t testing.TestingT, log *logger.Logger, reader *bufio.Reader, writer io.StringWriter) error {
 	var line string
 	var readErr error
+	// The output is logged line by line, so secrets that span multiple lines can only be redacted by tracking them
+	redactor := &logger.StreamRedactor{}
 	for {
 		line, readErr = reader.ReadString('\n')
 
@@ -209,7 +403,7 @@ func readData(t testing.TestingT, log *logger.Logger, reader *bufio.Reader, writ
 		// the line.
 		//
 		// See https://github.com/gruntwork-io/terratest/issues/982.
-		log.Logf(t, "%s", line)
+		log.Logf(t, "%s", redactor.RedactLine(line))
 
 		if _, err := writer.WriteString(line); err != nil {
 			return err
@@ -233,7 +427,8 @@ func GetExitCodeForRunCommandError(err error) (int, error) {
 	}
 
 	// http://stackoverflow.com/a/10385867/483528
-	if exitErr, ok := err.(*exec.ExitError); ok {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
 		// The program has exited with an exit code != 0
 
 		// This works on both Unix and Windows. Although package
@@ -2,10 +2,12 @@ package shell
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -223,3 +225,67 @@ func TestRunCommandWithStdinAndGetOutput(t *testing.T) {
 	out := RunCommandAndGetOutput(t, cmd)
 	assert.Equal(t, text, strings.TrimSpace(out))
 }
+
+func TestRunCommandWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	cmd := Command{
+		Command: "bash",
+		Args:    []string{"-c", "echo started; sleep 60"},
+		Timeout: 500 * time.Millisecond,
+		Logger:  logger.Discard,
+	}
+
+	start := time.Now()
+	out, err := RunCommandAndGetOutputE(t, cmd)
+	assert.Less(t, time.Since(start), 30*time.Second)
+	assert.Equal(t, "started", out)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var stopped CommandStopped
+	assert.ErrorAs(t, err, &stopped)
+}
+
+func TestRunCommandContextSendsSigintFirst(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(500*time.Millisecond, cancel)
+
+	// Exits cleanly on SIGINT, the way terraform does after releasing its state lock.
+	bashCode := `trap 'echo interrupted; exit 3' INT; echo started; while true; do sleep 0.1; done`
+	cmd := Command{
+		Command: "bash",
+		Args:    []string{"-c", bashCode},
+		Logger:  logger.Discard,
+	}
+
+	out, err := RunCommandAndGetOutputContextE(t, ctx, cmd)
+	assert.Equal(t, "started\ninterrupted", out)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	code, err := GetExitCodeForRunCommandError(err)
+	require.NoError(t, err)
+	assert.Equal(t, 3, code)
+}
+
+func TestRunCommandWithTimeoutEscalatesAndKillsChildren(t *testing.T) {
+	t.Parallel()
+
+	// The background child ignores SIGINT and SIGTERM, so only SIGKILL sent to the whole process group stops it. If
+	// the child survived, it would keep stdout open and the command would never return.
+	bashCode := `trap '' INT TERM; (trap '' INT TERM; sleep 60) & echo started; wait`
+	cmd := Command{
+		Command:     "bash",
+		Args:        []string{"-c", bashCode},
+		Timeout:     200 * time.Millisecond,
+		GracePeriod: 200 * time.Millisecond,
+		Logger:      logger.Discard,
+	}
+
+	start := time.Now()
+	out, err := RunCommandAndGetOutputE(t, cmd)
+	assert.Less(t, time.Since(start), 30*time.Second)
+	assert.Equal(t, "started", out)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
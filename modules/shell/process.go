@@ -0,0 +1,423 @@
+package shell
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+const (
+	// StreamStdout marks a Line that the process wrote to stdout.
+	StreamStdout = "stdout"
+	// StreamStderr marks a Line that the process wrote to stderr.
+	StreamStderr = "stderr"
+)
+
+// Line is a single line of output of a background Process.
+type Line struct {
+	Stream string // Either StreamStdout or StreamStderr
+	Text   string
+}
+
+// Process is a command running in the background, started with Start. Its stdout and stderr are captured, and logged
+// with the Logger of the command, while it runs.
+type Process struct {
+	command Command
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	output  *output
+	cancel  context.CancelFunc
+
+	mutex sync.Mutex
+	// newLine is broadcast whenever a line is appended to lines and when the process exits.
+	newLine *sync.Cond
+	lines   []Line
+	exited  bool
+	err     error
+
+	done chan struct{}
+}
+
+// cleaner is implemented by Go's testing.T and allows registering a function to run when the test finishes.
+type cleaner interface {
+	Cleanup(func())
+}
+
+// Start starts the given command in the background and returns a handle to interact with it. If the given
+// testing.TestingT supports Cleanup (as Go's testing.T does), the process is stopped when the test finishes. If there
+// are any errors starting the process, fail the test.
+func Start(t testing.TestingT, command Command) *Process {
+	process, err := StartE(t, command)
+	require.NoError(t, err)
+	return process
+}
+
+// StartE starts the given command in the background and returns a handle to interact with it. If the given
+// testing.TestingT supports Cleanup (as Go's testing.T does), the process is stopped when the test finishes.
+func StartE(t testing.TestingT, command Command) (*Process, error) {
+	return StartContextE(t, context.Background(), command)
+}
+
+// StartContext is like Start, but also stops the process as soon as the given context is done.
+func StartContext(t testing.TestingT, ctx context.Context, command Command) *Process {
+	process, err := StartContextE(t, ctx, command)
+	require.NoError(t, err)
+	return process
+}
+
+// StartContextE is like StartE, but also stops the process as soon as the given context is done.
+//
+// The process runs in its own process group. When it is stopped, because its Timeout passed, the context is done, the
+// test finished or Stop was called, SIGINT, SIGTERM and SIGKILL are sent in turn to the whole group, waiting up to the
+// GracePeriod of the command after each one.
+func StartContextE(t testing.TestingT, ctx context.Context, command Command) (*Process, error) {
+	commandLogger(command).Logf(t, "Starting command %s with args %s in the background", command.Command, command.Args)
+
+	ctx, cancel := context.WithCancel(ctx)
+	if command.Timeout > 0 {
+		ctx, cancel = withTimeoutAndCancel(ctx, cancel, command.Timeout)
+	}
+
+	cmd := exec.Command(command.Command, command.Args...)
+	cmd.Dir = command.WorkingDir
+	cmd.Env = formatEnvVars(command)
+	setProcessGroup(cmd)
+
+	process := &Process{
+		command: command,
+		cmd:     cmd,
+		output:  newOutput(),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	process.newLine = sync.NewCond(&process.mutex)
+
+	var err error
+	if command.Stdin != nil {
+		cmd.Stdin = command.Stdin
+	} else if process.stdin, err = cmd.StdinPipe(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	artifacts := newCommandArtifacts(t, command)
+	if err := cmd.Start(); err != nil {
+		artifacts.finish(err)
+		cancel()
+		return nil, err
+	}
+
+	go process.run(t, ctx, artifacts, stdout, stderr)
+
+	if tt, ok := t.(cleaner); ok {
+		tt.Cleanup(func() {
+			process.Stop(t)
+		})
+	}
+
+	return process, nil
+}
+
+// withTimeoutAndCancel derives a context with the given timeout, whose cancel function also calls the given one.
+func withTimeoutAndCancel(ctx context.Context, cancel context.CancelFunc, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancelTimeout()
+		cancel()
+	}
+}
+
+// run captures the output of the process until it exits, stopping it if the context is done first.
+func (process *Process) run(t testing.TestingT, ctx context.Context, artifacts *commandArtifacts, stdout, stderr io.ReadCloser) {
+	exited := make(chan struct{})
+	stopped := make(chan bool, 1)
+	go func() {
+		stopped <- stopOnDone(t, ctx, process.command, process.cmd.Process, exited)
+	}()
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	var stdoutErr, stderrErr error
+	go func() {
+		defer wg.Done()
+		stdoutErr = readData(t, commandLogger(process.command), bufio.NewReader(stdout), artifacts.tee(StreamStdout, &lineWriter{process, StreamStdout, process.output.stdout}))
+	}()
+	go func() {
+		defer wg.Done()
+		stderrErr = readData(t, commandLogger(process.command), bufio.NewReader(stderr), artifacts.tee(StreamStderr, &lineWriter{process, StreamStderr, process.output.stderr}))
+	}()
+	wg.Wait()
+
+	err := process.cmd.Wait()
+	close(exited)
+	if <-stopped {
+		err = CommandStopped{Command: process.command.Command, Cause: ctx.Err(), Underlying: err}
+	}
+	if err == nil {
+		err = errors.Join(stdoutErr, stderrErr)
+	}
+	artifacts.finish(err)
+	process.cancel()
+
+	process.mutex.Lock()
+	process.exited = true
+	if err != nil {
+		process.err = &ErrWithCmdOutput{err, process.output}
+	}
+	process.newLine.Broadcast()
+	process.mutex.Unlock()
+
+	close(process.done)
+}
+
+// lineWriter records every line read from one of the output streams of a process, both in the output object and in
+// the list of lines that Follow and WaitForOutput look at.
+type lineWriter struct {
+	process *Process
+	stream  string
+	out     *outputStream
+}
+
+func (writer *lineWriter) WriteString(s string) (int, error) {
+	writer.process.mutex.Lock()
+	defer writer.process.mutex.Unlock()
+
+	writer.process.lines = append(writer.process.lines, Line{Stream: writer.stream, Text: s})
+	writer.process.newLine.Broadcast()
+	return writer.out.WriteString(s)
+}
+
+// Pid returns the process ID of the process.
+func (process *Process) Pid() int {
+	return process.cmd.Process.Pid
+}
+
+// Done returns a channel that is closed when the process has exited and all of its output has been read.
+func (process *Process) Done() <-chan struct{} {
+	return process.done
+}
+
+// Lines returns a copy of all the lines the process has written so far, from stdout and stderr, in the order they
+// were read.
+func (process *Process) Lines() []Line {
+	process.mutex.Lock()
+	defer process.mutex.Unlock()
+
+	lines := make([]Line, len(process.lines))
+	copy(lines, process.lines)
+	return lines
+}
+
+// Follow returns a channel that receives every line the process writes to stdout or stderr from now on. The channel
+// is closed once the process has exited and all of its output was delivered, or once the given context is done. Lines
+// are buffered for as long as needed, so a slow reader never blocks the process. A reader that stops reading before
+// the channel is closed, e.g. after the first matching line, must cancel the context, or the goroutine that feeds the
+// channel leaks.
+func (process *Process) Follow(ctx context.Context) <-chan Line {
+	process.mutex.Lock()
+	next := len(process.lines)
+	process.mutex.Unlock()
+
+	lines := make(chan Line)
+	go func() {
+		defer close(lines)
+
+		// Wake up linesAfter when the context is done, as it waits for new lines
+		stop := context.AfterFunc(ctx, func() {
+			process.mutex.Lock()
+			defer process.mutex.Unlock()
+			process.newLine.Broadcast()
+		})
+		defer stop()
+
+		for {
+			pending, exited := process.linesAfter(ctx, next)
+			for _, line := range pending {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			next += len(pending)
+			if ctx.Err() != nil || (exited && len(pending) == 0) {
+				return
+			}
+		}
+	}()
+	return lines
+}
+
+// linesAfter blocks until the process has written more than the given number of lines or has exited, or the given
+// context is done, and then returns the lines after the given index and whether the process exited.
+func (process *Process) linesAfter(ctx context.Context, index int) ([]Line, bool) {
+	process.mutex.Lock()
+	defer process.mutex.Unlock()
+
+	for len(process.lines) <= index && !process.exited && ctx.Err() == nil {
+		process.newLine.Wait()
+	}
+
+	pending := make([]Line, len(process.lines)-index)
+	copy(pending, process.lines[index:])
+	return pending, process.exited
+}
+
+// WaitForOutput waits until the process writes a line, to stdout or stderr, that matches the given regular
+// expression, and returns that line. Lines written before WaitForOutput was called count as well. This is typically
+// used to wait for a server to report that it is ready. This will fail the test if no such line appears before the
+// timeout passes or the process exits.
+func (process *Process) WaitForOutput(t testing.TestingT, regex string, timeout time.Duration) string {
+	line, err := process.WaitForOutputE(t, regex, timeout)
+	require.NoError(t, err)
+	return line
+}
+
+// WaitForOutputE waits until the process writes a line, to stdout or stderr, that matches the given regular
+// expression, and returns that line. Lines written before WaitForOutputE was called count as well. If no such line
+// appears before the timeout passes or the process exits, return an OutputNotFound error.
+func (process *Process) WaitForOutputE(t testing.TestingT, regex string, timeout time.Duration) (string, error) {
+	pattern, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+
+	process.command.Logger.Logf(t, "Waiting up to %s for command %s to output a line matching %s", timeout, process.command.Command, regex)
+
+	timedOut := false
+	timer := time.AfterFunc(timeout, func() {
+		process.mutex.Lock()
+		defer process.mutex.Unlock()
+		timedOut = true
+		process.newLine.Broadcast()
+	})
+	defer timer.Stop()
+
+	process.mutex.Lock()
+	defer process.mutex.Unlock()
+
+	for next := 0; ; {
+		for ; next < len(process.lines); next++ {
+			if pattern.MatchString(process.lines[next].Text) {
+				return process.lines[next].Text, nil
+			}
+		}
+		if process.exited || timedOut {
+			return "", OutputNotFound{Command: process.command.Command, Regex: regex, Timeout: timeout, Exited: process.exited}
+		}
+		process.newLine.Wait()
+	}
+}
+
+// WriteStdin writes the given input to the stdin of the process. This returns an error if the Stdin of the command was
+// set, as the process then reads from that instead.
+func (process *Process) WriteStdin(input string) error {
+	if process.stdin == nil {
+		return errors.New("stdin of the process is not available, as the Stdin of the command was set")
+	}
+	_, err := io.WriteString(process.stdin, input)
+	return err
+}
+
+// CloseStdin closes the stdin of the process, signalling that there is no more input.
+func (process *Process) CloseStdin() error {
+	if process.stdin == nil {
+		return errors.New("stdin of the process is not available, as the Stdin of the command was set")
+	}
+	return process.stdin.Close()
+}
+
+// Signal sends the given signal to the process and all processes it started.
+func (process *Process) Signal(sig os.Signal) error {
+	return signalProcessGroup(process.cmd.Process, sig)
+}
+
+// Wait waits for the process to exit on its own. This will fail the test if the process exits with an error.
+func (process *Process) Wait(t testing.TestingT) {
+	require.NoError(t, process.WaitE(t))
+}
+
+// WaitE waits for the process to exit on its own. Any returned error will be of type ErrWithCmdOutput, containing the
+// output streams and the underlying error.
+func (process *Process) WaitE(t testing.TestingT) error {
+	<-process.done
+
+	process.mutex.Lock()
+	defer process.mutex.Unlock()
+	return process.err
+}
+
+// Stop stops the process, if it is still running, and waits for it to exit. The process is first sent SIGINT, then
+// SIGTERM and finally SIGKILL, waiting up to the GracePeriod of the command after each signal. It is safe to call Stop
+// more than once, and on a process that has already exited.
+func (process *Process) Stop(t testing.TestingT) {
+	process.cancel()
+	<-process.done
+}
+
+// Stdout returns the stdout of the process. While the process is running, this is the output written so far.
+func (process *Process) Stdout() string {
+	return process.joinLines(StreamStdout)
+}
+
+// Stderr returns the stderr of the process. While the process is running, this is the output written so far.
+func (process *Process) Stderr() string {
+	return process.joinLines(StreamStderr)
+}
+
+// Combined returns the stdout and stderr of the process, interleaved in the order they were read. While the process
+// is running, this is the output written so far.
+func (process *Process) Combined() string {
+	return process.joinLines("")
+}
+
+func (process *Process) joinLines(stream string) string {
+	var texts []string
+	for _, line := range process.Lines() {
+		if stream == "" || line.Stream == stream {
+			texts = append(texts, line.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// OutputNotFound is an error that occurs when a background process does not write a line matching the expected
+// regular expression.
+type OutputNotFound struct {
+	Command string
+	Regex   string
+	Timeout time.Duration
+	// Exited is true if the process exited before writing a matching line, and false if the timeout passed.
+	Exited bool
+}
+
+func (err OutputNotFound) Error() string {
+	if err.Exited {
+		return fmt.Sprintf("command %s exited without writing a line matching %s", err.Command, err.Regex)
+	}
+	return fmt.Sprintf("command %s did not write a line matching %s within %s", err.Command, err.Regex, err.Timeout)
+}
@@ -0,0 +1,118 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+)
+
+func TestStartAndWaitForOutput(t *testing.T) {
+	t.Parallel()
+
+	process := Start(t, Command{
+		Command: "bash",
+		Args:    []string{"-c", "echo starting; sleep 0.2; echo 'listening on port 8080' >&2; sleep 60"},
+		Logger:  logger.Discard,
+	})
+
+	line := process.WaitForOutput(t, `listening on port \d+`, 30*time.Second)
+	assert.Equal(t, "listening on port 8080", line)
+	assert.Equal(t, "starting", process.Stdout())
+	assert.Equal(t, "listening on port 8080", process.Stderr())
+
+	process.Stop(t)
+	err := process.WaitE(t)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStartWaitForOutputFailsWhenProcessExits(t *testing.T) {
+	t.Parallel()
+
+	process := Start(t, Command{
+		Command: "bash",
+		Args:    []string{"-c", "echo not ready; exit 1"},
+		Logger:  logger.Discard,
+	})
+
+	_, err := process.WaitForOutputE(t, "ready to serve", 30*time.Second)
+	require.Error(t, err)
+	assert.Equal(t, OutputNotFound{Command: "bash", Regex: "ready to serve", Timeout: 30 * time.Second, Exited: true}, err)
+
+	err = process.WaitE(t)
+	code, exitCodeErr := GetExitCodeForRunCommandError(err)
+	require.NoError(t, exitCodeErr)
+	assert.Equal(t, 1, code)
+}
+
+func TestStartWriteStdinAndFollow(t *testing.T) {
+	t.Parallel()
+
+	process := Start(t, Command{
+		Command: "cat",
+		Logger:  logger.Discard,
+	})
+	lines := process.Follow(context.Background())
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, process.WriteStdin(fmt.Sprintf("line %d\n", i)))
+	}
+	require.NoError(t, process.CloseStdin())
+
+	var received []string
+	for line := range lines {
+		assert.Equal(t, StreamStdout, line.Stream)
+		received = append(received, line.Text)
+	}
+
+	assert.Equal(t, []string{"line 0", "line 1", "line 2"}, received)
+	process.Wait(t)
+	assert.Equal(t, strings.Join(received, "\n"), process.Combined())
+}
+
+func TestFollowStopsWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	process := Start(t, Command{
+		Command: "cat",
+		Logger:  logger.Discard,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := process.Follow(ctx)
+
+	require.NoError(t, process.WriteStdin("line 0\nline 1\n"))
+	assert.Equal(t, "line 0", (<-lines).Text)
+
+	// Stop reading while the process is still running: the channel is closed rather than blocking forever
+	cancel()
+	for range lines {
+	}
+	require.NoError(t, process.CloseStdin())
+	process.Wait(t)
+}
+
+func TestStartIsStoppedOnCleanup(t *testing.T) {
+	t.Parallel()
+
+	var process *Process
+	t.Run("StartProcess", func(t *testing.T) {
+		process = Start(t, Command{
+			Command:     "sleep",
+			Args:        []string{"60"},
+			GracePeriod: 100 * time.Millisecond,
+			Logger:      logger.Discard,
+		})
+	})
+
+	select {
+	case <-process.Done():
+	case <-time.After(30 * time.Second):
+		t.Fatal("process was not stopped when the test that started it finished")
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// stopSignals are the signals sent, in order, to stop a command that timed out or whose context was cancelled.
+var stopSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL}
+
+// setProcessGroup makes the command the leader of a new process group, so that it and all of its children can be
+// signalled at once.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalProcessGroup sends the given signal to every process in the process group led by the given process.
+func signalProcessGroup(process *os.Process, sig os.Signal) error {
+	return syscall.Kill(-process.Pid, sig.(syscall.Signal))
+}
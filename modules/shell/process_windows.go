@@ -0,0 +1,24 @@
+//go:build windows
+// +build windows
+
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// stopSignals are the signals sent, in order, to stop a command that timed out or whose context was cancelled. Windows
+// has no way to deliver SIGINT or SIGTERM to another process, so the process is killed straight away.
+var stopSignals = []os.Signal{os.Kill}
+
+// setProcessGroup makes the command the root of a new process group.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// signalProcessGroup kills the given process. Windows does not support sending signals to process groups.
+func signalProcessGroup(process *os.Process, sig os.Signal) error {
+	return process.Kill()
+}
@@ -621,6 +621,7 @@ func createAuthMethodsForHost(host Host) ([]ssh.AuthMethod, error) {
 
 	// Use given password
 	if len(host.Password) > 0 {
+		logger.RegisterSecret(host.Password)
 		methods = append(methods, []ssh.AuthMethod{ssh.Password(host.Password)}...)
 	}
 
@@ -38,7 +38,11 @@ func Apply(t testing.TestingT, options *Options) string {
 // ApplyE runs terraform apply with the given options and return stdout/stderr. Note that this method does NOT call destroy and
 // assumes the caller is responsible for cleaning up any resources created by running apply.
 func ApplyE(t testing.TestingT, options *Options) (string, error) {
-	return RunTerraformCommandE(t, options, FormatArgs(options, prepend(options.ExtraArgs.Apply, "apply", "-input=false", "-auto-approve")...)...)
+	args, err := FormatArgsE(options, prepend(options.ExtraArgs.Apply, "apply", "-input=false", "-auto-approve")...)
+	if err != nil {
+		return "", err
+	}
+	return RunTerraformCommandE(t, options, args...)
 }
 
 // ApplyAndIdempotent runs terraform apply with the given options and return stdout/stderr from the apply command. It then runs
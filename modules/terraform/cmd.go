@@ -59,6 +59,8 @@ func GetCommonOptions(options *Options, args ...string) (*Options, []string) {
 		}
 		options.EnvVars["SSH_AUTH_SOCK"] = options.SshAgent.SocketFile()
 	}
+
+	registerSensitiveVars(options)
 	return options, args
 }
 
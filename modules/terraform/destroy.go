@@ -14,5 +14,9 @@ func Destroy(t testing.TestingT, options *Options) string {
 
 // DestroyE runs terraform destroy with the given options and return stdout/stderr.
 func DestroyE(t testing.TestingT, options *Options) (string, error) {
-	return RunTerraformCommandE(t, options, FormatArgs(options, prepend(options.ExtraArgs.Destroy, "destroy", "-auto-approve", "-input=false")...)...)
+	args, err := FormatArgsE(options, prepend(options.ExtraArgs.Destroy, "destroy", "-auto-approve", "-input=false")...)
+	if err != nil {
+		return "", err
+	}
+	return RunTerraformCommandE(t, options, args...)
 }
@@ -93,3 +93,16 @@ type WorkspaceDoesNotExist string
 func (err WorkspaceDoesNotExist) Error() string {
 	return fmt.Sprintf("The workspace %q does not exist.", string(err))
 }
+
+// UnexpectedAttributeType is an error that occurs when an attribute of a module file is not of the type Terraform
+// expects, e.g. a variable description that is not a string
+type UnexpectedAttributeType struct {
+	Name         string
+	ExpectedType string
+	ActualType   string
+	Pos          SourcePos
+}
+
+func (err UnexpectedAttributeType) Error() string {
+	return fmt.Sprintf("%s:%d: expected attribute '%s' to be of type '%s' but got '%s'", err.Pos.Filename, err.Pos.Line, err.Name, err.ExpectedType, err.ActualType)
+}
@@ -1,10 +1,10 @@
 package terraform
 
 import (
+	"crypto/sha256"
 	"fmt"
-	"reflect"
-	"strconv"
-	"strings"
+	"os"
+	"path/filepath"
 
 	"github.com/gruntwork-io/terratest/internal/lib/formatting"
 	"github.com/gruntwork-io/terratest/modules/collections"
@@ -33,8 +33,21 @@ var TerraformCommandsWithPlanFileSupport = []string{
 }
 
 // FormatArgs converts the inputs to a format palatable to terraform. This includes converting the given vars to the
-// format the Terraform CLI expects (-var key=value).
+// format the Terraform CLI expects (-var key=value). If VarsAsFile is set and the var file can not be written, the
+// variables are passed as -var arguments instead; use FormatArgsE to get the error.
 func FormatArgs(options *Options, args ...string) []string {
+	terraformArgs, err := FormatArgsE(options, args...)
+	if err != nil {
+		optionsWithoutVarFile := *options
+		optionsWithoutVarFile.VarsAsFile = false
+		terraformArgs, _ = FormatArgsE(&optionsWithoutVarFile, args...)
+	}
+	return terraformArgs
+}
+
+// FormatArgsE converts the inputs to a format palatable to terraform, like FormatArgs, but returns an error if
+// VarsAsFile is set and the var file can not be written.
+func FormatArgsE(options *Options, args ...string) ([]string, error) {
 	var terraformArgs []string
 	commandType := args[0]
 	lockSupported := collections.ListContains(TerraformCommandsWithLockSupport, commandType)
@@ -50,11 +63,15 @@ func FormatArgs(options *Options, args ...string) []string {
 			terraformArgs = append(terraformArgs, v.Args()...)
 		}
 
+		varArgs, err := formatVars(options)
+		if err != nil {
+			return nil, err
+		}
 		if options.SetVarsAfterVarFiles {
 			terraformArgs = append(terraformArgs, FormatTerraformArgs("-var-file", options.VarFiles)...)
-			terraformArgs = append(terraformArgs, FormatTerraformVarsAsArgs(options.Vars)...)
+			terraformArgs = append(terraformArgs, varArgs...)
 		} else {
-			terraformArgs = append(terraformArgs, FormatTerraformVarsAsArgs(options.Vars)...)
+			terraformArgs = append(terraformArgs, varArgs...)
 			terraformArgs = append(terraformArgs, FormatTerraformArgs("-var-file", options.VarFiles)...)
 		}
 	}
@@ -75,7 +92,7 @@ func FormatArgs(options *Options, args ...string) []string {
 		terraformArgs = append(terraformArgs, FormatTerraformPlanFileAsArg(commandType, options.PlanFilePath)...)
 	}
 
-	return terraformArgs
+	return terraformArgs, nil
 }
 
 // FormatTerraformPlanFileAsArg formats the out variable as a command-line arg for Terraform (e.g. of the format
@@ -97,6 +114,65 @@ func FormatTerraformVarsAsArgs(vars map[string]interface{}) []string {
 	return formatTerraformArgs(vars, "-var", true, false)
 }
 
+// FormatTerraformVarsAsVarFileArgs writes the given variables to a .tfvars.json file in the given directory (or the
+// temp dir of the OS, if empty), and returns the -var-file argument for it. Unlike with FormatTerraformVarsAsArgs, the
+// size of the variables is not bound by the limit of the OS on the length of the command line. The file is named after
+// a hash of its contents, so that running several commands with the same variables writes a single file.
+//
+// The file is only readable by the current user, as it may contain secrets, and it is replaced atomically, so that
+// parallel tests with the same variables never read a partially written file. It is NOT removed afterwards, as other
+// tests may still use it: pass a directory that is cleaned up, such as t.TempDir(), to remove it.
+func FormatTerraformVarsAsVarFileArgs(vars map[string]interface{}, dir string) ([]string, error) {
+	if len(vars) == 0 {
+		return nil, nil
+	}
+
+	contents, err := formatting.FormatVarsJSON(vars)
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(contents)
+	path := filepath.Join(dir, fmt.Sprintf("terratest-%x.tfvars.json", hash[:8]))
+	if err := writeFileAtomically(path, contents); err != nil {
+		return nil, err
+	}
+	return []string{"-var-file", path}, nil
+}
+
+// writeFileAtomically writes the given contents to a temp file next to the given path, with permissions 0600, and then
+// renames it to the path, so that readers of the path see either the old or the new file, but never a partial one.
+func writeFileAtomically(path string, contents []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(contents); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// formatVars formats the Vars of the given options as arguments: as -var arguments, or as a -var-file argument if
+// VarsAsFile is set.
+func formatVars(options *Options) ([]string, error) {
+	if options.VarsAsFile {
+		return FormatTerraformVarsAsVarFileArgs(options.Vars, options.VarsFileDir)
+	}
+	return FormatTerraformVarsAsArgs(options.Vars), nil
+}
+
 // FormatTerraformLockAsArgs formats the lock and lock-timeout variables
 // -lock, -lock-timeout
 func FormatTerraformLockAsArgs(lockCheck bool, lockTimeout string) []string {
@@ -143,8 +219,7 @@ func formatTerraformArgs(vars map[string]interface{}, prefix string, useSpaceAsS
 		if omitNil && value == nil {
 			argValue = key
 		} else {
-			hclString := toHclString(value, false)
-			argValue = fmt.Sprintf("%s=%s", key, hclString)
+			argValue = fmt.Sprintf("%s=%s", key, formatting.FormatArgValue(value))
 		}
 		if useSpaceAsSeparator {
 			args = append(args, prefix, argValue)
@@ -155,114 +230,3 @@ func formatTerraformArgs(vars map[string]interface{}, prefix string, useSpaceAsS
 
 	return args
 }
-
-// Terraform allows you to pass in command-line variables using HCL syntax (e.g. -var foo=[1,2,3]). Unfortunately,
-// while their golang hcl library can convert an HCL string to a Go type, they don't seem to offer a library to convert
-// arbitrary Go types to an HCL string. Therefore, this method is a simple implementation that correctly handles
-// ints, booleans, lists, and maps. Everything else is forced into a string using Sprintf. Hopefully, this approach is
-// good enough for the type of variables we deal with in Terratest.
-func toHclString(value interface{}, isNested bool) string {
-	// Ideally, we'd use a type switch here to identify slices and maps, but we can't do that, because Go doesn't
-	// support generics, and the type switch only matches concrete types. So we could match []interface{}, but if
-	// a user passes in []string{}, that would NOT match (the same logic applies to maps). Therefore, we have to
-	// use reflection and manually convert into []interface{} and map[string]interface{}.
-
-	if slice, isSlice := tryToConvertToGenericSlice(value); isSlice {
-		return sliceToHclString(slice)
-	} else if m, isMap := tryToConvertToGenericMap(value); isMap {
-		return mapToHclString(m)
-	} else {
-		return primitiveToHclString(value, isNested)
-	}
-}
-
-// Try to convert the given value to a generic slice. Return the slice and true if the underlying value itself was a
-// slice and an empty slice and false if it wasn't. This is necessary because Go is a shitty language that doesn't
-// have generics, nor useful utility methods built-in. For more info, see: http://stackoverflow.com/a/12754757/483528
-func tryToConvertToGenericSlice(value interface{}) ([]interface{}, bool) {
-	reflectValue := reflect.ValueOf(value)
-	if reflectValue.Kind() != reflect.Slice {
-		return []interface{}{}, false
-	}
-
-	genericSlice := make([]interface{}, reflectValue.Len())
-
-	for i := 0; i < reflectValue.Len(); i++ {
-		genericSlice[i] = reflectValue.Index(i).Interface()
-	}
-
-	return genericSlice, true
-}
-
-// Try to convert the given value to a generic map. Return the map and true if the underlying value itself was a
-// map and an empty map and false if it wasn't. This is necessary because Go is a shitty language that doesn't
-// have generics, nor useful utility methods built-in. For more info, see: http://stackoverflow.com/a/12754757/483528
-func tryToConvertToGenericMap(value interface{}) (map[string]interface{}, bool) {
-	reflectValue := reflect.ValueOf(value)
-	if reflectValue.Kind() != reflect.Map {
-		return map[string]interface{}{}, false
-	}
-
-	reflectType := reflect.TypeOf(value)
-	if reflectType.Key().Kind() != reflect.String {
-		return map[string]interface{}{}, false
-	}
-
-	genericMap := make(map[string]interface{}, reflectValue.Len())
-
-	mapKeys := reflectValue.MapKeys()
-	for _, key := range mapKeys {
-		genericMap[key.String()] = reflectValue.MapIndex(key).Interface()
-	}
-
-	return genericMap, true
-}
-
-// Convert a slice to an HCL string. See ToHclString for details.
-func sliceToHclString(slice []interface{}) string {
-	hclValues := []string{}
-
-	for _, value := range slice {
-		hclValue := toHclString(value, true)
-		hclValues = append(hclValues, hclValue)
-	}
-
-	return fmt.Sprintf("[%s]", strings.Join(hclValues, ", "))
-}
-
-// Convert a map to an HCL string. See ToHclString for details.
-func mapToHclString(m map[string]interface{}) string {
-	keyValuePairs := []string{}
-
-	for key, value := range m {
-		keyValuePair := fmt.Sprintf(`"%s" = %s`, key, toHclString(value, true))
-		keyValuePairs = append(keyValuePairs, keyValuePair)
-	}
-
-	return fmt.Sprintf("{%s}", strings.Join(keyValuePairs, ", "))
-}
-
-// Convert a primitive, such as a bool, int, or string, to an HCL string. If this isn't a primitive, force its value
-// using Sprintf. See ToHclString for details.
-func primitiveToHclString(value interface{}, isNested bool) string {
-	if value == nil {
-		return "null"
-	}
-
-	switch v := value.(type) {
-
-	case bool:
-		return strconv.FormatBool(v)
-
-	case string:
-		// If string is nested in a larger data structure (e.g. list of string, map of string), ensure value is quoted
-		if isNested {
-			return fmt.Sprintf("\"%v\"", v)
-		}
-
-		return fmt.Sprintf("%v", v)
-
-	default:
-		return fmt.Sprintf("%v", v)
-	}
-}
@@ -2,9 +2,12 @@ package terraform
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatTerraformPlanFileAsArgs(t *testing.T) {
@@ -44,6 +47,11 @@ func TestFormatTerraformVarsAsArgs(t *testing.T) {
 		{map[string]interface{}{"foo": nil}, []string{"-var", "foo=null"}},
 		{map[string]interface{}{"foo": []int{1, 2, 3}}, []string{"-var", "foo=[1, 2, 3]"}},
 		{map[string]interface{}{"foo": map[string]string{"baz": "blah"}}, []string{"-var", "foo={\"baz\" = \"blah\"}"}},
+		{map[string]interface{}{"foo": []string{"say \"hi\""}}, []string{"-var", "foo=[\"say \\\"hi\\\"\"]"}},
+		{map[string]interface{}{"foo": struct {
+			Name string `hcl:"name"`
+			Size *int   `hcl:"size,optional"`
+		}{Name: "web"}}, []string{"-var", "foo={\"name\" = \"web\"}"}},
 		{
 			map[string]interface{}{"str": "bar", "int": -1, "bool": false, "list": []string{"foo", "bar", "baz"}, "map": map[string]int{"foo": 0}},
 			[]string{"-var", "str=bar", "-var", "int=-1", "-var", "bool=false", "-var", "list=[\"foo\", \"bar\", \"baz\"]", "-var", "map={\"foo\" = 0}"},
@@ -57,6 +65,65 @@ func TestFormatTerraformVarsAsArgs(t *testing.T) {
 	}
 }
 
+func TestFormatTerraformVarsAsVarFileArgs(t *testing.T) {
+	t.Parallel()
+
+	args, err := FormatTerraformVarsAsVarFileArgs(map[string]interface{}{"foo": "bar", "list": []int{1, 2}}, t.TempDir())
+	require.NoError(t, err)
+	require.Len(t, args, 2)
+	assert.Equal(t, "-var-file", args[0])
+
+	// The file may contain secrets, so only the current user can read it
+	info, err := os.Stat(args[1])
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	var vars map[string]interface{}
+	require.NoError(t, GetAllVariablesFromVarFileE(t, args[1], &vars))
+	assert.Equal(t, map[string]interface{}{"foo": "bar", "list": []interface{}{float64(1), float64(2)}}, vars)
+
+	args, err = FormatTerraformVarsAsVarFileArgs(map[string]interface{}{}, t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, args)
+}
+
+func TestFormatArgsVarsAsFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	options := &Options{
+		Vars:        map[string]interface{}{"foo": "bar"},
+		VarFiles:    []string{"foo.tfvars"},
+		VarsAsFile:  true,
+		VarsFileDir: dir,
+	}
+	args := FormatArgs(options, "apply")
+	require.Len(t, args, 6)
+	assert.Equal(t, []string{"apply", "-var-file"}, args[:2])
+	assert.Equal(t, dir, filepath.Dir(args[2]))
+	assert.Equal(t, []string{"-var-file", "foo.tfvars", "-lock=false"}, args[3:])
+
+	// The same variables are written to the same file
+	assert.Equal(t, args, FormatArgs(options, "apply"))
+}
+
+func TestFormatArgsEVarsAsFileReturnsWriteError(t *testing.T) {
+	t.Parallel()
+
+	notADir := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(notADir, nil, 0644))
+	options := &Options{
+		Vars:        map[string]interface{}{"foo": "bar"},
+		VarsAsFile:  true,
+		VarsFileDir: notADir,
+	}
+	_, err := FormatArgsE(options, "apply")
+	require.Error(t, err)
+
+	// FormatArgs falls back to -var arguments
+	assert.Equal(t, []string{"apply", "-var", "foo=bar", "-lock=false"}, FormatArgs(options, "apply"))
+}
+
 // Some of our tests execute code that loops over a map to produce output. The problem is that the order of map
 // iteration is generally unpredictable and, to make it even more unpredictable, Go intentionally randomizes the
 // iteration order (https://blog.golang.org/go-maps-in-action#TOC_7). Therefore, the order of items in the output
@@ -0,0 +1,112 @@
+package terraform
+
+import (
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// VarGenerator returns the value to use for a required variable of a module, e.g. to satisfy its validation rules.
+type VarGenerator func(variable *ModuleVariable) interface{}
+
+// GenerateRequiredVars returns values for all the variables of the module in the given directory that have no
+// default, for use as the Vars of Options, e.g. to check that every module of a repo plans cleanly. The value of a
+// variable is generated by the VarGenerator for its name in overrides if there is one, and is a placeholder that
+// matches its type otherwise:
+//
+//   - a unique name for strings named `name` or `*_name`, e.g. terratest-a1b2c3
+//   - a random string for other strings and variables without a type
+//   - 1 for numbers, and false for bools
+//   - a single placeholder element for lists, sets and maps, and a placeholder for every required attribute of objects
+func GenerateRequiredVars(t testing.TestingT, dir string, overrides map[string]VarGenerator) map[string]interface{} {
+	vars, err := GenerateRequiredVarsE(t, dir, overrides)
+	require.NoError(t, err)
+	return vars
+}
+
+// GenerateRequiredVarsE returns values for all the variables of the module in the given directory that have no
+// default, for use as the Vars of Options. See GenerateRequiredVars for how the values are generated.
+func GenerateRequiredVarsE(t testing.TestingT, dir string, overrides map[string]VarGenerator) (map[string]interface{}, error) {
+	module, err := InspectModuleE(t, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]interface{}{}
+	for name, variable := range module.Variables {
+		if !variable.Required {
+			continue
+		}
+		if generator, hasOverride := overrides[name]; hasOverride {
+			vars[name] = generator(variable)
+			continue
+		}
+
+		varType, err := parseVariableType(variable)
+		if err != nil {
+			return nil, err
+		}
+		vars[name] = placeholderValue(name, varType)
+	}
+	return vars, nil
+}
+
+// parseVariableType returns the type constraint of the given variable, or cty.DynamicPseudoType if it has none.
+func parseVariableType(variable *ModuleVariable) (cty.Type, error) {
+	if variable.Type == "" {
+		return cty.DynamicPseudoType, nil
+	}
+
+	// Report diagnostics relative to the variable block rather than to the start of its file
+	pos := hcl.Pos{Line: variable.Pos.Line, Column: 1, Byte: 0}
+	expr, diags := hclsyntax.ParseExpression([]byte(variable.Type), variable.Pos.Filename, pos)
+	if diags.HasErrors() {
+		return cty.NilType, diags
+	}
+	// Unlike TypeConstraint, this accepts optional attributes with a default, e.g. optional(number, 3)
+	varType, _, diags := typeexpr.TypeConstraintWithDefaults(expr)
+	if diags.HasErrors() {
+		return cty.NilType, diags
+	}
+	return varType, nil
+}
+
+// placeholderValue returns a value of the given type, for the variable or attribute with the given name.
+func placeholderValue(name string, varType cty.Type) interface{} {
+	switch {
+	case varType == cty.Number:
+		return 1
+	case varType == cty.Bool:
+		return false
+	case varType.IsListType() || varType.IsSetType():
+		return []interface{}{placeholderValue(name, varType.ElementType())}
+	case varType.IsMapType():
+		return map[string]interface{}{"key": placeholderValue(name, varType.ElementType())}
+	case varType.IsTupleType():
+		values := []interface{}{}
+		for _, elementType := range varType.TupleElementTypes() {
+			values = append(values, placeholderValue(name, elementType))
+		}
+		return values
+	case varType.IsObjectType():
+		values := map[string]interface{}{}
+		for attributeName, attributeType := range varType.AttributeTypes() {
+			if !varType.AttributeOptional(attributeName) {
+				values[attributeName] = placeholderValue(attributeName, attributeType)
+			}
+		}
+		return values
+	case name == "name" || strings.HasSuffix(name, "_name"):
+		// Names often need to be unique, and are restricted to lower case letters, digits and hyphens
+		return "terratest-" + strings.ToLower(random.UniqueId())
+	default:
+		// Strings, and variables of any type, as Terraform converts strings to most other types
+		return strings.ToLower(random.UniqueId())
+	}
+}
@@ -0,0 +1,68 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRequiredVars(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "variables.tf"), []byte(`
+variable "name" { type = string }
+variable "bucket_name" {}
+variable "region" { type = string }
+variable "count_per_zone" { type = number }
+variable "enabled" { type = bool }
+variable "zones" { type = list(string) }
+variable "tags" { type = map(number) }
+variable "config" {
+  type = object({
+    size     = number
+    comment  = optional(string)
+    replicas = optional(number, 3)
+  })
+}
+variable "password" { type = string }
+variable "optional" {
+  type    = string
+  default = "set"
+}
+`), 0644))
+
+	vars := GenerateRequiredVars(t, dir, map[string]VarGenerator{
+		"password": func(variable *ModuleVariable) interface{} { return "correct-horse-battery-staple" },
+	})
+
+	assert.Len(t, vars, 9)
+	assert.Regexp(t, `^terratest-[a-z0-9]{6}$`, vars["name"])
+	assert.Regexp(t, `^terratest-[a-z0-9]{6}$`, vars["bucket_name"])
+	assert.Regexp(t, `^[a-z0-9]{6}$`, vars["region"])
+	assert.Equal(t, 1, vars["count_per_zone"])
+	assert.Equal(t, false, vars["enabled"])
+	assert.Len(t, vars["zones"], 1)
+	assert.Equal(t, map[string]interface{}{"key": 1}, vars["tags"])
+	assert.Equal(t, map[string]interface{}{"size": 1}, vars["config"])
+	assert.Equal(t, "correct-horse-battery-staple", vars["password"])
+	assert.NotContains(t, vars, "optional")
+}
+
+func TestGenerateRequiredVarsReportsInvalidTypeAtVariable(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "variables.tf"), []byte(`
+variable "name" { type = string }
+
+variable "broken" { type = list(string, number) }
+`), 0644))
+
+	_, err := GenerateRequiredVarsE(t, dir, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "variables.tf:4,")
+}
@@ -0,0 +1,524 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// Module is the static view of a Terraform module: what it declares, as read from its .tf and .tf.json files without
+// running Terraform. Use it to lint modules, e.g. to check that every variable has a description or that every module
+// call pins a version.
+type Module struct {
+	Path              string
+	Variables         map[string]*ModuleVariable
+	Outputs           map[string]*ModuleOutput
+	ManagedResources  map[string]*ModuleResource // Keyed by address, e.g. aws_instance.web
+	DataResources     map[string]*ModuleResource // Keyed by address, e.g. data.aws_ami.ubuntu
+	ModuleCalls       map[string]*ModuleCall
+	RequiredCore      []string // The required_version constraints of all terraform blocks
+	RequiredProviders map[string]*ProviderRequirement
+}
+
+// ModuleVariable is a variable block of a module.
+type ModuleVariable struct {
+	Name        string
+	Type        string // The type constraint as written, e.g. list(string), or empty if there is none
+	Description string
+	Default     interface{} // The default value, converted as with GetAllVariablesFromVarFile
+	Required    bool        // True if the variable has no default
+	Sensitive   bool
+	Nullable    bool
+	Pos         SourcePos
+}
+
+// ModuleOutput is an output block of a module.
+type ModuleOutput struct {
+	Name        string
+	Description string
+	Sensitive   bool
+	Pos         SourcePos
+}
+
+// ModuleResource is a resource or data block of a module.
+type ModuleResource struct {
+	Mode     string // managed or data
+	Type     string
+	Name     string
+	Provider ProviderRef
+	Pos      SourcePos
+}
+
+// ProviderRef references a provider configuration, e.g. aws.west.
+type ProviderRef struct {
+	Name  string
+	Alias string
+}
+
+// ModuleCall is a module block of a module.
+type ModuleCall struct {
+	Name    string
+	Source  string
+	Version string // The version constraint, or empty if the call pins none
+	Pos     SourcePos
+}
+
+// ProviderRequirement is an entry of the required_providers block of a module, or a provider that resources of the
+// module use without requiring it, in which case Source and VersionConstraints are empty.
+type ProviderRequirement struct {
+	Source               string
+	VersionConstraints   []string
+	ConfigurationAliases []ProviderRef
+}
+
+// SourcePos is the location of a block in the files of a module.
+type SourcePos struct {
+	Filename string
+	Line     int
+}
+
+var moduleFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "terraform"},
+		{Type: "variable", LabelNames: []string{"name"}},
+		{Type: "output", LabelNames: []string{"name"}},
+		{Type: "resource", LabelNames: []string{"type", "name"}},
+		{Type: "data", LabelNames: []string{"type", "name"}},
+		{Type: "module", LabelNames: []string{"name"}},
+		{Type: "provider", LabelNames: []string{"name"}},
+		{Type: "locals"},
+		{Type: "moved"},
+		{Type: "import"},
+		{Type: "removed"},
+		{Type: "check", LabelNames: []string{"name"}},
+	},
+}
+
+var terraformBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "required_version"}},
+	Blocks:     []hcl.BlockHeaderSchema{{Type: "required_providers"}},
+}
+
+// InspectModule reads the Terraform module in the given directory and returns what it declares: variables, outputs,
+// resources, data sources, module calls and provider requirements. This does not run Terraform, so it neither
+// downloads providers and modules nor needs credentials.
+func InspectModule(t testing.TestingT, dir string) *Module {
+	module, err := InspectModuleE(t, dir)
+	require.NoError(t, err)
+	return module
+}
+
+// InspectModuleE reads the Terraform module in the given directory and returns what it declares: variables, outputs,
+// resources, data sources, module calls and provider requirements. This does not run Terraform, so it neither
+// downloads providers and modules nor needs credentials. Returns an error if the directory can not be read or a file
+// of the module is not valid HCL.
+func InspectModuleE(t testing.TestingT, dir string) (*Module, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	module := &Module{
+		Path:              dir,
+		Variables:         map[string]*ModuleVariable{},
+		Outputs:           map[string]*ModuleOutput{},
+		ManagedResources:  map[string]*ModuleResource{},
+		DataResources:     map[string]*ModuleResource{},
+		ModuleCalls:       map[string]*ModuleCall{},
+		RequiredCore:      []string{},
+		RequiredProviders: map[string]*ProviderRequirement{},
+	}
+
+	parser := hclparse.NewParser()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json")) {
+			continue
+		}
+
+		var file *hcl.File
+		var diags hcl.Diagnostics
+		if strings.HasSuffix(name, ".json") {
+			file, diags = parser.ParseJSONFile(filepath.Join(dir, name))
+		} else {
+			file, diags = parser.ParseHCLFile(filepath.Join(dir, name))
+		}
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		if err := module.addFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	module.addImpliedProviders()
+	return module, nil
+}
+
+// addFile adds the blocks declared in the given file to the module.
+func (module *Module) addFile(file *hcl.File) error {
+	content, _, diags := file.Body.PartialContent(moduleFileSchema)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	for _, block := range content.Blocks {
+		var err error
+		switch block.Type {
+		case "terraform":
+			err = module.addTerraformBlock(block)
+		case "variable":
+			err = module.addVariable(file, block)
+		case "output":
+			err = module.addOutput(block)
+		case "resource", "data":
+			err = module.addResource(block)
+		case "module":
+			err = module.addModuleCall(block)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (module *Module) addTerraformBlock(block *hcl.Block) error {
+	content, _, diags := block.Body.PartialContent(terraformBlockSchema)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if attribute, hasVersion := content.Attributes["required_version"]; hasVersion {
+		version, err := stringAttribute(attribute)
+		if err != nil {
+			return err
+		}
+		module.RequiredCore = append(module.RequiredCore, version)
+	}
+
+	for _, requiredProviders := range content.Blocks {
+		attributes, diags := requiredProviders.Body.JustAttributes()
+		if diags.HasErrors() {
+			return diags
+		}
+		for name, attribute := range attributes {
+			requirement, err := parseProviderRequirement(attribute)
+			if err != nil {
+				return err
+			}
+			module.RequiredProviders[name] = requirement
+		}
+	}
+	return nil
+}
+
+// parseProviderRequirement parses an entry of a required_providers block, which is either an object such as
+// `{ source = "hashicorp/aws", version = "~> 5.0" }`, or just a version constraint in the legacy syntax.
+func parseProviderRequirement(attribute *hcl.Attribute) (*ProviderRequirement, error) {
+	requirement := &ProviderRequirement{VersionConstraints: []string{}, ConfigurationAliases: []ProviderRef{}}
+
+	pairs, diags := hcl.ExprMap(attribute.Expr)
+	if diags.HasErrors() {
+		version, err := stringAttribute(attribute)
+		if err != nil {
+			return nil, err
+		}
+		requirement.VersionConstraints = append(requirement.VersionConstraints, version)
+		return requirement, nil
+	}
+
+	for _, pair := range pairs {
+		key := hcl.ExprAsKeyword(pair.Key)
+		if key == "" {
+			keyValue, diags := pair.Key.Value(nil)
+			if diags.HasErrors() || keyValue.Type() != cty.String {
+				continue
+			}
+			key = keyValue.AsString()
+		}
+
+		switch key {
+		case "source", "version":
+			value, diags := pair.Value.Value(nil)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			if value.IsNull() || value.Type() != cty.String {
+				continue
+			}
+			if key == "source" {
+				requirement.Source = value.AsString()
+			} else {
+				requirement.VersionConstraints = append(requirement.VersionConstraints, value.AsString())
+			}
+		case "configuration_aliases":
+			aliases, diags := hcl.ExprList(pair.Value)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			for _, alias := range aliases {
+				ref, diags := parseProviderRef(alias)
+				if diags.HasErrors() {
+					return nil, diags
+				}
+				requirement.ConfigurationAliases = append(requirement.ConfigurationAliases, ref)
+			}
+		}
+	}
+	return requirement, nil
+}
+
+func (module *Module) addVariable(file *hcl.File, block *hcl.Block) error {
+	attributes, diags := block.Body.JustAttributes()
+	if diags.HasErrors() {
+		// Variables may contain validation blocks, which JustAttributes does not accept
+		attributes, diags = partialAttributes(block.Body, "type", "description", "default", "sensitive", "nullable")
+		if diags.HasErrors() {
+			return diags
+		}
+	}
+
+	variable := &ModuleVariable{
+		Name:     block.Labels[0],
+		Required: true,
+		Nullable: true,
+		Pos:      sourcePos(block.DefRange),
+	}
+	if attribute, hasType := attributes["type"]; hasType {
+		variable.Type = typeExpression(file, attribute.Expr)
+	}
+	if attribute, hasDescription := attributes["description"]; hasDescription {
+		description, err := stringAttribute(attribute)
+		if err != nil {
+			return err
+		}
+		variable.Description = description
+	}
+	if attribute, hasDefault := attributes["default"]; hasDefault {
+		value, diags := attribute.Expr.Value(nil)
+		if diags.HasErrors() {
+			return diags
+		}
+		defaultValue, err := ctyValueToGo(value)
+		if err != nil {
+			return err
+		}
+		variable.Default = defaultValue
+		variable.Required = false
+	}
+	if attribute, hasSensitive := attributes["sensitive"]; hasSensitive {
+		sensitive, err := boolAttribute(attribute)
+		if err != nil {
+			return err
+		}
+		variable.Sensitive = sensitive
+	}
+	if attribute, hasNullable := attributes["nullable"]; hasNullable {
+		nullable, err := boolAttribute(attribute)
+		if err != nil {
+			return err
+		}
+		variable.Nullable = nullable
+	}
+
+	module.Variables[variable.Name] = variable
+	return nil
+}
+
+func (module *Module) addOutput(block *hcl.Block) error {
+	attributes, diags := partialAttributes(block.Body, "description", "sensitive")
+	if diags.HasErrors() {
+		return diags
+	}
+
+	output := &ModuleOutput{Name: block.Labels[0], Pos: sourcePos(block.DefRange)}
+	if attribute, hasDescription := attributes["description"]; hasDescription {
+		description, err := stringAttribute(attribute)
+		if err != nil {
+			return err
+		}
+		output.Description = description
+	}
+	if attribute, hasSensitive := attributes["sensitive"]; hasSensitive {
+		sensitive, err := boolAttribute(attribute)
+		if err != nil {
+			return err
+		}
+		output.Sensitive = sensitive
+	}
+
+	module.Outputs[output.Name] = output
+	return nil
+}
+
+func (module *Module) addResource(block *hcl.Block) error {
+	resource := &ModuleResource{
+		Mode: "managed",
+		Type: block.Labels[0],
+		Name: block.Labels[1],
+		Pos:  sourcePos(block.DefRange),
+		// By default, resources use the default configuration of the provider named by the prefix of their type
+		Provider: ProviderRef{Name: strings.SplitN(block.Labels[0], "_", 2)[0]},
+	}
+
+	attributes, diags := partialAttributes(block.Body, "provider")
+	if diags.HasErrors() {
+		return diags
+	}
+	if attribute, hasProvider := attributes["provider"]; hasProvider {
+		ref, diags := parseProviderRef(attribute.Expr)
+		if diags.HasErrors() {
+			return diags
+		}
+		resource.Provider = ref
+	}
+
+	address := resource.Type + "." + resource.Name
+	if block.Type == "data" {
+		resource.Mode = "data"
+		module.DataResources["data."+address] = resource
+	} else {
+		module.ManagedResources[address] = resource
+	}
+	return nil
+}
+
+func (module *Module) addModuleCall(block *hcl.Block) error {
+	attributes, diags := partialAttributes(block.Body, "source", "version")
+	if diags.HasErrors() {
+		return diags
+	}
+
+	call := &ModuleCall{Name: block.Labels[0], Pos: sourcePos(block.DefRange)}
+	if attribute, hasSource := attributes["source"]; hasSource {
+		source, err := stringAttribute(attribute)
+		if err != nil {
+			return err
+		}
+		call.Source = source
+	}
+	if attribute, hasVersion := attributes["version"]; hasVersion {
+		version, err := stringAttribute(attribute)
+		if err != nil {
+			return err
+		}
+		call.Version = version
+	}
+
+	module.ModuleCalls[call.Name] = call
+	return nil
+}
+
+// addImpliedProviders adds the providers that resources use without the module requiring them, as Terraform does.
+func (module *Module) addImpliedProviders() {
+	for _, resources := range []map[string]*ModuleResource{module.ManagedResources, module.DataResources} {
+		for _, resource := range resources {
+			if _, isRequired := module.RequiredProviders[resource.Provider.Name]; !isRequired {
+				module.RequiredProviders[resource.Provider.Name] = &ProviderRequirement{
+					VersionConstraints:   []string{},
+					ConfigurationAliases: []ProviderRef{},
+				}
+			}
+		}
+	}
+	sort.Strings(module.RequiredCore)
+}
+
+// partialAttributes returns the given attributes of the body, ignoring all other attributes and blocks.
+func partialAttributes(body hcl.Body, names ...string) (hcl.Attributes, hcl.Diagnostics) {
+	schema := &hcl.BodySchema{}
+	for _, name := range names {
+		schema.Attributes = append(schema.Attributes, hcl.AttributeSchema{Name: name})
+	}
+	content, _, diags := body.PartialContent(schema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return content.Attributes, nil
+}
+
+// parseProviderRef parses a reference to a provider configuration, such as aws or aws.west.
+func parseProviderRef(expr hcl.Expression) (ProviderRef, hcl.Diagnostics) {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() {
+		return ProviderRef{}, diags
+	}
+
+	ref := ProviderRef{Name: traversal.RootName()}
+	if len(traversal) > 1 {
+		if attribute, isAttribute := traversal[1].(hcl.TraverseAttr); isAttribute {
+			ref.Alias = attribute.Name
+		}
+	}
+	return ref, nil
+}
+
+// typeExpression returns the type constraint of a variable as written. Type constraints are expressions in HCL files,
+// and strings in JSON files.
+func typeExpression(file *hcl.File, expr hcl.Expression) string {
+	if _, isNative := expr.(hclsyntax.Expression); isNative {
+		return string(expr.Range().SliceBytes(file.Bytes))
+	}
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || value.IsNull() || value.Type() != cty.String {
+		return ""
+	}
+	return value.AsString()
+}
+
+func stringAttribute(attribute *hcl.Attribute) (string, error) {
+	value, diags := attribute.Expr.Value(nil)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	if value.IsNull() {
+		return "", nil
+	}
+	if value.Type() != cty.String {
+		return "", UnexpectedAttributeType{Name: attribute.Name, ExpectedType: "string", ActualType: value.Type().FriendlyName(), Pos: sourcePos(attribute.Range)}
+	}
+	return value.AsString(), nil
+}
+
+func boolAttribute(attribute *hcl.Attribute) (bool, error) {
+	value, diags := attribute.Expr.Value(nil)
+	if diags.HasErrors() {
+		return false, diags
+	}
+	if value.IsNull() {
+		return false, nil
+	}
+	if value.Type() != cty.Bool {
+		return false, UnexpectedAttributeType{Name: attribute.Name, ExpectedType: "bool", ActualType: value.Type().FriendlyName(), Pos: sourcePos(attribute.Range)}
+	}
+	return value.True(), nil
+}
+
+// ctyValueToGo converts the given value to the Go types encoding/json unmarshals to, e.g. map[string]interface{}.
+func ctyValueToGo(value cty.Value) (interface{}, error) {
+	if value.IsNull() {
+		return nil, nil
+	}
+	jsonBytes, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(jsonBytes, &out)
+	return out, err
+}
+
+func sourcePos(r hcl.Range) SourcePos {
+	return SourcePos{Filename: r.Filename, Line: r.Start.Line}
+}
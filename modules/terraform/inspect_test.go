@@ -0,0 +1,83 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectModule(t *testing.T) {
+	t.Parallel()
+
+	module := InspectModule(t, "../../test/fixtures/terraform-inspect")
+
+	require.Len(t, module.Variables, 3)
+	name := module.Variables["name"]
+	assert.Equal(t, "string", name.Type)
+	assert.Equal(t, "The name of the instance", name.Description)
+	assert.True(t, name.Required)
+	assert.Nil(t, name.Default)
+	assert.Equal(t, "../../test/fixtures/terraform-inspect/variables.tf", name.Pos.Filename)
+	assert.Equal(t, 1, name.Pos.Line)
+
+	tags := module.Variables["tags"]
+	assert.Equal(t, "map(string)", tags.Type)
+	assert.False(t, tags.Required)
+	assert.Equal(t, map[string]interface{}{"Team": "platform"}, tags.Default)
+
+	password := module.Variables["password"]
+	assert.Empty(t, password.Description)
+	assert.False(t, password.Required)
+	assert.True(t, password.Sensitive)
+	assert.False(t, password.Nullable)
+
+	require.Len(t, module.Outputs, 2)
+	assert.Equal(t, "The ID of the instance", module.Outputs["instance_id"].Description)
+	assert.True(t, module.Outputs["password"].Sensitive)
+
+	require.Len(t, module.ManagedResources, 2)
+	assert.Equal(t, ProviderRef{Name: "aws", Alias: "west"}, module.ManagedResources["aws_instance.web"].Provider)
+	assert.Equal(t, ProviderRef{Name: "random"}, module.ManagedResources["random_id.suffix"].Provider)
+	require.Len(t, module.DataResources, 1)
+	assert.Equal(t, "data", module.DataResources["data.aws_ami.ubuntu"].Mode)
+
+	assert.Equal(t, map[string]*ModuleCall{
+		"vpc":   {Name: "vpc", Source: "terraform-aws-modules/vpc/aws", Version: "5.1.0", Pos: SourcePos{Filename: "../../test/fixtures/terraform-inspect/main.tf", Line: 17}},
+		"local": {Name: "local", Source: "./modules/local", Pos: SourcePos{Filename: "../../test/fixtures/terraform-inspect/main.tf", Line: 22}},
+	}, module.ModuleCalls)
+
+	assert.Equal(t, []string{">= 1.0"}, module.RequiredCore)
+	assert.Equal(t, map[string]*ProviderRequirement{
+		"aws":    {Source: "hashicorp/aws", VersionConstraints: []string{"~> 5.0"}, ConfigurationAliases: []ProviderRef{{Name: "aws", Alias: "west"}}},
+		"null":   {VersionConstraints: []string{"~> 3.0"}, ConfigurationAliases: []ProviderRef{}},
+		"random": {VersionConstraints: []string{}, ConfigurationAliases: []ProviderRef{}},
+	}, module.RequiredProviders)
+}
+
+func TestInspectModuleEInvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"syntax error", `variable "name" {`},
+		{"description not a string", "variable \"name\" {\n  description = 1\n}\n"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(testCase.content), 0644))
+
+			_, err := InspectModuleE(t, dir)
+			require.Error(t, err)
+		})
+	}
+}
@@ -51,6 +51,20 @@ type Options struct {
 	// }
 	Vars map[string]interface{}
 
+	// If true, Vars are written to a .tfvars.json file that is passed with -var-file, rather than as -var arguments.
+	// This avoids the limit of the OS on the length of the command line for huge inputs, and unlike with -var arguments,
+	// `nil` values are passed as `null`.
+	VarsAsFile bool
+
+	// The directory to write the var file to if VarsAsFile is true, e.g. a directory that CI keeps as an artifact, so
+	// that the run can be reproduced. Defaults to the temp dir of the OS. The file is not removed after the run, so use
+	// e.g. t.TempDir() if Vars contains secrets.
+	VarsFileDir string
+
+	// The names of the Vars, MixedVars, BackendConfig and EnvVars (either by the name itself or as TF_VAR_<name>) whose
+	// values are secrets. Their values are redacted from all log output (see logger.RegisterSecret).
+	SensitiveVars []string
+
 	VarFiles                 []string               // The var file paths to pass to Terraform commands using -var-file option.
 	MixedVars                []Var                  // Mix of `-var` and `-var-file` in arbritrary order, use `VarInline()` `VarFile()` to set the value.
 	Targets                  []string               // The target resources to pass to the terraform command with -target
@@ -36,7 +36,11 @@ func Plan(t testing.TestingT, options *Options) string {
 
 // PlanE runs terraform plan with the given options and returns stdout/stderr.
 func PlanE(t testing.TestingT, options *Options) (string, error) {
-	return RunTerraformCommandE(t, options, FormatArgs(options, prepend(options.ExtraArgs.Plan, "plan", "-input=false", "-lock=false")...)...)
+	args, err := FormatArgsE(options, prepend(options.ExtraArgs.Plan, "plan", "-input=false", "-lock=false")...)
+	if err != nil {
+		return "", err
+	}
+	return RunTerraformCommandE(t, options, args...)
 }
 
 // InitAndPlanAndShow runs terraform init, then terraform plan, and then terraform show with the given options, and
@@ -122,7 +126,11 @@ func PlanExitCode(t testing.TestingT, options *Options) int {
 
 // PlanExitCodeE runs terraform plan with the given options and returns the detailed exitcode.
 func PlanExitCodeE(t testing.TestingT, options *Options) (int, error) {
-	return GetExitCodeForTerraformCommandE(t, options, FormatArgs(options, prepend(options.ExtraArgs.Plan, "plan", "-input=false", "-detailed-exitcode")...)...)
+	args, err := FormatArgsE(options, prepend(options.ExtraArgs.Plan, "plan", "-input=false", "-detailed-exitcode")...)
+	if err != nil {
+		return DefaultErrorExitCode, err
+	}
+	return GetExitCodeForTerraformCommandE(t, options, args...)
 }
 
 // Custom errors
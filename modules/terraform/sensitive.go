@@ -0,0 +1,54 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/terratest/internal/lib/formatting"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// registerSensitiveVars registers the values of the vars, backend config and environment variables named in
+// options.SensitiveVars as secrets, so that they are redacted from the logs (see logger.RegisterSecret).
+func registerSensitiveVars(options *Options) {
+	for _, name := range options.SensitiveVars {
+		if value, hasVar := options.Vars[name]; hasVar {
+			registerSecretValue(value)
+		}
+		for _, mixedVar := range options.MixedVars {
+			if inline, isInline := mixedVar.(varInline); isInline && inline.name == name {
+				registerSecretValue(inline.value)
+			}
+		}
+		if value, hasConfig := options.BackendConfig[name]; hasConfig {
+			registerSecretValue(value)
+		}
+		for _, envVarName := range []string{name, "TF_VAR_" + name} {
+			if value, hasEnvVar := options.EnvVars[envVarName]; hasEnvVar {
+				logger.RegisterSecret(value)
+			}
+		}
+	}
+}
+
+// registerSecretValue registers the given value as a secret. The elements of lists, maps and structs are registered one
+// by one, as they end up in the logs within the HCL representation of the whole value.
+func registerSecretValue(value interface{}) {
+	ctyValue, err := formatting.ToCtyValue(value)
+	if err != nil {
+		logger.RegisterSecret(fmt.Sprint(value))
+		return
+	}
+	cty.Walk(ctyValue, func(_ cty.Path, element cty.Value) (bool, error) {
+		if !element.IsKnown() || element.IsNull() {
+			return false, nil
+		}
+		switch element.Type() {
+		case cty.String:
+			logger.RegisterSecret(element.AsString())
+		case cty.Number:
+			logger.RegisterSecret(element.AsBigFloat().Text('f', -1))
+		}
+		return true, nil
+	})
+}
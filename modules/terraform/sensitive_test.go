@@ -0,0 +1,30 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterSensitiveVars(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		Vars: map[string]interface{}{
+			"password": "sensitive-var-password",
+			"tokens":   []string{"sensitive-var-token-1", "sensitive-var-token-2"},
+			"public":   "sensitive-var-public",
+		},
+		MixedVars:     []Var{VarInline("api_key", map[string]interface{}{"key": "sensitive-var-api-key"})},
+		BackendConfig: map[string]interface{}{"access_key": "sensitive-var-access-key"},
+		EnvVars:       map[string]string{"TF_VAR_db_password": "sensitive-var-db-password"},
+		SensitiveVars: []string{"password", "tokens", "api_key", "access_key", "db_password"},
+	}
+	registerSensitiveVars(options)
+
+	assert.Equal(t,
+		"[REDACTED] [REDACTED] [REDACTED] [REDACTED] [REDACTED] [REDACTED] sensitive-var-public",
+		logger.Redact("sensitive-var-password sensitive-var-token-1 sensitive-var-token-2 sensitive-var-api-key sensitive-var-access-key sensitive-var-db-password sensitive-var-public"),
+	)
+}
@@ -14,7 +14,11 @@ func Validate(t testing.TestingT, options *Options) string {
 
 // ValidateE calls terraform validate and returns stdout/stderr.
 func ValidateE(t testing.TestingT, options *Options) (string, error) {
-	return RunTerraformCommandE(t, options, FormatArgs(options, prepend(options.ExtraArgs.Validate, "validate")...)...)
+	args, err := FormatArgsE(options, prepend(options.ExtraArgs.Validate, "validate")...)
+	if err != nil {
+		return "", err
+	}
+	return RunTerraformCommandE(t, options, args...)
 }
 
 // InitAndValidate runs terraform init and validate with the given options and returns stdout/stderr from the validate command.
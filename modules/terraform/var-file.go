@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/gruntwork-io/terratest/internal/lib/formatting"
 	"github.com/gruntwork-io/terratest/modules/testing"
 
 	"github.com/hashicorp/hcl/v2"
@@ -116,6 +117,37 @@ func GetVariableAsListFromVarFileE(t testing.TestingT, fileName string, key stri
 	return resultArray, nil
 }
 
+// WriteVarFile writes the given variables to a var file at the given path, which can be passed to Terraform with
+// VarFiles or read back with GetAllVariablesFromVarFile. The file is written as JSON if the path ends with .json (e.g.
+// terraform.tfvars.json), and as HCL otherwise. Besides primitives, lists and maps, the values may be structs, pointers
+// and durations, which are converted as for Vars.
+func WriteVarFile(t testing.TestingT, path string, vars map[string]interface{}) {
+	err := WriteVarFileE(t, path, vars)
+	require.NoError(t, err)
+}
+
+// WriteVarFileE writes the given variables to a var file at the given path, which can be passed to Terraform with
+// VarFiles or read back with GetAllVariablesFromVarFile. The file is written as JSON if the path ends with .json (e.g.
+// terraform.tfvars.json), and as HCL otherwise. Returns an error if a value can not be converted to HCL or the file
+// can not be written.
+func WriteVarFileE(t testing.TestingT, path string, vars map[string]interface{}) error {
+	return writeVarFile(path, vars)
+}
+
+func writeVarFile(path string, vars map[string]interface{}) error {
+	var contents []byte
+	var err error
+	if strings.HasSuffix(path, ".json") {
+		contents, err = formatting.FormatVarsJSON(vars)
+	} else {
+		contents, err = formatting.FormatHclFile(vars)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents, 0644)
+}
+
 // GetAllVariablesFromVarFile Parses all data from a provided input file found ind in VarFile and stores the result in
 // the value pointed to by out.
 func GetAllVariablesFromVarFile(t testing.TestingT, fileName string, out interface{}) {
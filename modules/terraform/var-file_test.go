@@ -3,7 +3,9 @@ package terraform
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/stretchr/testify/require"
@@ -473,3 +475,44 @@ func WriteFile(t *testing.T, fileName string, bytes []byte) {
 
 	require.NoError(t, err)
 }
+
+func TestWriteVarFileRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	type tag struct {
+		Key   string `hcl:"key"`
+		Value string `hcl:"value,optional"`
+	}
+	vars := map[string]interface{}{
+		"aws_region": "us-east-2",
+		"user_data":  "#!/bin/bash\necho \"${HOSTNAME}\"\n",
+		"count":      3,
+		"enabled":    true,
+		"zones":      []string{"a", "b"},
+		"tags":       []tag{{Key: "env", Value: "test"}},
+		"settings":   map[string]interface{}{"timeout": 90 * time.Second, "nothing": nil},
+	}
+	expected := map[string]interface{}{
+		"aws_region": "us-east-2",
+		"user_data":  "#!/bin/bash\necho \"${HOSTNAME}\"\n",
+		"count":      float64(3),
+		"enabled":    true,
+		"zones":      []interface{}{"a", "b"},
+		"tags":       []interface{}{map[string]interface{}{"key": "env", "value": "test"}},
+		"settings":   map[string]interface{}{"timeout": "1m30s", "nothing": nil},
+	}
+
+	for _, fileName := range []string{"terraform.tfvars", "terraform.tfvars.json"} {
+		fileName := fileName
+		t.Run(fileName, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), fileName)
+			WriteVarFile(t, path, vars)
+
+			var variables map[string]interface{}
+			GetAllVariablesFromVarFile(t, path, &variables)
+			require.Equal(t, expected, variables)
+		})
+	}
+}
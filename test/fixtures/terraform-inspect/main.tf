@@ -0,0 +1,24 @@
+data "aws_ami" "ubuntu" {
+  most_recent = true
+  owners      = ["099720109477"]
+}
+
+resource "aws_instance" "web" {
+  provider      = aws.west
+  ami           = data.aws_ami.ubuntu.id
+  instance_type = "t3.micro"
+  tags          = merge(var.tags, { Name = var.name })
+}
+
+resource "random_id" "suffix" {
+  byte_length = 4
+}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.1.0"
+}
+
+module "local" {
+  source = "./modules/local"
+}
@@ -0,0 +1,24 @@
+variable "name" {
+  description = "The name of the instance"
+  type        = string
+}
+
+variable "tags" {
+  description = "The tags of the instance"
+  type        = map(string)
+  default = {
+    Team = "platform"
+  }
+}
+
+variable "password" {
+  type      = string
+  sensitive = true
+  nullable  = false
+  default   = null
+
+  validation {
+    condition     = var.password == null || length(var.password) > 8
+    error_message = "The password must be longer than 8 characters."
+  }
+}